@@ -0,0 +1,200 @@
+package common_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	. "github.com/xitongsys/parquet-go/types"
+)
+
+//The tests of the comparisons of the binary values of types, which imports common
+
+func TestCmpIntBinary(t *testing.T) {
+	cases := []struct {
+		numa int32
+		numb int32
+	}{
+		{-1, 0},
+		{1, 2},
+		{1, 1},
+		{1, 0},
+		{0, 0},
+		{-1, -2},
+		{-2, -1},
+		{-1, 1},
+		{2147483647, 2147483647},
+		{-2147483648, -2147483647},
+		{-2147483648, 2147483647},
+	}
+
+	for _, c := range cases {
+		abuf, bbuf := new(bytes.Buffer), new(bytes.Buffer)
+		binary.Write(abuf, binary.LittleEndian, c.numa)
+		binary.Write(bbuf, binary.LittleEndian, c.numb)
+		as, bs := string(abuf.Bytes()), string(bbuf.Bytes())
+		if (c.numa < c.numb) != (common.CmpIntBinary(as, bs, "LittleEndian", true)) {
+			t.Errorf("CmpIntBinary error, %v-%v", c.numa, c.numb)
+		}
+	}
+
+	cases2 := []struct {
+		numa string
+		numb string
+	}{
+		{"-1", "0"},
+		{"1", "2"},
+		{"1", "1"},
+		{"1", "0"},
+		{"0", "0"},
+		{"-123", "-2"},
+		{"-2", "-1"},
+		{"-1344", "123"},
+		{"2147483647", "2147483647"},
+		{"-2147483648", "-2147483647"},
+		{"-2147483648", "2147483647"},
+	}
+
+	for _, c := range cases2 {
+		as := StrIntToBinary(c.numa, "LittleEndian", 0, true)
+		bs := StrIntToBinary(c.numb, "LittleEndian", 0, true)
+		an, bn := 0, 0
+		fmt.Sscanf(c.numa, "%d", &an)
+		fmt.Sscanf(c.numb, "%d", &bn)
+		if (an < bn) != (common.CmpIntBinary(as, bs, "LittleEndian", true)) {
+			t.Errorf("CmpIntBinary error, %v-%v", c.numa, c.numb)
+		}
+	}
+
+	cases3 := []struct {
+		numa string
+		numb string
+	}{
+		{"1", "2"},
+		{"1", "1"},
+		{"1", "0"},
+		{"0", "0"},
+		{"123", "2"},
+		{"1344", "123"},
+		{"2147483647", "2147483647"},
+		{"2147483648", "2147483647"},
+	}
+
+	for _, c := range cases3 {
+		as := StrIntToBinary(c.numa, "LittleEndian", 0, false)
+		bs := StrIntToBinary(c.numb, "LittleEndian", 0, false)
+		an, bn := uint64(0), uint64(0)
+		fmt.Sscanf(c.numa, "%d", &an)
+		fmt.Sscanf(c.numb, "%d", &bn)
+		if (an < bn) != (common.CmpIntBinary(as, bs, "LittleEndian", false)) {
+			t.Errorf("CmpIntBinary error, %v-%v", c.numa, c.numb)
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	cases := []struct {
+		str    string
+		numa   interface{}
+		numb   interface{}
+		PT     *parquet.Type
+		CT     *parquet.ConvertedType
+		expect bool
+	}{
+		{"bool 1", bool(false), bool(true), parquet.TypePtr(parquet.Type_BOOLEAN), nil, true},
+		{"bool 2", bool(true), bool(false), parquet.TypePtr(parquet.Type_BOOLEAN), nil, false},
+		{"bool 3", bool(true), bool(true), parquet.TypePtr(parquet.Type_BOOLEAN), nil, false},
+
+		{"int32 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), nil, true},
+		{"int32 2", int32(-1), int32(2), parquet.TypePtr(parquet.Type_INT32), nil, true},
+
+		{"int64 1", int64(-1), int64(-1), parquet.TypePtr(parquet.Type_INT64), nil, false},
+		{"int64 2", int64(-1), int64(1), parquet.TypePtr(parquet.Type_INT64), nil, true},
+
+		{"int96 1", string(StrIntToBinary("2147483648", "LittleEndian", 12, true)),
+			string(StrIntToBinary("2147483647", "LittleEndian", 12, true)), parquet.TypePtr(parquet.Type_INT96), nil, false},
+		{"int96 2", string(StrIntToBinary("-2147483648", "LittleEndian", 12, true)),
+			string(StrIntToBinary("-2147483647", "LittleEndian", 12, true)), parquet.TypePtr(parquet.Type_INT96), nil, true},
+
+		{"float 1", float32(0.1), float32(0.2), parquet.TypePtr(parquet.Type_FLOAT), nil, true},
+		{"float 1", float32(0.1), float32(0.1), parquet.TypePtr(parquet.Type_FLOAT), nil, false},
+
+		{"double 1", float64(0.1), float64(0.2), parquet.TypePtr(parquet.Type_DOUBLE), nil, true},
+		{"double 2", float64(0.1), float64(0.1), parquet.TypePtr(parquet.Type_DOUBLE), nil, false},
+
+		{"byte_array 1", string("abc bcd"), string("abc"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), nil, false},
+		{"byte_array 2", string("abc"), string("abc bcd"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), nil, true},
+		{"byte_array 3", string("abc bcd"), string("abc bcd"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), nil, false},
+
+		{"fixed 1", string("abc bcd"), string("abc aaa"), parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), nil, false},
+		{"fixed 2", string("abc"), string("bcd"), parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), nil, true},
+		{"fixed 3", string("abc bcd"), string("aac bcd"), parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), nil, false},
+
+		{"utf8 1", string("abc bcd"), string("abc"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8), false},
+		{"utf8 2", string("abc"), string("abc"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8), false},
+		{"utf8 3", string("abc"), string("abc def"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8), true},
+
+		{"int_8 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_8), true},
+		{"int_8 2", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_16), true},
+		{"int_8 3", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_32), true},
+		{"int_8 4", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_64), true},
+
+		{"uint_8 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), true},
+		{"uint_8 2", int32(1), int32(-2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), true},
+		{"uint_8 3", int32(-1), int32(-2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), false},
+		{"uint_8 4", int32(-2), int32(-1), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), true},
+		{"uint_16 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_16), true},
+		{"uint_16 2", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_32), true},
+		{"uint_16 3", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_64), true},
+		{"uint_32 1", int32(-1), int32(1), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_32), false},
+		{"uint_32 2", int32(math.MaxInt32), int32(math.MinInt32), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_32), true},
+		{"uint_64 1", int64(-1), int64(1), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_64), false},
+		{"uint_64 2", int64(math.MaxInt64), int64(math.MinInt64), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_64), true},
+
+		{"date 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_DATE), true},
+		{"time_millis 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_TIME_MILLIS), true},
+		{"time_micros 1", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_TIME_MICROS), true},
+		{"timestamp_micros 1", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MICROS), true},
+		{"timestamp_millis 1", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MILLIS), true},
+
+		{"interval 1", string(StrIntToBinary("12345", "LittleEndian", 12, false)),
+			string(StrIntToBinary("123456", "LittleEndian", 12, false)),
+			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_INTERVAL), true},
+		{"interval 2", string(StrIntToBinary("123457", "LittleEndian", 12, false)),
+			string(StrIntToBinary("123456", "LittleEndian", 12, false)),
+			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_INTERVAL), false},
+
+		{"decimal 1", int32(12345), int32(123), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), false},
+		{"decimal 2", int64(12345), int64(12346), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
+
+		{"decimal 3", string(StrIntToBinary("12345", "BigEndian", 0, true)),
+			string(StrIntToBinary("12346", "BigEndian", 0, true)),
+			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
+		{"decimal 4", string(StrIntToBinary("-12345", "BigEndian", 0, true)),
+			string(StrIntToBinary("-12346", "BigEndian", 0, true)),
+			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), false},
+
+		{"decimal 5", string(StrIntToBinary("12345", "BigEndian", 0, true)),
+			string(StrIntToBinary("12346", "BigEndian", 0, true)),
+			parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
+		{"decimal 6", string(StrIntToBinary("-12345", "BigEndian", 0, true)),
+			string(StrIntToBinary("-12346", "BigEndian", 0, true)),
+			parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), false},
+		{"decimal 7", "\xff", "\x01\x00", parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
+		{"decimal 8", "\x01\x00", "\x7f", parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), false},
+		{"decimal 9", "\x80\x00", "\xff\xff", parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
+		{"decimal 10", "\xff\xff", "\x00\x00", parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
+	}
+
+	for _, c := range cases {
+		funcTable := common.FindFuncTable(c.PT, c.CT, nil)
+		res := funcTable.LessThan(c.numa, c.numb)
+		if res != c.expect {
+			t.Errorf("Cmp error %v-%v, %v", c.numa, c.numa, c.str)
+		}
+	}
+}
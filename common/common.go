@@ -5,6 +5,7 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -47,6 +48,11 @@ type Tag struct {
 	KeyFieldID   int32
 	ValueFieldID int32
 
+	//The fieldid tags are set, so fieldid=0 is a field_id
+	HasFieldID      bool
+	KeyHasFieldID   bool
+	ValueHasFieldID bool
+
 	Encoding      parquet.Encoding
 	KeyEncoding   parquet.Encoding
 	ValueEncoding parquet.Encoding
@@ -145,14 +151,17 @@ func StringToTag(tag string) (*Tag, error) {
 			if mp.FieldID, err = Str2Int32(val); err != nil {
 				return nil, fmt.Errorf("failed to parse fieldid: %s", err.Error())
 			}
+			mp.HasFieldID = true
 		case "keyfieldid":
 			if mp.KeyFieldID, err = Str2Int32(val); err != nil {
 				return nil, fmt.Errorf("failed to parse keyfieldid: %s", err.Error())
 			}
+			mp.KeyHasFieldID = true
 		case "valuefieldid":
 			if mp.ValueFieldID, err = Str2Int32(val); err != nil {
 				return nil, fmt.Errorf("failed to parse valuefieldid: %s", err.Error())
 			}
+			mp.ValueHasFieldID = true
 		case "isadjustedtoutc":
 			if mp.IsAdjustedToUTC, err = Str2Bool(val); err != nil {
 				return nil, fmt.Errorf("failed to parse isadjustedtoutc: %s", err.Error())
@@ -289,13 +298,22 @@ func StringToTag(tag string) (*Tag, error) {
 	return mp, nil
 }
 
+//SetFieldID sets the field_id of the schema element from the fieldid tag.
+//A FieldID other than 0 is a field_id even without HasFieldID, for the tags created without StringToTag.
+func SetFieldID(schema *parquet.SchemaElement, info *Tag) {
+	if info.HasFieldID || info.FieldID != 0 {
+		fieldID := info.FieldID
+		schema.FieldID = &fieldID
+	}
+}
+
 func NewSchemaElementFromTagMap(info *Tag) (*parquet.SchemaElement, error) {
 	schema := parquet.NewSchemaElement()
 	schema.Name = info.InName
 	schema.TypeLength = &info.Length
 	schema.Scale = &info.Scale
 	schema.Precision = &info.Precision
-	schema.FieldID = &info.FieldID
+	SetFieldID(schema, info)
 	schema.RepetitionType = &info.RepetitionType
 	schema.NumChildren = nil
 
@@ -306,8 +324,19 @@ func NewSchemaElementFromTagMap(info *Tag) (*parquet.SchemaElement, error) {
 		return nil, fmt.Errorf("type %s: %s", info.Type, err.Error())
 	}
 
+	//BYTE_STREAM_SPLIT splits the bytes of the fixed width types
+	if info.Encoding == parquet.Encoding_BYTE_STREAM_SPLIT {
+		switch schema.GetType() {
+		case parquet.Type_FLOAT, parquet.Type_DOUBLE, parquet.Type_INT32, parquet.Type_INT64, parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		default:
+			return nil, fmt.Errorf("encoding BYTE_STREAM_SPLIT can't be used on %v", schema.GetType())
+		}
+	}
+
 	if ct, err := parquet.ConvertedTypeFromString(info.ConvertedType); err == nil {
 		schema.ConvertedType = &ct
+	} else if info.ConvertedType != "" {
+		return nil, fmt.Errorf("convertedtype %s: %s", info.ConvertedType, err.Error())
 	}
 
 	var logicalType *parquet.LogicalType
@@ -417,6 +446,12 @@ func NewLogicalTypeFromFieldsMap(mp map[string]string) (*parquet.LogicalType, er
 		case "UUID":
 			logicalType.UUID = parquet.NewUUIDType()
 
+		case "FLOAT16":
+			logicalType.FLOAT16 = parquet.NewFloat16Type()
+
+		case "UNKNOWN":
+			logicalType.UNKNOWN = parquet.NewNullType()
+
 		default:
 			return nil, fmt.Errorf("unknow logicaltype: %s", val)
 		}
@@ -542,6 +577,7 @@ func GetKeyTagMap(src *Tag) *Tag {
 	res.Scale = src.KeyScale
 	res.Precision = src.KeyPrecision
 	res.FieldID = src.KeyFieldID
+	res.HasFieldID = src.KeyHasFieldID
 	res.Encoding = src.KeyEncoding
 	res.OmitStats = src.KeyOmitStats
 	res.RepetitionType = parquet.FieldRepetitionType_REQUIRED
@@ -560,6 +596,7 @@ func GetValueTagMap(src *Tag) *Tag {
 	res.Scale = src.ValueScale
 	res.Precision = src.ValuePrecision
 	res.FieldID = src.ValueFieldID
+	res.HasFieldID = src.ValueHasFieldID
 	res.Encoding = src.ValueEncoding
 	res.OmitStats = src.ValueOmitStats
 	res.RepetitionType = src.ValueRepetitionType
@@ -606,6 +643,9 @@ func HeadToUpper(str string) string {
 func CmpIntBinary(as string, bs string, order string, signed bool) bool {
 	abs, bbs := []byte(as), []byte(bs)
 	la, lb := len(abs), len(bbs)
+	if la == 0 || lb == 0 {
+		return la < lb
+	}
 
 	if order == "LittleEndian" {
 		for i, j := 0, len(abs)-1; i < j; i, j = i+1, j-1 {
@@ -741,12 +781,70 @@ func FindFuncTable(pT *parquet.Type, cT *parquet.ConvertedType, logT *parquet.Lo
 
 		} else if logT.BSON != nil || logT.JSON != nil || logT.STRING != nil || logT.UUID != nil {
 			return stringFuncTable{}
+
+		} else if logT.FLOAT16 != nil {
+			return float16FuncTable{}
+
+		} else if logT.UNKNOWN != nil {
+			return FindFuncTable(pT, nil, nil)
 		}
 	}
 
 	panic("No known func table in FindFuncTable")
 }
 
+// IsBinaryOrdered reports whether the statistics of a column are compared
+// as unsigned byte strings, which is the only order that allows truncation.
+func IsBinaryOrdered(pT *parquet.Type, cT *parquet.ConvertedType, logT *parquet.LogicalType) bool {
+	if pT == nil || (*pT != parquet.Type_BYTE_ARRAY && *pT != parquet.Type_FIXED_LEN_BYTE_ARRAY) {
+		return false
+	}
+	if cT != nil && (*cT == parquet.ConvertedType_DECIMAL || *cT == parquet.ConvertedType_INTERVAL) {
+		return false
+	}
+	if logT != nil && (logT.DECIMAL != nil || logT.FLOAT16 != nil) {
+		return false
+	}
+	return true
+}
+
+// HasDefinedOrder reports whether the spec defines a sort order for a column.
+// The orders of INTERVAL and of the UNKNOWN (always null) logical type are undefined,
+// so their min/max statistics aren't written.
+func HasDefinedOrder(cT *parquet.ConvertedType, logT *parquet.LogicalType) bool {
+	if logT != nil && logT.IsSetUNKNOWN() {
+		return false
+	}
+	return cT == nil || *cT != parquet.ConvertedType_INTERVAL
+}
+
+// TruncateMinStatistic truncates a binary min statistic to at most length bytes.
+// A prefix of a value is never greater than the value, so it stays a valid lower bound.
+func TruncateMinStatistic(val []byte, length int) []byte {
+	if length <= 0 || len(val) <= length {
+		return val
+	}
+	return val[:length]
+}
+
+// TruncateMaxStatistic truncates a binary max statistic to at most length bytes
+// and increments the last byte so that the result stays a valid upper bound.
+// If no byte can be incremented the value is returned untruncated.
+func TruncateMaxStatistic(val []byte, length int) []byte {
+	if length <= 0 || len(val) <= length {
+		return val
+	}
+	for i := length - 1; i >= 0; i-- {
+		if val[i] != 0xFF {
+			res := make([]byte, i+1)
+			copy(res, val[:i+1])
+			res[i]++
+			return res
+		}
+	}
+	return val
+}
+
 func Str2Int32(val string) (int32, error) {
 	valInt, err := strconv.Atoi(val)
 	if err != nil {
@@ -890,6 +988,79 @@ func (table float64FuncTable) MinMaxSize(minVal interface{}, maxVal interface{},
 	return Min(table, minVal, val), Max(table, maxVal, val), 8
 }
 
+//FLOAT16 values are compared as floats
+type float16FuncTable struct{}
+
+func (_ float16FuncTable) LessThan(a interface{}, b interface{}) bool {
+	return FLOAT16ToFloat32(a.(string)) < FLOAT16ToFloat32(b.(string))
+}
+
+//NaN isn't ordered, it isn't a min or max value
+func (table float16FuncTable) MinMaxSize(minVal interface{}, maxVal interface{}, val interface{}) (interface{}, interface{}, int32) {
+	if math.IsNaN(float64(FLOAT16ToFloat32(val.(string)))) {
+		return minVal, maxVal, 2
+	}
+	return Min(table, minVal, val), Max(table, maxVal, val), 2
+}
+
+//Float32ToFLOAT16 converts f to the 2 bytes of the nearest half precision float (ties to even)
+func Float32ToFLOAT16(f float32) string {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+
+	var h uint16
+	switch {
+	case exp == 0xff: //NaN and infinities
+		h = sign | 0x7c00
+		if mant != 0 {
+			h |= 0x200 | uint16(mant>>13)
+		}
+	case exp-127 > 15: //overflow
+		h = sign | 0x7c00
+	case exp-127 >= -14: //normal
+		h = sign | uint16(exp-127+15)<<10 | uint16(mant>>13)
+		//round to nearest even, the carry may increase the exponent up to infinity
+		if rem := mant & 0x1fff; rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+			h++
+		}
+	case exp-127 >= -25: //subnormal
+		mant |= 0x800000
+		shift := uint32(-14-(exp-127)) + 13
+		h = sign | uint16(mant>>shift)
+		half := uint32(1) << (shift - 1)
+		if rem := mant & (half<<1 - 1); rem > half || (rem == half && h&1 == 1) {
+			h++
+		}
+	default: //underflow
+		h = sign
+	}
+	return string([]byte{byte(h), byte(h >> 8)})
+}
+
+//FLOAT16ToFloat32 converts the 2 bytes of a half precision float to float32
+func FLOAT16ToFloat32(s string) float32 {
+	h := uint32(s[0]) | uint32(s[1])<<8
+	sign := (h & 0x8000) << 16
+	exp := (h >> 10) & 0x1f
+	mant := h & 0x3ff
+
+	switch {
+	case exp == 0x1f: //NaN and infinities
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case exp == 0 && mant == 0:
+		return math.Float32frombits(sign)
+	case exp == 0: //subnormal
+		f := float32(mant) / (1 << 24)
+		if sign != 0 {
+			f = -f
+		}
+		return f
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
 type stringFuncTable struct{}
 
 func (_ stringFuncTable) LessThan(a interface{}, b interface{}) bool {
@@ -1238,6 +1409,34 @@ func ArrowColToParquetCol(field arrow.Field, col array.Interface) (
 				recs[i] = int64(arr.Value(i))
 			}
 		}
+	case *arrow.Time64Type:
+		arr := col.(*array.Time64)
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				if !field.Nullable {
+					return nil, nonNullableFieldContainsNullError(field, i)
+				}
+				recs[i] = nil
+			} else {
+				recs[i] = int64(arr.Value(i))
+			}
+		}
+	case *arrow.Float16Type:
+		arr := col.(*array.Float16)
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				if !field.Nullable {
+					return nil, nonNullableFieldContainsNullError(field, i)
+				}
+				recs[i] = nil
+			} else {
+				recs[i] = Float32ToFLOAT16(arr.Value(i).Float32())
+			}
+		}
+	case *arrow.NullType:
+		if col.Len() > 0 && !field.Nullable {
+			return nil, nonNullableFieldContainsNullError(field, 0)
+		}
 	}
 	return recs, nil
 }
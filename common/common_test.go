@@ -1,14 +1,12 @@
 package common
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
 	"github.com/xitongsys/parquet-go/parquet"
-	. "github.com/xitongsys/parquet-go/types"
 )
 
 func TestHeadToUpper(t *testing.T) {
@@ -30,180 +28,38 @@ func TestHeadToUpper(t *testing.T) {
 	}
 }
 
-func TestCmpIntBinary(t *testing.T) {
+func TestCmpLogicalType(t *testing.T) {
+	unsigned := &parquet.LogicalType{INTEGER: &parquet.IntType{BitWidth: 32, IsSigned: false}}
+	decimal := &parquet.LogicalType{DECIMAL: &parquet.DecimalType{Precision: 10, Scale: 2}}
 	cases := []struct {
-		numa int32
-		numb int32
+		str        string
+		numa, numb interface{}
+		PT         *parquet.Type
+		logT       *parquet.LogicalType
+		expect     bool
 	}{
-		{-1, 0},
-		{1, 2},
-		{1, 1},
-		{1, 0},
-		{0, 0},
-		{-1, -2},
-		{-2, -1},
-		{-1, 1},
-		{2147483647, 2147483647},
-		{-2147483648, -2147483647},
-		{-2147483648, 2147483647},
+		{"uint32", int32(1), int32(-1), parquet.TypePtr(parquet.Type_INT32), unsigned, true},
+		{"uint64", int64(1), int64(-1), parquet.TypePtr(parquet.Type_INT64), unsigned, true},
+		{"decimal bytes", "\xff", "\x01\x00", parquet.TypePtr(parquet.Type_BYTE_ARRAY), decimal, true},
+		{"decimal fixed", "\x01\x00", "\xff\xff", parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), decimal, false},
 	}
 
 	for _, c := range cases {
-		abuf, bbuf := new(bytes.Buffer), new(bytes.Buffer)
-		binary.Write(abuf, binary.LittleEndian, c.numa)
-		binary.Write(bbuf, binary.LittleEndian, c.numb)
-		as, bs := string(abuf.Bytes()), string(bbuf.Bytes())
-		if (c.numa < c.numb) != (CmpIntBinary(as, bs, "LittleEndian", true)) {
-			t.Errorf("CmpIntBinary error, %v-%v", c.numa, c.numb)
-		}
-	}
-
-	cases2 := []struct {
-		numa string
-		numb string
-	}{
-		{"-1", "0"},
-		{"1", "2"},
-		{"1", "1"},
-		{"1", "0"},
-		{"0", "0"},
-		{"-123", "-2"},
-		{"-2", "-1"},
-		{"-1344", "123"},
-		{"2147483647", "2147483647"},
-		{"-2147483648", "-2147483647"},
-		{"-2147483648", "2147483647"},
-	}
-
-	for _, c := range cases2 {
-		as := StrIntToBinary(c.numa, "LittleEndian", 0, true)
-		bs := StrIntToBinary(c.numb, "LittleEndian", 0, true)
-		an, bn := 0, 0
-		fmt.Sscanf(c.numa, "%d", &an)
-		fmt.Sscanf(c.numb, "%d", &bn)
-		if (an < bn) != (CmpIntBinary(as, bs, "LittleEndian", true)) {
-			t.Errorf("CmpIntBinary error, %v-%v", c.numa, c.numb)
-		}
-	}
-
-	cases3 := []struct {
-		numa string
-		numb string
-	}{
-		{"1", "2"},
-		{"1", "1"},
-		{"1", "0"},
-		{"0", "0"},
-		{"123", "2"},
-		{"1344", "123"},
-		{"2147483647", "2147483647"},
-		{"2147483648", "2147483647"},
-	}
-
-	for _, c := range cases3 {
-		as := StrIntToBinary(c.numa, "LittleEndian", 0, false)
-		bs := StrIntToBinary(c.numb, "LittleEndian", 0, false)
-		an, bn := uint64(0), uint64(0)
-		fmt.Sscanf(c.numa, "%d", &an)
-		fmt.Sscanf(c.numb, "%d", &bn)
-		if (an < bn) != (CmpIntBinary(as, bs, "LittleEndian", false)) {
-			t.Errorf("CmpIntBinary error, %v-%v", c.numa, c.numb)
+		if res := FindFuncTable(c.PT, nil, c.logT).LessThan(c.numa, c.numb); res != c.expect {
+			t.Errorf("Cmp error %v-%v, %v", c.numa, c.numb, c.str)
 		}
 	}
 }
 
-func TestCmp(t *testing.T) {
-	cases := []struct {
-		str    string
-		numa   interface{}
-		numb   interface{}
-		PT     *parquet.Type
-		CT     *parquet.ConvertedType
-		expect bool
-	}{
-		{"bool 1", bool(false), bool(true), parquet.TypePtr(parquet.Type_BOOLEAN), nil, true},
-		{"bool 2", bool(true), bool(false), parquet.TypePtr(parquet.Type_BOOLEAN), nil, false},
-		{"bool 3", bool(true), bool(true), parquet.TypePtr(parquet.Type_BOOLEAN), nil, false},
-
-		{"int32 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), nil, true},
-		{"int32 2", int32(-1), int32(2), parquet.TypePtr(parquet.Type_INT32), nil, true},
-
-		{"int64 1", int64(-1), int64(-1), parquet.TypePtr(parquet.Type_INT64), nil, false},
-		{"int64 2", int64(-1), int64(1), parquet.TypePtr(parquet.Type_INT64), nil, true},
-
-		{"int96 1", string(StrIntToBinary("2147483648", "LittleEndian", 12, true)),
-			string(StrIntToBinary("2147483647", "LittleEndian", 12, true)), parquet.TypePtr(parquet.Type_INT96), nil, false},
-		{"int96 2", string(StrIntToBinary("-2147483648", "LittleEndian", 12, true)),
-			string(StrIntToBinary("-2147483647", "LittleEndian", 12, true)), parquet.TypePtr(parquet.Type_INT96), nil, true},
-
-		{"float 1", float32(0.1), float32(0.2), parquet.TypePtr(parquet.Type_FLOAT), nil, true},
-		{"float 1", float32(0.1), float32(0.1), parquet.TypePtr(parquet.Type_FLOAT), nil, false},
-
-		{"double 1", float64(0.1), float64(0.2), parquet.TypePtr(parquet.Type_DOUBLE), nil, true},
-		{"double 2", float64(0.1), float64(0.1), parquet.TypePtr(parquet.Type_DOUBLE), nil, false},
-
-		{"byte_array 1", string("abc bcd"), string("abc"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), nil, false},
-		{"byte_array 2", string("abc"), string("abc bcd"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), nil, true},
-		{"byte_array 3", string("abc bcd"), string("abc bcd"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), nil, false},
-
-		{"fixed 1", string("abc bcd"), string("abc aaa"), parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), nil, false},
-		{"fixed 2", string("abc"), string("bcd"), parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), nil, true},
-		{"fixed 3", string("abc bcd"), string("aac bcd"), parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), nil, false},
-
-		{"utf8 1", string("abc bcd"), string("abc"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8), false},
-		{"utf8 2", string("abc"), string("abc"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8), false},
-		{"utf8 3", string("abc"), string("abc def"), parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8), true},
-
-		{"int_8 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_8), true},
-		{"int_8 2", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_16), true},
-		{"int_8 3", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_32), true},
-		{"int_8 4", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_INT_64), true},
-
-		{"uint_8 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), true},
-		{"uint_8 2", int32(1), int32(-2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), true},
-		{"uint_8 3", int32(-1), int32(-2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), false},
-		{"uint_8 4", int32(-2), int32(-1), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_8), true},
-		{"uint_16 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_16), true},
-		{"uint_16 2", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_32), true},
-		{"uint_16 3", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_64), true},
-
-		{"date 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_DATE), true},
-		{"time_millis 1", int32(1), int32(2), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_TIME_MILLIS), true},
-		{"time_micros 1", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_TIME_MICROS), true},
-		{"timestamp_micros 1", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MICROS), true},
-		{"timestamp_millis 1", int64(1), int64(2), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MILLIS), true},
-
-		{"interval 1", string(StrIntToBinary("12345", "LittleEndian", 12, false)),
-			string(StrIntToBinary("123456", "LittleEndian", 12, false)),
-			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_INTERVAL), true},
-		{"interval 2", string(StrIntToBinary("123457", "LittleEndian", 12, false)),
-			string(StrIntToBinary("123456", "LittleEndian", 12, false)),
-			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_INTERVAL), false},
-
-		{"decimal 1", int32(12345), int32(123), parquet.TypePtr(parquet.Type_INT32), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), false},
-		{"decimal 2", int64(12345), int64(12346), parquet.TypePtr(parquet.Type_INT64), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
-
-		{"decimal 3", string(StrIntToBinary("12345", "BigEndian", 0, true)),
-			string(StrIntToBinary("12346", "BigEndian", 0, true)),
-			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
-		{"decimal 4", string(StrIntToBinary("-12345", "BigEndian", 0, true)),
-			string(StrIntToBinary("-12346", "BigEndian", 0, true)),
-			parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), false},
-
-		{"decimal 5", string(StrIntToBinary("12345", "BigEndian", 0, true)),
-			string(StrIntToBinary("12346", "BigEndian", 0, true)),
-			parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), true},
-		{"decimal 6", string(StrIntToBinary("-12345", "BigEndian", 0, true)),
-			string(StrIntToBinary("-12346", "BigEndian", 0, true)),
-			parquet.TypePtr(parquet.Type_BYTE_ARRAY), parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), false},
+func TestHasDefinedOrder(t *testing.T) {
+	if HasDefinedOrder(parquet.ConvertedTypePtr(parquet.ConvertedType_INTERVAL), nil) {
+		t.Errorf("INTERVAL has no defined order")
 	}
-
-	for _, c := range cases {
-		funcTable := FindFuncTable(c.PT, c.CT, nil)
-		res := funcTable.LessThan(c.numa, c.numb)
-		if res != c.expect {
-			t.Errorf("Cmp error %v-%v, %v", c.numa, c.numa, c.str)
-		}
+	if HasDefinedOrder(nil, &parquet.LogicalType{UNKNOWN: parquet.NewNullType()}) {
+		t.Errorf("UNKNOWN has no defined order")
+	}
+	if !HasDefinedOrder(parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_32), nil) || !HasDefinedOrder(nil, nil) {
+		t.Errorf("UINT_32 and plain columns have a defined order")
 	}
 }
 
@@ -341,3 +197,91 @@ func TestStrToPath(t *testing.T) {
 		}
 	}
 }
+
+func TestTruncateStatistic(t *testing.T) {
+	testData := []struct {
+		Val         string
+		Length      int
+		ExpectedMin string
+		ExpectedMax string
+	}{
+		{"abc", 0, "abc", "abc"},
+		{"abc", 3, "abc", "abc"},
+		{"abcdef", 3, "abc", "abd"},
+		{"ab\xffdef", 3, "ab\xff", "ac"},
+		{"\xff\xff\xffa", 3, "\xff\xff\xff", "\xff\xff\xffa"},
+	}
+
+	for _, data := range testData {
+		resMin := string(TruncateMinStatistic([]byte(data.Val), data.Length))
+		if resMin != data.ExpectedMin {
+			t.Errorf("TruncateMinStatistic err, expect %q, get %q", data.ExpectedMin, resMin)
+		}
+		resMax := string(TruncateMaxStatistic([]byte(data.Val), data.Length))
+		if resMax != data.ExpectedMax {
+			t.Errorf("TruncateMaxStatistic err, expect %q, get %q", data.ExpectedMax, resMax)
+		}
+	}
+}
+
+func TestNewSchemaElementFromTagMapByteStreamSplit(t *testing.T) {
+	for tag, valid := range map[string]bool{
+		"name=a, type=FLOAT, encoding=BYTE_STREAM_SPLIT":                          true,
+		"name=a, type=INT64, encoding=BYTE_STREAM_SPLIT":                          true,
+		"name=a, type=FIXED_LEN_BYTE_ARRAY, length=3, encoding=BYTE_STREAM_SPLIT": true,
+		"name=a, type=BYTE_ARRAY, encoding=BYTE_STREAM_SPLIT":                     false,
+		"name=a, type=BOOLEAN, encoding=BYTE_STREAM_SPLIT":                        false,
+	} {
+		info, err := StringToTag(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = NewSchemaElementFromTagMap(info); (err == nil) != valid {
+			t.Errorf("%v: expect valid %v, get %v", tag, valid, err)
+		}
+	}
+}
+
+func TestFloat16(t *testing.T) {
+	testData := []struct {
+		f    float32
+		bits uint16
+		back float32
+	}{
+		{0, 0x0000, 0},
+		{1, 0x3c00, 1},
+		{-2, 0xc000, -2},
+		{0.1, 0x2e66, 0.099975586},
+		{65504, 0x7bff, 65504},
+		{65520, 0x7c00, float32(math.Inf(1))},
+		{float32(math.Inf(-1)), 0xfc00, float32(math.Inf(-1))},
+		{1.0 / (1 << 24), 0x0001, 1.0 / (1 << 24)},
+		{1.0 / (1 << 26), 0x0000, 0},
+		//ties to even
+		{1 + 1.0/(1<<11), 0x3c00, 1},
+		{1 + 3.0/(1<<11), 0x3c02, 1 + 2.0/(1<<10)},
+	}
+	for _, data := range testData {
+		s := Float32ToFLOAT16(data.f)
+		if bits := uint16(s[0]) | uint16(s[1])<<8; bits != data.bits {
+			t.Errorf("Float32ToFLOAT16(%v) = %#04x, expect %#04x", data.f, bits, data.bits)
+		}
+		if back := FLOAT16ToFloat32(s); back != data.back {
+			t.Errorf("FLOAT16ToFloat32(%#04x) = %v, expect %v", data.bits, back, data.back)
+		}
+	}
+	if f := FLOAT16ToFloat32(Float32ToFLOAT16(float32(math.NaN()))); !math.IsNaN(float64(f)) {
+		t.Errorf("expect NaN, get %v", f)
+	}
+}
+
+func TestFloat16MinMax(t *testing.T) {
+	funcTable := FindFuncTable(parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY), nil, &parquet.LogicalType{FLOAT16: parquet.NewFloat16Type()})
+	var minVal, maxVal interface{}
+	for _, f := range []float32{float32(math.NaN()), 1.5, -4.5, float32(math.NaN()), 4.5} {
+		minVal, maxVal, _ = funcTable.MinMaxSize(minVal, maxVal, Float32ToFLOAT16(f))
+	}
+	if minVal != Float32ToFLOAT16(-4.5) || maxVal != Float32ToFLOAT16(4.5) {
+		t.Errorf("expect min -4.5 and max 4.5, get %v and %v", minVal, maxVal)
+	}
+}
@@ -227,6 +227,40 @@ func ReadBitPacked(bytesReader *bytes.Reader, header uint64, bitWidth uint64) ([
 	return res, err
 }
 
+//Read the deprecated BIT_PACKED encoding, in which values are packed from the most significant bit.
+//return res is []INT64
+func ReadBitPackedDeprecated(bytesReader *bytes.Reader, cnt uint64, bitWidth uint64) ([]interface{}, error) {
+	var err error
+	res := make([]interface{}, 0, cnt)
+	if cnt == 0 {
+		return res, nil
+	}
+
+	if bitWidth == 0 {
+		for i := 0; i < int(cnt); i++ {
+			res = append(res, int64(0))
+		}
+		return res, err
+	}
+
+	bytesBuf := make([]byte, (cnt*bitWidth+7)/8)
+	if _, err = io.ReadFull(bytesReader, bytesBuf); err != nil {
+		return res, err
+	}
+
+	var pos uint64 = 0
+	for i := uint64(0); i < cnt; i++ {
+		var val uint64 = 0
+		for j := uint64(0); j < bitWidth; j++ {
+			bit := (bytesBuf[pos/8] >> (7 - pos%8)) & 1
+			val = (val << 1) | uint64(bit)
+			pos++
+		}
+		res = append(res, int64(val))
+	}
+	return res, err
+}
+
 //res is INT64
 func ReadRLEBitPackedHybrid(bytesReader *bytes.Reader, bitWidth uint64, length uint64) ([]interface{}, error) {
 	res := make([]interface{}, 0)
@@ -483,3 +517,27 @@ func ReadByteStreamSplitFloat64(bytesReader *bytes.Reader, cnt uint64) ([]interf
 
 	return res, err
 }
+
+func ReadByteStreamSplitINT32(bytesReader *bytes.Reader, cnt uint64) ([]interface{}, error) {
+	buf, err := readByteStreamSplit(bytesReader, cnt, 4)
+	if err != nil {
+		return make([]interface{}, cnt), err
+	}
+	return ReadPlainINT32(bytes.NewReader(buf), cnt)
+}
+
+func ReadByteStreamSplitINT64(bytesReader *bytes.Reader, cnt uint64) ([]interface{}, error) {
+	buf, err := readByteStreamSplit(bytesReader, cnt, 8)
+	if err != nil {
+		return make([]interface{}, cnt), err
+	}
+	return ReadPlainINT64(bytes.NewReader(buf), cnt)
+}
+
+func ReadByteStreamSplitFIXED_LEN_BYTE_ARRAY(bytesReader *bytes.Reader, cnt uint64, fixedLength uint64) ([]interface{}, error) {
+	buf, err := readByteStreamSplit(bytesReader, cnt, fixedLength)
+	if err != nil {
+		return make([]interface{}, cnt), err
+	}
+	return ReadPlainFIXED_LEN_BYTE_ARRAY(bytes.NewReader(buf), cnt, fixedLength)
+}
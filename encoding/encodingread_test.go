@@ -245,3 +245,52 @@ func TestReadBitPacked(t *testing.T) {
 		}
 	}
 }
+
+func TestReadBitPackedDeprecated(t *testing.T) {
+	testData := []struct {
+		data     []interface{}
+		bitWidth uint64
+	}{
+		{[]interface{}{int64(1), int64(0), int64(1), int64(1), int64(0), int64(0), int64(1), int64(0)}, 1},
+		{[]interface{}{int64(0), int64(1), int64(2), int64(3), int64(4), int64(5), int64(6), int64(7)}, 3},
+	}
+	for _, data := range testData {
+		res, err := ReadBitPackedDeprecated(bytes.NewReader(WriteBitPackedDeprecated(data.data, int64(data.bitWidth))), uint64(len(data.data)), data.bitWidth)
+		if err != nil || fmt.Sprintf("%v", res) != fmt.Sprintf("%v", data.data) {
+			t.Errorf("ReadBitPackedDeprecated err, expect %v, get %v, err info:%v", data.data, res, err)
+		}
+	}
+
+	// values are packed from the most significant bit and the last byte is padded
+	res, _ := ReadBitPackedDeprecated(bytes.NewReader([]byte{0x29, 0xC0}), 4, 3)
+	if fmt.Sprintf("%v", res) != "[1 2 3 4]" {
+		t.Errorf("ReadBitPackedDeprecated err, expect [1 2 3 4], get %v", res)
+	}
+
+	res, _ = ReadBitPackedDeprecated(bytes.NewReader([]byte{}), 3, 0)
+	if fmt.Sprintf("%v", res) != "[0 0 0]" {
+		t.Errorf("ReadBitPackedDeprecated err, expect [0 0 0], get %v", res)
+	}
+}
+
+func TestReadByteStreamSplit(t *testing.T) {
+	//streams of the k-th bytes of the little endian values
+	int32Res, _ := ReadByteStreamSplitINT32(bytes.NewReader([]byte{0x0D, 0x04, 0x0C, 0x03, 0x0B, 0x02, 0x0A, 0x01}), 2)
+	if fmt.Sprintf("%v", int32Res) != fmt.Sprintf("%v", []interface{}{int32(0x0A0B0C0D), int32(0x01020304)}) {
+		t.Errorf("ReadByteStreamSplitINT32 err, get %v", int32Res)
+	}
+
+	int64Res, _ := ReadByteStreamSplitINT64(bytes.NewReader([]byte{1, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF}), 2)
+	if fmt.Sprintf("%v", int64Res) != "[1 -1]" {
+		t.Errorf("ReadByteStreamSplitINT64 err, expect [1 -1], get %v", int64Res)
+	}
+
+	flbaRes, _ := ReadByteStreamSplitFIXED_LEN_BYTE_ARRAY(bytes.NewReader([]byte("adgbehcfi")), 3, 3)
+	if fmt.Sprintf("%v", flbaRes) != "[abc def ghi]" {
+		t.Errorf("ReadByteStreamSplitFIXED_LEN_BYTE_ARRAY err, expect [abc def ghi], get %v", flbaRes)
+	}
+
+	if _, err := ReadByteStreamSplitINT32(bytes.NewReader([]byte{1, 2, 3}), 1); err == nil {
+		t.Errorf("ReadByteStreamSplitINT32 err, expect error on short data")
+	}
+}
@@ -511,6 +511,10 @@ func WriteByteStreamSplit(nums []interface{}) []byte {
 		return WriteByteStreamSplitFloat32(nums)
 	} else if _, ok := nums[0].(float64); ok {
 		return WriteByteStreamSplitFloat64(nums)
+	} else if _, ok := nums[0].(int32); ok {
+		return WriteByteStreamSplitINT32(nums)
+	} else if _, ok := nums[0].(int64); ok {
+		return WriteByteStreamSplitINT64(nums)
 	} else {
 		return []byte{}
 	}
@@ -552,3 +556,15 @@ func WriteByteStreamSplitFloat64(vals []interface{}) []byte {
 	}
 	return buf
 }
+
+func WriteByteStreamSplitINT32(vals []interface{}) []byte {
+	return writeByteStreamSplit(WritePlainINT32(vals), 4)
+}
+
+func WriteByteStreamSplitINT64(vals []interface{}) []byte {
+	return writeByteStreamSplit(WritePlainINT64(vals), 8)
+}
+
+func WriteByteStreamSplitFIXED_LEN_BYTE_ARRAY(vals []interface{}, fixedLength int32) []byte {
+	return writeByteStreamSplit(WritePlainFIXED_LEN_BYTE_ARRAY(vals), int(fixedLength))
+}
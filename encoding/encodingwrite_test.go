@@ -1,6 +1,7 @@
 package encoding
 
 import (
+	"bytes"
 	"encoding/json"
 	"math/bits"
 	"testing"
@@ -300,3 +301,22 @@ func TestWriteBitPackedDeprecated(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteByteStreamSplit(t *testing.T) {
+	testData := []struct {
+		nums     []interface{}
+		expected []byte
+	}{
+		{[]interface{}{}, []byte{}},
+		{[]interface{}{int32(0x0A0B0C0D), int32(0x01020304)}, []byte{0x0D, 0x04, 0x0C, 0x03, 0x0B, 0x02, 0x0A, 0x01}},
+		{[]interface{}{int64(1), int64(-1)}, []byte{1, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF, 0, 0xFF}},
+		{[]interface{}{float32(1)}, []byte{0, 0, 0x80, 0x3F}},
+	}
+
+	for _, data := range testData {
+		res := WriteByteStreamSplit(data.nums)
+		if !bytes.Equal(res, data.expected) {
+			t.Errorf("WriteByteStreamSplit err, expect %v, get %v", data.expected, res)
+		}
+	}
+}
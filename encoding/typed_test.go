@@ -0,0 +1,287 @@
+package encoding
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTypedPlain(t *testing.T) {
+	bools := []bool{true, false, false, true, true, true, false, true, true}
+	int32s := []int32{0, 1, -1, math.MaxInt32, math.MinInt32}
+	int64s := []int64{0, 1, -1, math.MaxInt64, math.MinInt64}
+	float32s := []float32{0, 1.5, -2.25, math.MaxFloat32}
+	float64s := []float64{0, 1.5, -2.25, math.MaxFloat64}
+	byteArrays := []string{"a", "", "hello", "world"}
+
+	boolsRes, _ := ReadPlainBOOLEANTyped(bytes.NewReader(WritePlainBOOLEANTyped(bools)), uint64(len(bools)))
+	int32sRes, _ := ReadPlainINT32Typed(bytes.NewReader(WritePlainINT32Typed(int32s)), uint64(len(int32s)))
+	int64sRes, _ := ReadPlainINT64Typed(bytes.NewReader(WritePlainINT64Typed(int64s)), uint64(len(int64s)))
+	float32sRes, _ := ReadPlainFLOATTyped(bytes.NewReader(WritePlainFLOATTyped(float32s)), uint64(len(float32s)))
+	float64sRes, _ := ReadPlainDOUBLETyped(bytes.NewReader(WritePlainDOUBLETyped(float64s)), uint64(len(float64s)))
+	byteArraysRes, _ := ReadPlainBYTE_ARRAYTyped(bytes.NewReader(WritePlainBYTE_ARRAYTyped(byteArrays)), uint64(len(byteArrays)))
+	flbaRes, _ := ReadPlainFIXED_LEN_BYTE_ARRAYTyped(bytes.NewReader(WritePlainFIXED_LEN_BYTE_ARRAYTyped([]string{"ab", "cd"})), 2, 2)
+
+	testData := []struct {
+		expected interface{}
+		get      interface{}
+	}{
+		{bools, boolsRes},
+		{int32s, int32sRes},
+		{int64s, int64sRes},
+		{float32s, float32sRes},
+		{float64s, float64sRes},
+		{byteArrays, byteArraysRes},
+		{[]string{"ab", "cd"}, flbaRes},
+	}
+	for _, data := range testData {
+		if fmt.Sprintf("%v", data.expected) != fmt.Sprintf("%v", data.get) {
+			t.Errorf("typed plain err, expect %v, get %v", data.expected, data.get)
+		}
+	}
+
+	//same output as the interface{} encoders
+	if !bytes.Equal(WritePlainBOOLEANTyped(bools), WritePlainBOOLEAN(toInterfaces(bools))) {
+		t.Errorf("WritePlainBOOLEANTyped err, output differs from WritePlainBOOLEAN")
+	}
+	if !bytes.Equal(WritePlainINT64Typed(int64s), WritePlainINT64(toInterfaces(int64s))) {
+		t.Errorf("WritePlainINT64Typed err, output differs from WritePlainINT64")
+	}
+
+	if _, err := ReadPlainINT32Typed(bytes.NewReader([]byte{1, 2}), 1); err == nil {
+		t.Errorf("ReadPlainINT32Typed err, expect error on short data")
+	}
+}
+
+func TestReadRLEBitPackedHybridInt32(t *testing.T) {
+	testData := [][]int32{
+		{},
+		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		{1, 2, 3, 4, 5, 6, 7, 1, 2, 3},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 0},
+	}
+
+	for _, data := range testData {
+		buf := WriteRLEBitPackedHybridInt32(data, 3)
+		res, err := ReadRLEBitPackedHybridInt32(bytes.NewReader(buf), 3, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := ReadRLEBitPackedHybrid(bytes.NewReader(buf), 3, 0)
+		if fmt.Sprintf("%v", res) != fmt.Sprintf("%v", expected) {
+			t.Errorf("ReadRLEBitPackedHybridInt32 err, expect %v, get %v", expected, res)
+		}
+	}
+
+	//bit packed runs
+	vals := []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5), int64(6), int64(7), int64(0)}
+	buf := append(WriteUnsignedVarInt(1<<1|1), WriteBitPacked(vals, 3, false)...)
+	res, err := ReadRLEBitPackedHybridInt32(bytes.NewReader(buf), 3, uint64(len(buf)))
+	if err != nil || fmt.Sprintf("%v", res) != "[1 2 3 4 5 6 7 0]" {
+		t.Errorf("ReadRLEBitPackedHybridInt32 err, expect %v, get %v, %v", vals, res, err)
+	}
+}
+
+func TestTypedDelta(t *testing.T) {
+	int32Data := [][]int32{
+		{},
+		{1},
+		{1, 2, 3, 4, 5},
+		make([]int32, 300),
+		{-1, 100, -1000, math.MaxInt32, math.MinInt32, 0},
+	}
+	for i := range int32Data[3] {
+		int32Data[3][i] = int32(i * i)
+	}
+	for i, data := range int32Data {
+		buf := WriteDeltaINT32Typed(data)
+		//WriteDeltaINT32 doesn't handle the deltas overflowing int32
+		if i > 0 && i < 4 && !bytes.Equal(buf, WriteDeltaINT32(toInterfaces(data))) {
+			t.Errorf("WriteDeltaINT32Typed err, output differs from WriteDeltaINT32 for %v", data)
+		}
+		res, err := ReadDeltaBinaryPackedINT32Typed(bytes.NewReader(buf))
+		if err != nil || fmt.Sprintf("%v", res) != fmt.Sprintf("%v", data) {
+			t.Errorf("ReadDeltaBinaryPackedINT32Typed err, expect %v, get %v, %v", data, res, err)
+		}
+	}
+
+	int64Data := [][]int64{
+		{1},
+		{5, 4, 3, 2, 1, 0, -1},
+		{-1, 100, -1000, math.MaxInt64, math.MinInt64, 0},
+	}
+	for i, data := range int64Data {
+		buf := WriteDeltaINT64Typed(data)
+		if i < 2 && !bytes.Equal(buf, WriteDeltaINT64(toInterfaces(data))) {
+			t.Errorf("WriteDeltaINT64Typed err, output differs from WriteDeltaINT64 for %v", data)
+		}
+		res, err := ReadDeltaBinaryPackedINT64Typed(bytes.NewReader(buf))
+		if err != nil || fmt.Sprintf("%v", res) != fmt.Sprintf("%v", data) {
+			t.Errorf("ReadDeltaBinaryPackedINT64Typed err, expect %v, get %v, %v", data, res, err)
+		}
+	}
+
+	byteArrays := []string{"apple", "applesauce", "banana", "", "band"}
+	res, err := ReadDeltaLengthByteArrayTyped(bytes.NewReader(WriteDeltaLengthByteArrayTyped(byteArrays)))
+	if err != nil || fmt.Sprintf("%s", res) != fmt.Sprintf("%s", byteArrays) {
+		t.Errorf("ReadDeltaLengthByteArrayTyped err, expect %s, get %s, %v", byteArrays, res, err)
+	}
+	res, err = ReadDeltaByteArrayTyped(bytes.NewReader(WriteDeltaByteArrayTyped(byteArrays)))
+	if err != nil || fmt.Sprintf("%s", res) != fmt.Sprintf("%s", byteArrays) {
+		t.Errorf("ReadDeltaByteArrayTyped err, expect %s, get %s, %v", byteArrays, res, err)
+	}
+	strs, _ := ReadDeltaByteArray(bytes.NewReader(WriteDeltaByteArrayTyped(byteArrays)))
+	if fmt.Sprintf("%s", strs) != fmt.Sprintf("%s", byteArrays) {
+		t.Errorf("ReadDeltaByteArray err, expect %s, get %s", byteArrays, strs)
+	}
+}
+
+func TestTypedByteStreamSplit(t *testing.T) {
+	float32s := []float32{0, 1.5, -2.25, math.MaxFloat32}
+	float64s := []float64{0, 1.5, -2.25, math.MaxFloat64, math.Inf(-1)}
+	int32s := []int32{0, 1, -1, math.MaxInt32, math.MinInt32}
+	int64s := []int64{0, 1, -1, math.MaxInt64, math.MinInt64}
+	flbas := []string{"abc", "def", "\x00\x01\x02"}
+
+	//same output as the interface{} encoders
+	testData := []struct {
+		name     string
+		typed    []byte
+		boxed    []byte
+		expected interface{}
+		read     func(*bytes.Reader) (interface{}, error)
+	}{
+		{"Float32", WriteByteStreamSplitFloat32Typed(float32s), WriteByteStreamSplitFloat32(toInterfaces(float32s)), float32s,
+			func(r *bytes.Reader) (interface{}, error) { return ReadByteStreamSplitFloat32Typed(r, uint64(len(float32s))) }},
+		{"Float64", WriteByteStreamSplitFloat64Typed(float64s), WriteByteStreamSplitFloat64(toInterfaces(float64s)), float64s,
+			func(r *bytes.Reader) (interface{}, error) { return ReadByteStreamSplitFloat64Typed(r, uint64(len(float64s))) }},
+		{"INT32", WriteByteStreamSplitINT32Typed(int32s), WriteByteStreamSplitINT32(toInterfaces(int32s)), int32s,
+			func(r *bytes.Reader) (interface{}, error) { return ReadByteStreamSplitINT32Typed(r, uint64(len(int32s))) }},
+		{"INT64", WriteByteStreamSplitINT64Typed(int64s), WriteByteStreamSplitINT64(toInterfaces(int64s)), int64s,
+			func(r *bytes.Reader) (interface{}, error) { return ReadByteStreamSplitINT64Typed(r, uint64(len(int64s))) }},
+		{"FIXED_LEN_BYTE_ARRAY", WriteByteStreamSplitFIXED_LEN_BYTE_ARRAYTyped(flbas, 3), WriteByteStreamSplitFIXED_LEN_BYTE_ARRAY(toInterfaces(flbas), 3), flbas,
+			func(r *bytes.Reader) (interface{}, error) {
+				return ReadByteStreamSplitFIXED_LEN_BYTE_ARRAYTyped(r, uint64(len(flbas)), 3)
+			}},
+	}
+	for _, data := range testData {
+		if !bytes.Equal(data.typed, data.boxed) {
+			t.Errorf("WriteByteStreamSplit%sTyped err, output differs from WriteByteStreamSplit%s", data.name, data.name)
+		}
+		res, err := data.read(bytes.NewReader(data.typed))
+		if err != nil || fmt.Sprintf("%q", res) != fmt.Sprintf("%q", data.expected) {
+			t.Errorf("ReadByteStreamSplit%sTyped err, expect %v, get %v, %v", data.name, data.expected, res, err)
+		}
+	}
+
+	if _, err := ReadByteStreamSplitINT64Typed(bytes.NewReader([]byte{1, 2, 3}), 1); err == nil {
+		t.Errorf("ReadByteStreamSplitINT64Typed err, expect error on short data")
+	}
+}
+
+func toInterfaces[T any](vals []T) []interface{} {
+	res := make([]interface{}, len(vals))
+	for i, v := range vals {
+		res[i] = v
+	}
+	return res
+}
+
+func benchmarkInt64s() []int64 {
+	r := rand.New(rand.NewSource(0))
+	res := make([]int64, 10000)
+	for i := range res {
+		res[i] = int64(i)*100 + r.Int63n(100)
+	}
+	return res
+}
+
+func BenchmarkWritePlainINT64(b *testing.B) {
+	vals := toInterfaces(benchmarkInt64s())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WritePlainINT64(vals)
+	}
+}
+
+func BenchmarkWritePlainINT64Typed(b *testing.B) {
+	vals := benchmarkInt64s()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WritePlainINT64Typed(vals)
+	}
+}
+
+func BenchmarkReadPlainINT64(b *testing.B) {
+	buf := WritePlainINT64Typed(benchmarkInt64s())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReadPlainINT64(bytes.NewReader(buf), 10000)
+	}
+}
+
+func BenchmarkReadPlainINT64Typed(b *testing.B) {
+	buf := WritePlainINT64Typed(benchmarkInt64s())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReadPlainINT64Typed(bytes.NewReader(buf), 10000)
+	}
+}
+
+func BenchmarkWriteDeltaINT64(b *testing.B) {
+	vals := toInterfaces(benchmarkInt64s())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WriteDeltaINT64(vals)
+	}
+}
+
+func BenchmarkWriteDeltaINT64Typed(b *testing.B) {
+	vals := benchmarkInt64s()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WriteDeltaINT64Typed(vals)
+	}
+}
+
+func BenchmarkReadDeltaBinaryPackedINT64(b *testing.B) {
+	buf := WriteDeltaINT64Typed(benchmarkInt64s())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReadDeltaBinaryPackedINT64(bytes.NewReader(buf))
+	}
+}
+
+func BenchmarkReadDeltaBinaryPackedINT64Typed(b *testing.B) {
+	buf := WriteDeltaINT64Typed(benchmarkInt64s())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReadDeltaBinaryPackedINT64Typed(bytes.NewReader(buf))
+	}
+}
+
+func BenchmarkReadRLEBitPackedHybrid(b *testing.B) {
+	levels := make([]int32, 10000)
+	for i := range levels {
+		levels[i] = int32(i % 3)
+	}
+	buf := WriteRLEBitPackedHybridInt32(levels, 2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReadRLEBitPackedHybrid(bytes.NewReader(buf), 2, 0)
+	}
+}
+
+func BenchmarkReadRLEBitPackedHybridInt32(b *testing.B) {
+	levels := make([]int32, 10000)
+	for i := range levels {
+		levels[i] = int32(i % 3)
+	}
+	buf := WriteRLEBitPackedHybridInt32(levels, 2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ReadRLEBitPackedHybridInt32(bytes.NewReader(buf), 2, 0)
+	}
+}
@@ -0,0 +1,459 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+//Typed variants of the decoders, named like the interface{} decoders with the suffix Typed.
+//They return one slice of the go type of the physical type instead of boxing every value:
+//BYTE_ARRAY, FIXED_LEN_BYTE_ARRAY and INT96 values are strings, like in layout.Table.
+
+func readFull(bytesReader *bytes.Reader, ln uint64) ([]byte, error) {
+	if ln > uint64(bytesReader.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	buf := make([]byte, ln)
+	_, err := io.ReadFull(bytesReader, buf)
+	return buf, err
+}
+
+func ReadPlainBOOLEANTyped(bytesReader *bytes.Reader, cnt uint64) ([]bool, error) {
+	buf, err := readFull(bytesReader, (cnt+7)/8)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]bool, cnt)
+	for i := range res {
+		res[i] = (buf[i/8]>>uint(i%8))&1 == 1
+	}
+	return res, nil
+}
+
+func ReadPlainINT32Typed(bytesReader *bytes.Reader, cnt uint64) ([]int32, error) {
+	buf, err := readFull(bytesReader, cnt*4)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int32, cnt)
+	for i := range res {
+		res[i] = int32(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return res, nil
+}
+
+func ReadPlainINT64Typed(bytesReader *bytes.Reader, cnt uint64) ([]int64, error) {
+	buf, err := readFull(bytesReader, cnt*8)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, cnt)
+	for i := range res {
+		res[i] = int64(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+	return res, nil
+}
+
+func ReadPlainFLOATTyped(bytesReader *bytes.Reader, cnt uint64) ([]float32, error) {
+	buf, err := readFull(bytesReader, cnt*4)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float32, cnt)
+	for i := range res {
+		res[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return res, nil
+}
+
+func ReadPlainDOUBLETyped(bytesReader *bytes.Reader, cnt uint64) ([]float64, error) {
+	buf, err := readFull(bytesReader, cnt*8)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, cnt)
+	for i := range res {
+		res[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+	return res, nil
+}
+
+//The returned values share one buffer
+func ReadPlainINT96Typed(bytesReader *bytes.Reader, cnt uint64) ([]string, error) {
+	return ReadPlainFIXED_LEN_BYTE_ARRAYTyped(bytesReader, cnt, 12)
+}
+
+//The returned values share one buffer
+func ReadPlainBYTE_ARRAYTyped(bytesReader *bytes.Reader, cnt uint64) ([]string, error) {
+	res := make([]string, cnt)
+	buf := make([]byte, bytesReader.Len())
+	bytesReader.Read(buf)
+	//the lengths are read from buf and the values are sliced from str, which is one copy of buf
+	str := string(buf)
+	pos := 0
+	for i := range res {
+		if pos+4 > len(buf) {
+			return res, io.ErrUnexpectedEOF
+		}
+		ln := int(binary.LittleEndian.Uint32(buf[pos:]))
+		pos += 4
+		if ln < 0 || pos+ln > len(buf) {
+			return res, io.ErrUnexpectedEOF
+		}
+		res[i] = str[pos : pos+ln]
+		pos += ln
+	}
+	bytesReader.Seek(int64(pos-len(buf)), io.SeekCurrent)
+	return res, nil
+}
+
+//The returned values share one buffer
+func ReadPlainFIXED_LEN_BYTE_ARRAYTyped(bytesReader *bytes.Reader, cnt uint64, fixedLength uint64) ([]string, error) {
+	buf, err := readFull(bytesReader, cnt*fixedLength)
+	if err != nil {
+		return nil, err
+	}
+	return splitFixed(string(buf), cnt, fixedLength), nil
+}
+
+//Split str to cnt values of fixedLength bytes
+func splitFixed(str string, cnt uint64, fixedLength uint64) []string {
+	res := make([]string, cnt)
+	for i := range res {
+		res[i] = str[uint64(i)*fixedLength : uint64(i+1)*fixedLength]
+	}
+	return res
+}
+
+//Unpack cnt values bit packed from the least significant bit
+func unpackBits(buf []byte, cnt int, bitWidth uint, dst []uint64) []uint64 {
+	if bitWidth == 0 {
+		for i := 0; i < cnt; i++ {
+			dst = append(dst, 0)
+		}
+		return dst
+	}
+	var pos uint
+	for i := 0; i < cnt; i++ {
+		var v uint64
+		for got := uint(0); got < bitWidth; {
+			b := uint64(buf[pos/8]) >> (pos % 8)
+			n := min(8-pos%8, bitWidth-got)
+			v |= (b & (1<<n - 1)) << got
+			got += n
+			pos += n
+		}
+		dst = append(dst, v)
+	}
+	return dst
+}
+
+//Read the RLE/bit packed hybrid encoding into int32 values, which is used by levels and dictionary indexes.
+//If length is 0, it's read from the first 4 bytes.
+func ReadRLEBitPackedHybridInt32(bytesReader *bytes.Reader, bitWidth uint64, length uint64) ([]int32, error) {
+	if length <= 0 {
+		lb, err := readFull(bytesReader, 4)
+		if err != nil {
+			return nil, err
+		}
+		length = uint64(binary.LittleEndian.Uint32(lb))
+	}
+	buf, err := readFull(bytesReader, length)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]int32, 0)
+	var unpacked []uint64
+	newReader := bytes.NewReader(buf)
+	for newReader.Len() > 0 {
+		header, err := ReadUnsignedVarInt(newReader)
+		if err != nil {
+			return res, err
+		}
+		if header&1 == 0 {
+			cnt := header >> 1
+			var val int32
+			for i := uint64(0); i < (bitWidth+7)/8; i++ {
+				b, err := newReader.ReadByte()
+				if err != nil {
+					return res, err
+				}
+				val |= int32(b) << (8 * i)
+			}
+			for i := uint64(0); i < cnt; i++ {
+				res = append(res, val)
+			}
+
+		} else {
+			cnt := (header >> 1) * 8
+			packed, err := readFull(newReader, cnt*bitWidth/8)
+			if err != nil {
+				return res, err
+			}
+			unpacked = unpackBits(packed, int(cnt), uint(bitWidth), unpacked[:0])
+			for _, v := range unpacked {
+				res = append(res, int32(v))
+			}
+		}
+	}
+	return res, nil
+}
+
+//Read the delta binary packed encoding, the values are computed with 64 bits
+//and the callers truncate them to the width of the column.
+func readDeltaBinaryPacked(bytesReader *bytes.Reader, add func(delta uint64)) (uint64, uint64, error) {
+	blockSize, err := ReadUnsignedVarInt(bytesReader)
+	if err != nil {
+		return 0, 0, err
+	}
+	numMiniblocksInBlock, err := ReadUnsignedVarInt(bytesReader)
+	if err != nil {
+		return 0, 0, err
+	}
+	numValues, err := ReadUnsignedVarInt(bytesReader)
+	if err != nil {
+		return 0, 0, err
+	}
+	firstValueZigZag, err := ReadUnsignedVarInt(bytesReader)
+	if err != nil {
+		return 0, 0, err
+	}
+	if numMiniblocksInBlock == 0 || blockSize%numMiniblocksInBlock != 0 {
+		return 0, 0, fmt.Errorf("invalid delta binary packed header: block size %d, miniblocks %d", blockSize, numMiniblocksInBlock)
+	}
+	numValuesInMiniBlock := blockSize / numMiniblocksInBlock
+
+	bitWidths := make([]byte, numMiniblocksInBlock)
+	unpacked := make([]uint64, 0, numValuesInMiniBlock)
+	for read := uint64(1); read < numValues; {
+		minDeltaZigZag, err := ReadUnsignedVarInt(bytesReader)
+		if err != nil {
+			return 0, 0, err
+		}
+		minDelta := uint64(int64(minDeltaZigZag>>1) ^ -(int64(minDeltaZigZag) & 1))
+		if _, err = io.ReadFull(bytesReader, bitWidths); err != nil {
+			return 0, 0, err
+		}
+		for i := 0; i < len(bitWidths) && read < numValues; i++ {
+			packed, err := readFull(bytesReader, numValuesInMiniBlock*uint64(bitWidths[i])/8)
+			if err != nil {
+				return 0, 0, err
+			}
+			unpacked = unpackBits(packed, int(numValuesInMiniBlock), uint(bitWidths[i]), unpacked[:0])
+			for j := 0; j < len(unpacked) && read < numValues; j++ {
+				add(unpacked[j] + minDelta)
+				read++
+			}
+		}
+	}
+	return numValues, firstValueZigZag, nil
+}
+
+func ReadDeltaBinaryPackedINT32Typed(bytesReader *bytes.Reader) ([]int32, error) {
+	var res []int32
+	var cur int32
+	numValues, firstValueZigZag, err := readDeltaBinaryPacked(bytesReader, func(delta uint64) {
+		if res == nil {
+			res = make([]int32, 1, 128)
+		}
+		cur += int32(delta)
+		res = append(res, cur)
+	})
+	if err != nil || numValues == 0 {
+		return []int32{}, err
+	}
+	fv32 := int32(firstValueZigZag)
+	firstValue := int32(uint32(fv32)>>1) ^ -(fv32 & 1)
+	if res == nil {
+		res = make([]int32, 1)
+	}
+	res[0] = firstValue
+	for i := 1; i < len(res); i++ {
+		res[i] += firstValue
+	}
+	return res, nil
+}
+
+func ReadDeltaBinaryPackedINT64Typed(bytesReader *bytes.Reader) ([]int64, error) {
+	var res []int64
+	var cur int64
+	numValues, firstValueZigZag, err := readDeltaBinaryPacked(bytesReader, func(delta uint64) {
+		if res == nil {
+			res = make([]int64, 1, 128)
+		}
+		cur += int64(delta)
+		res = append(res, cur)
+	})
+	if err != nil || numValues == 0 {
+		return []int64{}, err
+	}
+	firstValue := int64(firstValueZigZag>>1) ^ -(int64(firstValueZigZag) & 1)
+	if res == nil {
+		res = make([]int64, 1)
+	}
+	res[0] = firstValue
+	for i := 1; i < len(res); i++ {
+		res[i] += firstValue
+	}
+	return res, nil
+}
+
+//The returned values share one buffer
+func ReadDeltaLengthByteArrayTyped(bytesReader *bytes.Reader) ([]string, error) {
+	lengths, err := ReadDeltaBinaryPackedINT32Typed(bytesReader)
+	if err != nil {
+		return nil, err
+	}
+	total := uint64(0)
+	for _, ln := range lengths {
+		if ln < 0 {
+			return nil, fmt.Errorf("invalid negative length %d in DELTA_LENGTH_BYTE_ARRAY", ln)
+		}
+		total += uint64(ln)
+	}
+	buf, err := readFull(bytesReader, total)
+	if err != nil {
+		return nil, err
+	}
+	str := string(buf)
+	res := make([]string, len(lengths))
+	pos := 0
+	for i, ln := range lengths {
+		res[i] = str[pos : pos+int(ln)]
+		pos += int(ln)
+	}
+	return res, nil
+}
+
+//The values with a prefix are built in one buffer
+func ReadDeltaByteArrayTyped(bytesReader *bytes.Reader) ([]string, error) {
+	prefixLengths, err := ReadDeltaBinaryPackedINT32Typed(bytesReader)
+	if err != nil {
+		return nil, err
+	}
+	suffixes, err := ReadDeltaLengthByteArrayTyped(bytesReader)
+	if err != nil {
+		return nil, err
+	}
+	if len(suffixes) < len(prefixLengths) {
+		return nil, fmt.Errorf("DELTA_BYTE_ARRAY has %d prefixes but %d suffixes", len(prefixLengths), len(suffixes))
+	}
+	total := 0
+	for i := range prefixLengths {
+		total += int(prefixLengths[i]) + len(suffixes[i])
+	}
+	//the values are appended to buf, which has the capacity for all of them, so the previous value stays in place
+	buf := make([]byte, 0, total)
+	ends := make([]int, len(prefixLengths))
+	prevBegin := 0
+	for i := range prefixLengths {
+		prefixLength := int(prefixLengths[i])
+		if prefixLength < 0 || prefixLength > len(buf)-prevBegin {
+			return nil, fmt.Errorf("invalid prefix length %d in DELTA_BYTE_ARRAY", prefixLength)
+		}
+		begin := len(buf)
+		buf = append(buf, buf[prevBegin:prevBegin+prefixLength]...)
+		buf = append(buf, suffixes[i]...)
+		ends[i] = len(buf)
+		prevBegin = begin
+	}
+	str := string(buf)
+	res := make([]string, len(prefixLengths))
+	begin := 0
+	for i, end := range ends {
+		res[i] = str[begin:end]
+		begin = end
+	}
+	return res, nil
+}
+
+//Read cnt byte stream split values with width bytes each and
+//return them in plain encoding order
+func readByteStreamSplit(bytesReader *bytes.Reader, cnt uint64, width uint64) ([]byte, error) {
+	buf, err := readFull(bytesReader, cnt*width)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, len(buf))
+	for i := uint64(0); i < cnt; i++ {
+		for k := uint64(0); k < width; k++ {
+			res[i*width+k] = buf[k*cnt+i]
+		}
+	}
+	return res, nil
+}
+
+func ReadByteStreamSplitFloat32Typed(bytesReader *bytes.Reader, cnt uint64) ([]float32, error) {
+	buf, err := readFull(bytesReader, cnt*4)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float32, cnt)
+	for i := range res {
+		res[i] = math.Float32frombits(uint32(buf[i]) |
+			uint32(buf[cnt+uint64(i)])<<8 |
+			uint32(buf[cnt*2+uint64(i)])<<16 |
+			uint32(buf[cnt*3+uint64(i)])<<24)
+	}
+	return res, nil
+}
+
+func ReadByteStreamSplitFloat64Typed(bytesReader *bytes.Reader, cnt uint64) ([]float64, error) {
+	buf, err := readFull(bytesReader, cnt*8)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]float64, cnt)
+	for i := range res {
+		var v uint64
+		for k := uint64(0); k < 8; k++ {
+			v |= uint64(buf[cnt*k+uint64(i)]) << (8 * k)
+		}
+		res[i] = math.Float64frombits(v)
+	}
+	return res, nil
+}
+
+func ReadByteStreamSplitINT32Typed(bytesReader *bytes.Reader, cnt uint64) ([]int32, error) {
+	buf, err := readFull(bytesReader, cnt*4)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int32, cnt)
+	for i := range res {
+		res[i] = int32(uint32(buf[i]) |
+			uint32(buf[cnt+uint64(i)])<<8 |
+			uint32(buf[cnt*2+uint64(i)])<<16 |
+			uint32(buf[cnt*3+uint64(i)])<<24)
+	}
+	return res, nil
+}
+
+func ReadByteStreamSplitINT64Typed(bytesReader *bytes.Reader, cnt uint64) ([]int64, error) {
+	buf, err := readFull(bytesReader, cnt*8)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, cnt)
+	for i := range res {
+		var v uint64
+		for k := uint64(0); k < 8; k++ {
+			v |= uint64(buf[cnt*k+uint64(i)]) << (8 * k)
+		}
+		res[i] = int64(v)
+	}
+	return res, nil
+}
+
+//The returned values share one buffer
+func ReadByteStreamSplitFIXED_LEN_BYTE_ARRAYTyped(bytesReader *bytes.Reader, cnt uint64, fixedLength uint64) ([]string, error) {
+	buf, err := readByteStreamSplit(bytesReader, cnt, fixedLength)
+	if err != nil {
+		return nil, err
+	}
+	return splitFixed(string(buf), cnt, fixedLength), nil
+}
@@ -0,0 +1,252 @@
+package encoding
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+//Typed variants of the encoders, named like the interface{} encoders with the suffix Typed.
+//They work on slices of the go types of the physical types, so the values are never boxed:
+//BYTE_ARRAY, FIXED_LEN_BYTE_ARRAY and INT96 values are strings, like in layout.Table.
+
+func WritePlainBOOLEANTyped(vals []bool) []byte {
+	res := make([]byte, (len(vals)+7)/8)
+	for i, v := range vals {
+		if v {
+			res[i/8] |= 1 << uint32(i%8)
+		}
+	}
+	return res
+}
+
+func WritePlainINT32Typed(vals []int32) []byte {
+	res := make([]byte, len(vals)*4)
+	for i, v := range vals {
+		binary.LittleEndian.PutUint32(res[i*4:], uint32(v))
+	}
+	return res
+}
+
+func WritePlainINT64Typed(vals []int64) []byte {
+	res := make([]byte, len(vals)*8)
+	for i, v := range vals {
+		binary.LittleEndian.PutUint64(res[i*8:], uint64(v))
+	}
+	return res
+}
+
+func WritePlainFLOATTyped(vals []float32) []byte {
+	res := make([]byte, len(vals)*4)
+	for i, v := range vals {
+		binary.LittleEndian.PutUint32(res[i*4:], math.Float32bits(v))
+	}
+	return res
+}
+
+func WritePlainDOUBLETyped(vals []float64) []byte {
+	res := make([]byte, len(vals)*8)
+	for i, v := range vals {
+		binary.LittleEndian.PutUint64(res[i*8:], math.Float64bits(v))
+	}
+	return res
+}
+
+//Plain encoding of BYTE_ARRAY: 4 bytes length followed by the bytes
+func WritePlainBYTE_ARRAYTyped(vals []string) []byte {
+	bufLen := 0
+	for _, v := range vals {
+		bufLen += 4 + len(v)
+	}
+	res := make([]byte, bufLen)
+	pos := 0
+	for _, v := range vals {
+		binary.LittleEndian.PutUint32(res[pos:], uint32(len(v)))
+		pos += 4
+		pos += copy(res[pos:], v)
+	}
+	return res
+}
+
+//Plain encoding of FIXED_LEN_BYTE_ARRAY and INT96: the bytes of the values
+func WritePlainFIXED_LEN_BYTE_ARRAYTyped(vals []string) []byte {
+	bufLen := 0
+	for _, v := range vals {
+		bufLen += len(v)
+	}
+	res := make([]byte, 0, bufLen)
+	for _, v := range vals {
+		res = append(res, v...)
+	}
+	return res
+}
+
+//Append vals bit packed from the least significant bit
+func appendBitPacked(dst []byte, vals []uint64, bitWidth uint) []byte {
+	if bitWidth == 0 {
+		return dst
+	}
+	var acc, spill uint64
+	var accBits uint
+	for _, v := range vals {
+		if bitWidth < 64 {
+			v &= (1 << bitWidth) - 1
+		}
+		acc |= v << accBits
+		spill = 0
+		if accBits > 0 {
+			spill = v >> (64 - accBits)
+		}
+		if accBits+bitWidth >= 64 {
+			dst = binary.LittleEndian.AppendUint64(dst, acc)
+			acc, accBits = spill, accBits+bitWidth-64
+		} else {
+			accBits += bitWidth
+		}
+	}
+	for ; accBits > 0; accBits -= min(accBits, 8) {
+		dst = append(dst, byte(acc))
+		acc >>= 8
+	}
+	return dst
+}
+
+//Write the delta binary packed encoding. deltas are the differences between
+//consecutive values and bitSize is the width of the column, which the packed
+//values are computed in.
+func writeDeltaBinaryPacked(firstValueZigZag uint64, deltas []int64, totalNumValues int, bitSize uint) []byte {
+	const blockSize, numMiniBlocksInBlock, numValuesInMiniBlock = 128, 4, 32
+	res := make([]byte, 0, 16+len(deltas))
+	res = append(res, WriteUnsignedVarInt(blockSize)...)
+	res = append(res, WriteUnsignedVarInt(numMiniBlocksInBlock)...)
+	res = append(res, WriteUnsignedVarInt(uint64(totalNumValues))...)
+	res = append(res, WriteUnsignedVarInt(firstValueZigZag)...)
+
+	var block [blockSize]uint64
+	for i := 0; i < len(deltas); i += blockSize {
+		end := min(i+blockSize, len(deltas))
+		minDelta := deltas[i]
+		for _, d := range deltas[i:end] {
+			minDelta = min(minDelta, d)
+		}
+		for j := 0; j < blockSize; j++ {
+			block[j] = 0
+			if i+j < end {
+				block[j] = uint64(deltas[i+j] - minDelta)
+				if bitSize < 64 {
+					block[j] &= 1<<bitSize - 1
+				}
+			}
+		}
+		var minDeltaZigZag uint64 = uint64((minDelta >> 63) ^ (minDelta << 1))
+		res = append(res, WriteUnsignedVarInt(minDeltaZigZag)...)
+
+		var bitWidths [numMiniBlocksInBlock]byte
+		for j := 0; j < numMiniBlocksInBlock; j++ {
+			var maxValue uint64
+			for _, v := range block[j*numValuesInMiniBlock : (j+1)*numValuesInMiniBlock] {
+				maxValue |= v
+			}
+			bitWidths[j] = byte(bits.Len64(maxValue))
+		}
+		res = append(res, bitWidths[:]...)
+		for j := 0; j < numMiniBlocksInBlock; j++ {
+			res = appendBitPacked(res, block[j*numValuesInMiniBlock:(j+1)*numValuesInMiniBlock], uint(bitWidths[j]))
+		}
+	}
+	return res
+}
+
+func WriteDeltaINT32Typed(vals []int32) []byte {
+	if len(vals) <= 0 {
+		return writeDeltaBinaryPacked(0, nil, 0, 32)
+	}
+	deltas := make([]int64, len(vals)-1)
+	for i := 1; i < len(vals); i++ {
+		deltas[i-1] = int64(vals[i] - vals[i-1])
+	}
+	return writeDeltaBinaryPacked(uint64(uint32((vals[0]>>31)^(vals[0]<<1))), deltas, len(vals), 32)
+}
+
+func WriteDeltaINT64Typed(vals []int64) []byte {
+	if len(vals) <= 0 {
+		return writeDeltaBinaryPacked(0, nil, 0, 64)
+	}
+	deltas := make([]int64, len(vals)-1)
+	for i := 1; i < len(vals); i++ {
+		deltas[i-1] = vals[i] - vals[i-1]
+	}
+	return writeDeltaBinaryPacked(uint64((vals[0]>>63)^(vals[0]<<1)), deltas, len(vals), 64)
+}
+
+func WriteDeltaLengthByteArrayTyped(vals []string) []byte {
+	lengths := make([]int32, len(vals))
+	bufLen := 0
+	for i, v := range vals {
+		lengths[i] = int32(len(v))
+		bufLen += len(v)
+	}
+	lengthBuf := WriteDeltaINT32Typed(lengths)
+	res := make([]byte, 0, len(lengthBuf)+bufLen)
+	res = append(res, lengthBuf...)
+	for _, v := range vals {
+		res = append(res, v...)
+	}
+	return res
+}
+
+func WriteDeltaByteArrayTyped(vals []string) []byte {
+	if len(vals) <= 0 {
+		return append(WriteDeltaINT32Typed(nil), WriteDeltaLengthByteArrayTyped(nil)...)
+	}
+	prefixLengths := make([]int32, len(vals))
+	suffixes := make([]string, len(vals))
+	suffixes[0] = vals[0]
+	for i := 1; i < len(vals); i++ {
+		prev, cur := vals[i-1], vals[i]
+		j := 0
+		for j < len(prev) && j < len(cur) && prev[j] == cur[j] {
+			j++
+		}
+		prefixLengths[i] = int32(j)
+		suffixes[i] = cur[j:]
+	}
+	res := WriteDeltaINT32Typed(prefixLengths)
+	return append(res, WriteDeltaLengthByteArrayTyped(suffixes)...)
+}
+
+//Byte stream split of plain encoded values with width bytes each:
+//the k-th byte of every value is written to the k-th stream
+func writeByteStreamSplit(plain []byte, width int) []byte {
+	if width <= 0 {
+		return []byte{}
+	}
+	ln := len(plain) / width
+	res := make([]byte, ln*width)
+	for i := 0; i < ln; i++ {
+		for k := 0; k < width; k++ {
+			res[k*ln+i] = plain[i*width+k]
+		}
+	}
+	return res
+}
+
+func WriteByteStreamSplitFloat32Typed(vals []float32) []byte {
+	return writeByteStreamSplit(WritePlainFLOATTyped(vals), 4)
+}
+
+func WriteByteStreamSplitFloat64Typed(vals []float64) []byte {
+	return writeByteStreamSplit(WritePlainDOUBLETyped(vals), 8)
+}
+
+func WriteByteStreamSplitINT32Typed(vals []int32) []byte {
+	return writeByteStreamSplit(WritePlainINT32Typed(vals), 4)
+}
+
+func WriteByteStreamSplitINT64Typed(vals []int64) []byte {
+	return writeByteStreamSplit(WritePlainINT64Typed(vals), 8)
+}
+
+func WriteByteStreamSplitFIXED_LEN_BYTE_ARRAYTyped(vals []string, fixedLength int32) []byte {
+	return writeByteStreamSplit(WritePlainFIXED_LEN_BYTE_ARRAYTyped(vals), int(fixedLength))
+}
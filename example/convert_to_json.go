@@ -1,8 +1,8 @@
 package main
 
 import (
-	"encoding/json"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/xitongsys/parquet-go-source/local"
@@ -79,19 +79,14 @@ func main() {
 	}
 
 	num = int(pr.GetNumRows())
-	res, err := pr.ReadByNumber(num)
+	//records with the original names as keys and formatted logical types
+	res, err := pr.ReadJSON(num)
 	if err != nil {
 		log.Println("Can't read", err)
 		return
 	}
 
-	jsonBs, err := json.Marshal(res)
-	if err != nil {
-		log.Println("Can't to json", err)
-		return
-	}
-
-	log.Println(string(jsonBs))
+	log.Println("[" + strings.Join(res, ",") + "]")
 
 	pr.ReadStop()
 	fr.Close()
@@ -12,6 +12,7 @@ require (
 	github.com/pierrec/lz4/v4 v4.1.8
 	github.com/stretchr/testify v1.7.0
 	github.com/xitongsys/parquet-go-source v0.0.0-20200817004010-026bad9b25d0
+	google.golang.org/protobuf v1.34.2
 )
 
 require (
@@ -0,0 +1,49 @@
+package layout
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//PageChecksumError is returned when the CRC32 of a page doesn't match the crc in its header
+type PageChecksumError struct {
+	//Path of the column
+	Path string
+	//Offset of the page in the file, -1 if unknown
+	Offset int64
+	//Checksum stored in the page header
+	Expected int32
+	//Checksum of the page data
+	Actual int32
+}
+
+func (e *PageChecksumError) Error() string {
+	return fmt.Sprintf("page checksum mismatch in column %s at offset %d: expect %d, get %d",
+		strings.ReplaceAll(e.Path, common.PAR_GO_PATH_DELIMITER, "."), e.Offset, e.Expected, e.Actual)
+}
+
+//PageChecksum computes the CRC32 of the compressed page data(levels included for data page v2)
+func PageChecksum(data []byte) int32 {
+	return int32(crc32.ChecksumIEEE(data))
+}
+
+//VerifyPageChecksum checks the page data against the crc in the page header.
+//Pages without crc are always valid.
+func VerifyPageChecksum(header *parquet.PageHeader, data []byte, path string) error {
+	if header == nil || !header.IsSetCrc() {
+		return nil
+	}
+	if crc := PageChecksum(data); crc != header.GetCrc() {
+		return &PageChecksumError{
+			Path:     path,
+			Offset:   -1,
+			Expected: header.GetCrc(),
+			Actual:   crc,
+		}
+	}
+	return nil
+}
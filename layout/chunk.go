@@ -3,7 +3,6 @@ package layout
 import (
 	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/xitongsys/parquet-go/common"
-	"github.com/xitongsys/parquet-go/encoding"
 	"github.com/xitongsys/parquet-go/parquet"
 	"github.com/xitongsys/parquet-go/schema"
 )
@@ -50,7 +49,9 @@ func PagesToChunk(pages []*Page) *Chunk {
 	metaData.Encodings = append(metaData.Encodings, parquet.Encoding_RLE)
 	metaData.Encodings = append(metaData.Encodings, parquet.Encoding_BIT_PACKED)
 	metaData.Encodings = append(metaData.Encodings, parquet.Encoding_PLAIN)
-	//metaData.Encodings = append(metaData.Encodings, parquet.Encoding_DELTA_BINARY_PACKED)
+	if encoding := pages[0].Info.Encoding; encoding != 0 && encoding != parquet.Encoding_RLE && encoding != parquet.Encoding_BIT_PACKED {
+		metaData.Encodings = append(metaData.Encodings, encoding)
+	}
 	metaData.Codec = pages[0].CompressType
 	metaData.NumValues = numValues
 	metaData.TotalCompressedSize = totalCompressedSize
@@ -59,12 +60,9 @@ func PagesToChunk(pages []*Page) *Chunk {
 	metaData.Statistics = parquet.NewStatistics()
 
 	if !omitStats && maxVal != nil && minVal != nil {
-		tmpBufMax := encoding.WritePlain([]interface{}{maxVal}, *pT)
-		tmpBufMin := encoding.WritePlain([]interface{}{minVal}, *pT)
-		if *pT == parquet.Type_BYTE_ARRAY {
-			tmpBufMax = tmpBufMax[4:]
-			tmpBufMin = tmpBufMin[4:]
-		}
+		truncateLength := pages[0].StatisticsTruncateLength
+		tmpBufMax := EncodeStatisticsValue(maxVal, pages[0].Schema, truncateLength, true)
+		tmpBufMin := EncodeStatisticsValue(minVal, pages[0].Schema, truncateLength, false)
 		metaData.Statistics.Max = tmpBufMax
 		metaData.Statistics.Min = tmpBufMin
 		metaData.Statistics.MaxValue = tmpBufMax
@@ -128,12 +126,9 @@ func PagesToDictChunk(pages []*Page) *Chunk {
 	metaData.Statistics = parquet.NewStatistics()
 
 	if !omitStats && maxVal != nil && minVal != nil {
-		tmpBufMax := encoding.WritePlain([]interface{}{maxVal}, *pT)
-		tmpBufMin := encoding.WritePlain([]interface{}{minVal}, *pT)
-		if *pT == parquet.Type_BYTE_ARRAY {
-			tmpBufMax = tmpBufMax[4:]
-			tmpBufMin = tmpBufMin[4:]
-		}
+		truncateLength := pages[1].StatisticsTruncateLength
+		tmpBufMax := EncodeStatisticsValue(maxVal, pages[1].Schema, truncateLength, true)
+		tmpBufMin := EncodeStatisticsValue(minVal, pages[1].Schema, truncateLength, false)
 		metaData.Statistics.Max = tmpBufMax
 		metaData.Statistics.Min = tmpBufMin
 		metaData.Statistics.MaxValue = tmpBufMax
@@ -153,13 +148,7 @@ func DecodeDictChunk(chunk *Chunk) {
 	dictPage := chunk.Pages[0]
 	numPages := len(chunk.Pages)
 	for i := 1; i < numPages; i++ {
-		numValues := len(chunk.Pages[i].DataTable.Values)
-		for j := 0; j < numValues; j++ {
-			if chunk.Pages[i].DataTable.Values[j] != nil {
-				index := chunk.Pages[i].DataTable.Values[j].(int64)
-				chunk.Pages[i].DataTable.Values[j] = dictPage.DataTable.Values[index]
-			}
-		}
+		chunk.Pages[i].Decode(dictPage)
 	}
 	chunk.Pages = chunk.Pages[1:] // delete the head dict page
 }
@@ -183,5 +172,8 @@ func ReadChunk(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.Sc
 	if len(chunk.Pages) > 0 && chunk.Pages[0].Header.GetType() == parquet.PageType_DICTIONARY_PAGE {
 		DecodeDictChunk(chunk)
 	}
+	for _, page := range chunk.Pages {
+		page.DataTable.BoxValues()
+	}
 	return chunk, nil
 }
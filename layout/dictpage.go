@@ -15,6 +15,8 @@ type DictRecType struct {
 	DictMap   map[interface{}]int32
 	DictSlice []interface{}
 	Type      parquet.Type
+	//Compute the CRC32 of the dictionary page
+	PageChecksum bool
 }
 
 func NewDictRec(pT parquet.Type) *DictRecType {
@@ -39,6 +41,7 @@ func DictRecToDictPage(dictRec *DictRecType, pageSize int32, compressType parque
 		Type: &dataType,
 	}
 	page.CompressType = compressType
+	page.PageChecksum = dictRec.PageChecksum
 
 	page.DictPageCompress(compressType, dictRec.Type)
 	totSize += int64(len(page.RawData))
@@ -59,6 +62,11 @@ func (page *Page) DictPageCompress(compressType parquet.CompressionCodec, pT par
 	page.Header.DictionaryPageHeader.NumValues = int32(len(page.DataTable.Values))
 	page.Header.DictionaryPageHeader.Encoding = parquet.Encoding_PLAIN
 
+	if page.PageChecksum {
+		crc := PageChecksum(dataEncodeBuf)
+		page.Header.Crc = &crc
+	}
+
 	ts := thrift.NewTSerializer()
 	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
 	pageHeaderBuf, _ := ts.Write(context.TODO(), page.Header)
@@ -78,6 +86,7 @@ func TableToDictDataPages(dictRec *DictRecType, table *Table, pageSize int32, bi
 	i := 0
 
 	pT, cT, logT, omitStats := table.Schema.Type, table.Schema.ConvertedType, table.Schema.LogicalType, table.Info.OmitStats
+	omitMinMax := omitStats || !common.HasDefinedOrder(cT, logT)
 
 	for i < totalLn {
 		j := i
@@ -91,11 +100,12 @@ func TableToDictDataPages(dictRec *DictRecType, table *Table, pageSize int32, bi
 
 		funcTable := common.FindFuncTable(pT, cT, logT)
 
-		for j < totalLn && size < pageSize {
+		//a page ends at the start of a row, so the rows of the offset index aren't split
+		for j < totalLn && (size < pageSize || !table.rowStart(j)) {
 			if table.DefinitionLevels[j] == table.MaxDefinitionLevel {
 				numValues++
 				var elSize int32
-				if omitStats {
+				if omitMinMax {
 					_, _, elSize = funcTable.MinMaxSize(nil, nil, table.Values[j])
 				} else {
 					minVal, maxVal, elSize = funcTable.MinMaxSize(minVal, maxVal, table.Values[j])
@@ -133,14 +143,18 @@ func TableToDictDataPages(dictRec *DictRecType, table *Table, pageSize int32, bi
 		//page.DataTable.Values = values
 
 		if !omitStats {
-			page.MaxVal = maxVal
-			page.MinVal = minVal
+			if !omitMinMax {
+				page.MaxVal = maxVal
+				page.MinVal = minVal
+			}
 			page.NullCount = &nullCount
 		}
 		page.Schema = table.Schema
 		page.CompressType = compressType
 		page.Path = table.Path
 		page.Info = table.Info
+		page.StatisticsTruncateLength = table.StatisticsTruncateLength
+		page.PageChecksum = table.PageChecksum
 
 		page.DictDataPageCompress(compressType, bitWidth, values)
 
@@ -194,24 +208,23 @@ func (page *Page) DictDataPageCompress(compressType parquet.CompressionCodec, bi
 
 	page.Header.DataPageHeader.Statistics = parquet.NewStatistics()
 	if page.MaxVal != nil {
-		tmpBuf := encoding.WritePlain([]interface{}{page.MaxVal}, *page.Schema.Type)
-		if *page.Schema.Type == parquet.Type_BYTE_ARRAY {
-			tmpBuf = tmpBuf[4:]
-		}
+		tmpBuf := EncodeStatisticsValue(page.MaxVal, page.Schema, page.StatisticsTruncateLength, true)
 		page.Header.DataPageHeader.Statistics.Max = tmpBuf
 		page.Header.DataPageHeader.Statistics.MaxValue = tmpBuf
 	}
 	if page.MinVal != nil {
-		tmpBuf := encoding.WritePlain([]interface{}{page.MinVal}, *page.Schema.Type)
-		if *page.Schema.Type == parquet.Type_BYTE_ARRAY {
-			tmpBuf = tmpBuf[4:]
-		}
+		tmpBuf := EncodeStatisticsValue(page.MinVal, page.Schema, page.StatisticsTruncateLength, false)
 		page.Header.DataPageHeader.Statistics.Min = tmpBuf
 		page.Header.DataPageHeader.Statistics.MinValue = tmpBuf
 	}
 
 	page.Header.DataPageHeader.Statistics.NullCount = page.NullCount
 
+	if page.PageChecksum {
+		crc := PageChecksum(dataEncodeBuf)
+		page.Header.Crc = &crc
+	}
+
 	ts := thrift.NewTSerializer()
 	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
 	pageHeaderBuf, _ := ts.Write(context.TODO(), page.Header)
@@ -0,0 +1,34 @@
+package layout
+
+import (
+	"bytes"
+
+	"github.com/xitongsys/parquet-go/encoding"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//Read the repetition or definition levels of a data page with the typed decoders.
+//The levels are []int32 in Table, so they are decoded without boxing. The values of
+//Table are []interface{} and are still decoded with the interface{} decoders.
+func ReadLevels(bytesReader *bytes.Reader, encodingMethod parquet.Encoding, cnt uint64, bitWidth uint64) ([]int32, error) {
+	if encodingMethod == parquet.Encoding_BIT_PACKED {
+		values, err := encoding.ReadBitPackedDeprecated(bytesReader, cnt, bitWidth)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]int32, len(values))
+		for i, v := range values {
+			res[i] = int32(v.(int64))
+		}
+		return res, nil
+	}
+
+	res, err := encoding.ReadRLEBitPackedHybridInt32(bytesReader, bitWidth, 0)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(res)) > cnt {
+		res = res[:cnt]
+	}
+	return res, nil
+}
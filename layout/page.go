@@ -19,6 +19,8 @@ import (
 type Page struct {
 	//Header of a page
 	Header *parquet.PageHeader
+	//Number of bytes of the header in the file, set when the page is read
+	HeaderSize int64
 	//Table to store values
 	DataTable *Table
 	//Compressed data of the page, which is written in parquet file
@@ -35,10 +37,16 @@ type Page struct {
 	MinVal interface{}
 	//NullCount
 	NullCount *int64
+	//Number of rows starting in the page, set by the writer when DataTable is released
+	NumRows int64
 	//Tag info
 	Info *common.Tag
 
 	PageSize int32
+	//Compute the CRC32 of the page data and store it in the page header
+	PageChecksum bool
+	//Maximum length of BYTE_ARRAY/FIXED_LEN_BYTE_ARRAY statistics, 0 means no truncation
+	StatisticsTruncateLength int32
 }
 
 //Create a new page
@@ -74,6 +82,7 @@ func TableToDataPages(table *Table, pageSize int32, compressType parquet.Compres
 	res := make([]*Page, 0)
 	i := 0
 	pT, cT, logT, omitStats := table.Schema.Type, table.Schema.ConvertedType, table.Schema.LogicalType, table.Info.OmitStats
+	omitMinMax := omitStats || !common.HasDefinedOrder(cT, logT)
 
 	for i < totalLn {
 		j := i
@@ -86,11 +95,12 @@ func TableToDataPages(table *Table, pageSize int32, compressType parquet.Compres
 
 		funcTable := common.FindFuncTable(pT, cT, logT)
 
-		for j < totalLn && size < pageSize {
+		//a page ends at the start of a row, so the rows of the offset index aren't split
+		for j < totalLn && (size < pageSize || !table.rowStart(j)) {
 			if table.DefinitionLevels[j] == table.MaxDefinitionLevel {
 				numValues++
 				var elSize int32
-				if omitStats {
+				if omitMinMax {
 					_, _, elSize = funcTable.MinMaxSize(nil, nil, table.Values[j])
 				} else {
 					minVal, maxVal, elSize = funcTable.MinMaxSize(minVal, maxVal, table.Values[j])
@@ -117,14 +127,18 @@ func TableToDataPages(table *Table, pageSize int32, compressType parquet.Compres
 		page.DataTable.DefinitionLevels = table.DefinitionLevels[i:j]
 		page.DataTable.RepetitionLevels = table.RepetitionLevels[i:j]
 		if !omitStats {
-			page.MaxVal = maxVal
-			page.MinVal = minVal
+			if !omitMinMax {
+				page.MaxVal = maxVal
+				page.MinVal = minVal
+			}
 			page.NullCount = &nullCount
 		}
 		page.Schema = table.Schema
 		page.CompressType = compressType
 		page.Path = table.Path
 		page.Info = table.Info
+		page.StatisticsTruncateLength = table.StatisticsTruncateLength
+		page.PageChecksum = table.PageChecksum
 
 		page.DataPageCompress(compressType)
 
@@ -154,10 +168,18 @@ func (page *Page) Decode(dictPage *Page) {
 		return
 	}
 
+	//the typed values are the int32 indexes
+	if page.DataTable.Typed != nil && dictPage.DataTable.Typed != nil {
+		indexes := page.DataTable.Typed
+		page.DataTable.Typed = dictPage.DataTable.Typed.gather(indexes.Int32, page.DataTable.DefinitionLevels, indexes.MaxDefinitionLevel)
+		return
+	}
+	page.DataTable.BoxValues()
+	dictPage.DataTable.BoxValues()
 	numValues := len(page.DataTable.Values)
 	for i := 0; i < numValues; i++ {
 		if page.DataTable.Values[i] != nil {
-			index := page.DataTable.Values[i].(int64)
+			index := toInt64(page.DataTable.Values[i])
 			page.DataTable.Values[i] = dictPage.DataTable.Values[index]
 		}
 	}
@@ -183,6 +205,9 @@ func (page *Page) EncodingValues(valuesBuf []interface{}) []byte {
 		return encoding.WriteDeltaLengthByteArray(valuesBuf)
 
 	} else if encodingMethod == parquet.Encoding_BYTE_STREAM_SPLIT {
+		if *page.Schema.Type == parquet.Type_FIXED_LEN_BYTE_ARRAY {
+			return encoding.WriteByteStreamSplitFIXED_LEN_BYTE_ARRAY(valuesBuf, page.Schema.GetTypeLength())
+		}
 		return encoding.WriteByteStreamSplit(valuesBuf)
 
 	} else {
@@ -192,28 +217,8 @@ func (page *Page) EncodingValues(valuesBuf []interface{}) []byte {
 
 //Compress the data page to parquet file
 func (page *Page) DataPageCompress(compressType parquet.CompressionCodec) []byte {
-	ln := len(page.DataTable.DefinitionLevels)
-
 	//values////////////////////////////////////////////
-	// valuesBuf == nil means "up to i, every item in DefinitionLevels was
-	// MaxDefinitionLevel". This lets us avoid allocating the array for the
-	// (somewhat) common case of "all values present".
-	var valuesBuf []interface{}
-	for i := 0; i < ln; i++ {
-		if page.DataTable.DefinitionLevels[i] == page.DataTable.MaxDefinitionLevel {
-			if valuesBuf != nil {
-				valuesBuf = append(valuesBuf, page.DataTable.Values[i])
-			}
-		} else if valuesBuf == nil {
-			valuesBuf = make([]interface{}, i, ln)
-			copy(valuesBuf[:i], page.DataTable.Values[:i])
-		}
-	}
-	if valuesBuf == nil {
-		valuesBuf = page.DataTable.Values
-	}
-	//valuesRawBuf := encoding.WritePlain(valuesBuf)
-	valuesRawBuf := page.EncodingValues(valuesBuf)
+	valuesRawBuf := page.encodingTypedValues(definedTypedValues(page.DataTable, *page.Schema.Type))
 
 	//definitionLevel//////////////////////////////////
 	var definitionLevelBuf []byte
@@ -252,24 +257,23 @@ func (page *Page) DataPageCompress(compressType parquet.CompressionCodec) []byte
 
 	page.Header.DataPageHeader.Statistics = parquet.NewStatistics()
 	if page.MaxVal != nil {
-		tmpBuf := encoding.WritePlain([]interface{}{page.MaxVal}, *page.Schema.Type)
-		if *page.Schema.Type == parquet.Type_BYTE_ARRAY {
-			tmpBuf = tmpBuf[4:]
-		}
+		tmpBuf := EncodeStatisticsValue(page.MaxVal, page.Schema, page.StatisticsTruncateLength, true)
 		page.Header.DataPageHeader.Statistics.Max = tmpBuf
 		page.Header.DataPageHeader.Statistics.MaxValue = tmpBuf
 	}
 	if page.MinVal != nil {
-		tmpBuf := encoding.WritePlain([]interface{}{page.MinVal}, *page.Schema.Type)
-		if *page.Schema.Type == parquet.Type_BYTE_ARRAY {
-			tmpBuf = tmpBuf[4:]
-		}
+		tmpBuf := EncodeStatisticsValue(page.MinVal, page.Schema, page.StatisticsTruncateLength, false)
 		page.Header.DataPageHeader.Statistics.Min = tmpBuf
 		page.Header.DataPageHeader.Statistics.MinValue = tmpBuf
 	}
 
 	page.Header.DataPageHeader.Statistics.NullCount = page.NullCount
 
+	if page.PageChecksum {
+		crc := PageChecksum(dataEncodeBuf)
+		page.Header.Crc = &crc
+	}
+
 	ts := thrift.NewTSerializer()
 	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
 	pageHeaderBuf, _ := ts.Write(context.TODO(), page.Header)
@@ -285,14 +289,8 @@ func (page *Page) DataPageV2Compress(compressType parquet.CompressionCodec) []by
 	ln := len(page.DataTable.DefinitionLevels)
 
 	//values////////////////////////////////////////////
-	valuesBuf := make([]interface{}, 0)
-	for i := 0; i < ln; i++ {
-		if page.DataTable.DefinitionLevels[i] == page.DataTable.MaxDefinitionLevel {
-			valuesBuf = append(valuesBuf, page.DataTable.Values[i])
-		}
-	}
-	//valuesRawBuf := encoding.WritePlain(valuesBuf)
-	valuesRawBuf := page.EncodingValues(valuesBuf)
+	values := definedTypedValues(page.DataTable, *page.Schema.Type)
+	valuesRawBuf := page.encodingTypedValues(values)
 
 	//definitionLevel//////////////////////////////////
 	var definitionLevelBuf []byte
@@ -330,8 +328,8 @@ func (page *Page) DataPageV2Compress(compressType parquet.CompressionCodec) []by
 	page.Header.CompressedPageSize = int32(len(dataEncodeBuf) + len(definitionLevelBuf) + len(repetitionLevelBuf))
 	page.Header.UncompressedPageSize = int32(len(valuesRawBuf) + len(definitionLevelBuf) + len(repetitionLevelBuf))
 	page.Header.DataPageHeaderV2 = parquet.NewDataPageHeaderV2()
-	page.Header.DataPageHeaderV2.NumValues = int32(len(page.DataTable.Values))
-	page.Header.DataPageHeaderV2.NumNulls = page.Header.DataPageHeaderV2.NumValues - int32(len(valuesBuf))
+	page.Header.DataPageHeaderV2.NumValues = int32(ln)
+	page.Header.DataPageHeaderV2.NumNulls = page.Header.DataPageHeaderV2.NumValues - int32(values.Len())
 	page.Header.DataPageHeaderV2.NumRows = r0Num
 	//page.Header.DataPageHeaderV2.Encoding = parquet.Encoding_PLAIN
 	page.Header.DataPageHeaderV2.Encoding = page.Info.Encoding
@@ -342,24 +340,27 @@ func (page *Page) DataPageV2Compress(compressType parquet.CompressionCodec) []by
 
 	page.Header.DataPageHeaderV2.Statistics = parquet.NewStatistics()
 	if page.MaxVal != nil {
-		tmpBuf := encoding.WritePlain([]interface{}{page.MaxVal}, *page.Schema.Type)
-		if *page.Schema.Type == parquet.Type_BYTE_ARRAY {
-			tmpBuf = tmpBuf[4:]
-		}
+		tmpBuf := EncodeStatisticsValue(page.MaxVal, page.Schema, page.StatisticsTruncateLength, true)
 		page.Header.DataPageHeaderV2.Statistics.Max = tmpBuf
 		page.Header.DataPageHeaderV2.Statistics.MaxValue = tmpBuf
 	}
 	if page.MinVal != nil {
-		tmpBuf := encoding.WritePlain([]interface{}{page.MinVal}, *page.Schema.Type)
-		if *page.Schema.Type == parquet.Type_BYTE_ARRAY {
-			tmpBuf = tmpBuf[4:]
-		}
+		tmpBuf := EncodeStatisticsValue(page.MinVal, page.Schema, page.StatisticsTruncateLength, false)
 		page.Header.DataPageHeaderV2.Statistics.Min = tmpBuf
 		page.Header.DataPageHeaderV2.Statistics.MinValue = tmpBuf
 	}
 
 	page.Header.DataPageHeaderV2.Statistics.NullCount = page.NullCount
 
+	if page.PageChecksum {
+		crcBuf := make([]byte, 0, len(repetitionLevelBuf)+len(definitionLevelBuf)+len(dataEncodeBuf))
+		crcBuf = append(crcBuf, repetitionLevelBuf...)
+		crcBuf = append(crcBuf, definitionLevelBuf...)
+		crcBuf = append(crcBuf, dataEncodeBuf...)
+		crc := PageChecksum(crcBuf)
+		page.Header.Crc = &crc
+	}
+
 	ts := thrift.NewTSerializer()
 	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
 	pageHeaderBuf, _ := ts.Write(context.TODO(), page.Header)
@@ -397,7 +398,7 @@ func ReadPageRawData(thriftReader *thrift.TBufferedTransport, schemaHandler *sch
 		err error
 	)
 
-	pageHeader, err := ReadPageHeader(thriftReader)
+	pageHeader, headerSize, err := readPageHeader(thriftReader)
 	if err != nil {
 		return nil, err
 	}
@@ -418,6 +419,7 @@ func ReadPageRawData(thriftReader *thrift.TBufferedTransport, schemaHandler *sch
 	}
 
 	page.Header = pageHeader
+	page.HeaderSize = headerSize
 	page.CompressType = colMetaData.GetCodec()
 	page.RawData = buf
 	page.Path = make([]string, 0)
@@ -476,49 +478,23 @@ func (p *Page) GetRLDLFromRawData(schemaHandler *schema.SchemaHandler) (int64, i
 
 		maxDefinitionLevel, _ := schemaHandler.MaxDefinitionLevel(p.Path)
 		maxRepetitionLevel, _ := schemaHandler.MaxRepetitionLevel(p.Path)
+		rlEncoding, dlEncoding := levelEncodings(p.Header)
 
-		var repetitionLevels, definitionLevels []interface{}
+		repetitionLevels, definitionLevels := make([]int32, numValues), make([]int32, numValues)
 		if maxRepetitionLevel > 0 {
 			bitWidth := uint64(bits.Len32(uint32(maxRepetitionLevel)))
-			if repetitionLevels, err = ReadDataPageValues(bytesReader,
-				parquet.Encoding_RLE,
-				parquet.Type_INT64,
-				-1,
-				numValues,
-				bitWidth); err != nil {
+			if repetitionLevels, err = ReadLevels(bytesReader, rlEncoding, numValues, bitWidth); err != nil {
 				return 0, 0, err
 			}
-		} else {
-			repetitionLevels = make([]interface{}, numValues)
-			for i := 0; i < len(repetitionLevels); i++ {
-				repetitionLevels[i] = int64(0)
-			}
 		}
-		if len(repetitionLevels) > int(numValues) {
-			repetitionLevels = repetitionLevels[:numValues]
-		}
-
 		if maxDefinitionLevel > 0 {
 			bitWidth := uint64(bits.Len32(uint32(maxDefinitionLevel)))
-
-			definitionLevels, err = ReadDataPageValues(bytesReader,
-				parquet.Encoding_RLE,
-				parquet.Type_INT64,
-				-1,
-				numValues,
-				bitWidth)
-			if err != nil {
+			if definitionLevels, err = ReadLevels(bytesReader, dlEncoding, numValues, bitWidth); err != nil {
 				return 0, 0, err
 			}
-
-		} else {
-			definitionLevels = make([]interface{}, numValues)
-			for i := 0; i < len(definitionLevels); i++ {
-				definitionLevels[i] = int64(0)
-			}
 		}
-		if len(definitionLevels) > int(numValues) {
-			definitionLevels = definitionLevels[:numValues]
+		if len(repetitionLevels) < len(definitionLevels) {
+			return 0, 0, fmt.Errorf("expect %v repetition levels, get %v", len(definitionLevels), len(repetitionLevels))
 		}
 
 		table := new(Table)
@@ -527,16 +503,15 @@ func (p *Page) GetRLDLFromRawData(schemaHandler *schema.SchemaHandler) (int64, i
 		table.RepetitionType = schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].GetRepetitionType()
 		table.MaxRepetitionLevel = maxRepetitionLevel
 		table.MaxDefinitionLevel = maxDefinitionLevel
-		table.Values = make([]interface{}, len(definitionLevels))
+		table.Typed = NewTypedValues(*p.Schema.Type, maxDefinitionLevel)
+		table.Typed.appendZeros(len(definitionLevels))
 		table.RepetitionLevels = make([]int32, len(definitionLevels))
 		table.DefinitionLevels = make([]int32, len(definitionLevels))
 
 		numRows := int64(0)
 		for i := 0; i < len(definitionLevels); i++ {
-			dl, _ := definitionLevels[i].(int64)
-			rl, _ := repetitionLevels[i].(int64)
-			table.RepetitionLevels[i] = int32(rl)
-			table.DefinitionLevels[i] = int32(dl)
+			table.RepetitionLevels[i] = repetitionLevels[i]
+			table.DefinitionLevels[i] = definitionLevels[i]
 			if table.RepetitionLevels[i] == 0 {
 				numRows++
 			}
@@ -566,10 +541,10 @@ func (p *Page) GetValueFromRawData(schemaHandler *schema.SchemaHandler) error {
 	switch p.Header.GetType() {
 	case parquet.PageType_DICTIONARY_PAGE:
 		bytesReader := bytes.NewReader(p.RawData)
-		p.DataTable.Values, err = encoding.ReadPlain(bytesReader,
+		p.DataTable.Typed, err = readPlainTyped(bytesReader,
 			*p.Schema.Type,
 			uint64(p.Header.DictionaryPageHeader.GetNumValues()),
-			0)
+			uint64(p.Schema.GetTypeLength()))
 		if err != nil {
 			return err
 		}
@@ -590,28 +565,17 @@ func (p *Page) GetValueFromRawData(schemaHandler *schema.SchemaHandler) error {
 			}
 		}
 		name := common.PathToStr(p.DataTable.Path)
-		var values []interface{}
-		var ct parquet.ConvertedType = -1
-		if schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].IsSetConvertedType() {
-			ct = schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].GetConvertedType()
-		}
-
-		values, err = ReadDataPageValues(bytesReader,
+		values, err := ReadDataPageValuesTyped(bytesReader,
 			encodingType,
 			*p.Schema.Type,
-			ct,
 			uint64(len(p.DataTable.DefinitionLevels))-numNulls,
 			uint64(schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].GetTypeLength()))
 		if err != nil {
 			return err
 		}
-		j := 0
-		for i := 0; i < len(p.DataTable.DefinitionLevels); i++ {
-			if p.DataTable.DefinitionLevels[i] == p.DataTable.MaxDefinitionLevel {
-				p.DataTable.Values[i] = values[j]
-				j++
-			}
-		}
+		values.MaxDefinitionLevel = p.DataTable.MaxDefinitionLevel
+		values.spread(p.DataTable.DefinitionLevels)
+		p.DataTable.Typed, p.DataTable.Values = values, nil
 		p.RawData = []byte{}
 		return nil
 
@@ -621,12 +585,54 @@ func (p *Page) GetValueFromRawData(schemaHandler *schema.SchemaHandler) error {
 	return nil
 }
 
+//Get the encodings of repetition and definition levels.
+//Levels of data page v2 are always RLE, data page v1 may use the deprecated BIT_PACKED.
+func levelEncodings(pageHeader *parquet.PageHeader) (parquet.Encoding, parquet.Encoding) {
+	rlEncoding, dlEncoding := parquet.Encoding_RLE, parquet.Encoding_RLE
+	if pageHeader.GetType() == parquet.PageType_DATA_PAGE && pageHeader.DataPageHeader != nil {
+		if pageHeader.DataPageHeader.GetRepetitionLevelEncoding() == parquet.Encoding_BIT_PACKED {
+			rlEncoding = parquet.Encoding_BIT_PACKED
+		}
+		if pageHeader.DataPageHeader.GetDefinitionLevelEncoding() == parquet.Encoding_BIT_PACKED {
+			dlEncoding = parquet.Encoding_BIT_PACKED
+		}
+	}
+	return rlEncoding, dlEncoding
+}
+
 //Read page header
 func ReadPageHeader(thriftReader *thrift.TBufferedTransport) (*parquet.PageHeader, error) {
-	protocol := thrift.NewTCompactProtocol(thriftReader)
+	pageHeader, _, err := readPageHeader(thriftReader)
+	return pageHeader, err
+}
+
+//Read the page header and return the number of bytes it takes in the file
+func readPageHeader(thriftReader *thrift.TBufferedTransport) (*parquet.PageHeader, int64, error) {
+	counter := &countingTransport{TBufferedTransport: thriftReader}
+	protocol := thrift.NewTCompactProtocol(counter)
 	pageHeader := parquet.NewPageHeader()
 	err := pageHeader.Read(context.TODO(), protocol)
-	return pageHeader, err
+	return pageHeader, counter.n, err
+}
+
+//countingTransport counts the bytes read from a buffered transport
+type countingTransport struct {
+	*thrift.TBufferedTransport
+	n int64
+}
+
+func (t *countingTransport) Read(b []byte) (int, error) {
+	n, err := t.TBufferedTransport.Read(b)
+	t.n += int64(n)
+	return n, err
+}
+
+func (t *countingTransport) ReadByte() (byte, error) {
+	b, err := t.TBufferedTransport.ReadByte()
+	if err == nil {
+		t.n++
+	}
+	return b, err
 }
 
 //Read data page values
@@ -652,7 +658,7 @@ func ReadDataPageValues(bytesReader *bytes.Reader, encodingMethod parquet.Encodi
 		if err != nil {
 			return res, err
 		}
-		return buf[:cnt], err
+		return buf[:min(cnt, uint64(len(buf)))], err
 
 	} else if encodingMethod == parquet.Encoding_RLE {
 		values, err := encoding.ReadRLEBitPackedHybrid(bytesReader, bitWidth, 0)
@@ -664,11 +670,27 @@ func ReadDataPageValues(bytesReader *bytes.Reader, encodingMethod parquet.Encodi
 				values[i] = int32(values[i].(int64))
 			}
 		}
-		return values[:cnt], nil
+		return values[:min(cnt, uint64(len(values)))], nil
 
 	} else if encodingMethod == parquet.Encoding_BIT_PACKED {
 		//deprecated
-		return res, fmt.Errorf("Unsupported Encoding method BIT_PACKED")
+		if dataType == parquet.Type_BOOLEAN {
+			bitWidth = 1
+		}
+		values, err := encoding.ReadBitPackedDeprecated(bytesReader, cnt, bitWidth)
+		if err != nil {
+			return res, err
+		}
+		if dataType == parquet.Type_INT32 {
+			for i := 0; i < len(values); i++ {
+				values[i] = int32(values[i].(int64))
+			}
+		} else if dataType == parquet.Type_BOOLEAN {
+			for i := 0; i < len(values); i++ {
+				values[i] = values[i].(int64) == 1
+			}
+		}
+		return values, nil
 
 	} else if encodingMethod == parquet.Encoding_DELTA_BINARY_PACKED {
 
@@ -686,31 +708,28 @@ func ReadDataPageValues(bytesReader *bytes.Reader, encodingMethod parquet.Encodi
 		if err != nil {
 			return res, err
 		}
-		if dataType == parquet.Type_FIXED_LEN_BYTE_ARRAY {
-			for i := 0; i < len(values); i++ {
-				values[i] = values[i].(string)
-			}
-		}
-		return values[:cnt], nil
+		return values[:min(cnt, uint64(len(values)))], nil
 
 	} else if encodingMethod == parquet.Encoding_DELTA_BYTE_ARRAY {
 		values, err := encoding.ReadDeltaByteArray(bytesReader)
 		if err != nil {
 			return res, err
 		}
-		if dataType == parquet.Type_FIXED_LEN_BYTE_ARRAY {
-			for i := 0; i < len(values); i++ {
-				values[i] = values[i].(string)
-			}
-		}
-		return values[:cnt], nil
+		return values[:min(cnt, uint64(len(values)))], nil
 	} else if encodingMethod == parquet.Encoding_BYTE_STREAM_SPLIT {
-		if dataType == parquet.Type_FLOAT {
+		switch dataType {
+		case parquet.Type_FLOAT:
 			return encoding.ReadByteStreamSplitFloat32(bytesReader, cnt)
-		} else if dataType == parquet.Type_DOUBLE {
+		case parquet.Type_DOUBLE:
 			return encoding.ReadByteStreamSplitFloat64(bytesReader, cnt)
+		case parquet.Type_INT32:
+			return encoding.ReadByteStreamSplitINT32(bytesReader, cnt)
+		case parquet.Type_INT64:
+			return encoding.ReadByteStreamSplitINT64(bytesReader, cnt)
+		case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+			return encoding.ReadByteStreamSplitFIXED_LEN_BYTE_ARRAY(bytesReader, cnt, bitWidth)
 		}
-		return res, fmt.Errorf("The encoding method BYTE_STREAM_SPLIT can only be used with Float and double types")
+		return res, fmt.Errorf("The encoding method BYTE_STREAM_SPLIT can only be used with FLOAT, DOUBLE, INT32, INT64 and FIXED_LEN_BYTE_ARRAY types")
 
 	} else {
 		return res, fmt.Errorf("Unknown Encoding method")
@@ -719,15 +738,26 @@ func ReadDataPageValues(bytesReader *bytes.Reader, encodingMethod parquet.Encodi
 
 //Read page from parquet file
 func ReadPage(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.SchemaHandler, colMetaData *parquet.ColumnMetaData) (*Page, int64, int64, error) {
+	return ReadPageWithChecksum(thriftReader, schemaHandler, colMetaData, false)
+}
+
+//Read page from parquet file and verify the crc in the page header if verifyChecksum is true.
+//A mismatch is reported as *PageChecksumError.
+func ReadPageWithChecksum(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.SchemaHandler, colMetaData *parquet.ColumnMetaData, verifyChecksum bool) (*Page, int64, int64, error) {
 	var (
 		err error
 	)
 
-	pageHeader, err := ReadPageHeader(thriftReader)
+	pageHeader, headerSize, err := readPageHeader(thriftReader)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
+	path := make([]string, 0)
+	path = append(path, schemaHandler.GetRootInName())
+	path = append(path, colMetaData.GetPathInSchema()...)
+	name := common.PathToStr(path)
+
 	buf := make([]byte, 0)
 
 	var page *Page
@@ -750,6 +780,16 @@ func ReadPage(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.Sch
 			return nil, 0, 0, err
 		}
 
+		if verifyChecksum {
+			crcBuf := make([]byte, 0, compressedPageSize)
+			crcBuf = append(crcBuf, repetitionLevelsBuf...)
+			crcBuf = append(crcBuf, definitionLevelsBuf...)
+			crcBuf = append(crcBuf, dataBuf...)
+			if err = VerifyPageChecksum(pageHeader, crcBuf, name); err != nil {
+				return nil, 0, 0, err
+			}
+		}
+
 		codec := colMetaData.GetCodec()
 		if len(dataBuf) > 0 {
 			if dataBuf, err = compress.Uncompress(dataBuf, codec); err != nil {
@@ -777,6 +817,11 @@ func ReadPage(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.Sch
 		if _, err = io.ReadFull(thriftReader, buf); err != nil {
 			return nil, 0, 0, err
 		}
+		if verifyChecksum {
+			if err = VerifyPageChecksum(pageHeader, buf, name); err != nil {
+				return nil, 0, 0, err
+			}
+		}
 		codec := colMetaData.GetCodec()
 		if buf, err = compress.Uncompress(buf, codec); err != nil {
 			return nil, 0, 0, err
@@ -784,14 +829,11 @@ func ReadPage(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.Sch
 	}
 
 	bytesReader := bytes.NewReader(buf)
-	path := make([]string, 0)
-	path = append(path, schemaHandler.GetRootInName())
-	path = append(path, colMetaData.GetPathInSchema()...)
-	name := common.PathToStr(path)
 
 	if pageHeader.GetType() == parquet.PageType_DICTIONARY_PAGE {
 		page = NewDictPage()
 		page.Header = pageHeader
+		page.HeaderSize = headerSize
 		table := new(Table)
 		table.Path = path
 		bitWidth, idx := 0, schemaHandler.MapIndex[name]
@@ -799,7 +841,7 @@ func ReadPage(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.Sch
 			bitWidth = int(schemaHandler.SchemaElements[idx].GetTypeLength())
 		}
 
-		table.Values, err = encoding.ReadPlain(bytesReader,
+		table.Typed, err = readPlainTyped(bytesReader,
 			colMetaData.GetType(),
 			uint64(pageHeader.DictionaryPageHeader.GetNumValues()),
 			uint64(bitWidth))
@@ -818,8 +860,10 @@ func ReadPage(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.Sch
 
 		page = NewDataPage()
 		page.Header = pageHeader
+		page.HeaderSize = headerSize
 		maxDefinitionLevel, _ := schemaHandler.MaxDefinitionLevel(path)
 		maxRepetitionLevel, _ := schemaHandler.MaxRepetitionLevel(path)
+		rlEncoding, dlEncoding := levelEncodings(pageHeader)
 
 		var numValues uint64
 		var encodingType parquet.Encoding
@@ -832,97 +876,53 @@ func ReadPage(thriftReader *thrift.TBufferedTransport, schemaHandler *schema.Sch
 			encodingType = pageHeader.DataPageHeaderV2.GetEncoding()
 		}
 
-		var repetitionLevels []interface{}
+		repetitionLevels, definitionLevels := make([]int32, numValues), make([]int32, numValues)
 		if maxRepetitionLevel > 0 {
 			bitWidth := uint64(bits.Len32(uint32(maxRepetitionLevel)))
-
-			repetitionLevels, err = ReadDataPageValues(bytesReader,
-				parquet.Encoding_RLE,
-				parquet.Type_INT64,
-				-1,
-				numValues,
-				bitWidth)
-			if err != nil {
+			if repetitionLevels, err = ReadLevels(bytesReader, rlEncoding, numValues, bitWidth); err != nil {
 				return nil, 0, 0, err
 			}
-
-		} else {
-			repetitionLevels = make([]interface{}, numValues)
-			for i := 0; i < len(repetitionLevels); i++ {
-				repetitionLevels[i] = int64(0)
-			}
 		}
-		if len(repetitionLevels) > int(numValues) {
-			repetitionLevels = repetitionLevels[:numValues]
-		}
-
-		var definitionLevels []interface{}
 		if maxDefinitionLevel > 0 {
 			bitWidth := uint64(bits.Len32(uint32(maxDefinitionLevel)))
-
-			definitionLevels, err = ReadDataPageValues(bytesReader,
-				parquet.Encoding_RLE,
-				parquet.Type_INT64,
-				-1,
-				numValues,
-				bitWidth)
-			if err != nil {
+			if definitionLevels, err = ReadLevels(bytesReader, dlEncoding, numValues, bitWidth); err != nil {
 				return nil, 0, 0, err
 			}
-
-		} else {
-			definitionLevels = make([]interface{}, numValues)
-			for i := 0; i < len(definitionLevels); i++ {
-				definitionLevels[i] = int64(0)
-			}
 		}
-		if len(definitionLevels) > int(numValues) {
-			definitionLevels = definitionLevels[:numValues]
+		if len(repetitionLevels) < len(definitionLevels) {
+			return nil, 0, 0, fmt.Errorf("expect %v repetition levels, get %v", len(definitionLevels), len(repetitionLevels))
 		}
 
 		var numNulls uint64 = 0
 		for i := 0; i < len(definitionLevels); i++ {
-			if int32(definitionLevels[i].(int64)) != maxDefinitionLevel {
+			if definitionLevels[i] != maxDefinitionLevel {
 				numNulls++
 			}
 		}
 
-		var values []interface{}
-		var ct parquet.ConvertedType = -1
-		if schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].IsSetConvertedType() {
-			ct = schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].GetConvertedType()
-		}
-		values, err = ReadDataPageValues(bytesReader,
+		values, err := ReadDataPageValuesTyped(bytesReader,
 			encodingType,
 			colMetaData.GetType(),
-			ct,
 			uint64(len(definitionLevels))-numNulls,
 			uint64(schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].GetTypeLength()))
 		if err != nil {
 			return nil, 0, 0, err
 		}
+		values.MaxDefinitionLevel = maxDefinitionLevel
+		values.spread(definitionLevels)
 
 		table := new(Table)
 		table.Path = path
 		table.RepetitionType = schemaHandler.SchemaElements[schemaHandler.MapIndex[name]].GetRepetitionType()
 		table.MaxRepetitionLevel = maxRepetitionLevel
 		table.MaxDefinitionLevel = maxDefinitionLevel
-		table.Values = make([]interface{}, len(definitionLevels))
-		table.RepetitionLevels = make([]int32, len(definitionLevels))
-		table.DefinitionLevels = make([]int32, len(definitionLevels))
+		table.Typed = values
+		table.RepetitionLevels = repetitionLevels[:len(definitionLevels)]
+		table.DefinitionLevels = definitionLevels
 
-		j := 0
 		numRows := int64(0)
-		for i := 0; i < len(definitionLevels); i++ {
-			dl, _ := definitionLevels[i].(int64)
-			rl, _ := repetitionLevels[i].(int64)
-			table.RepetitionLevels[i] = int32(rl)
-			table.DefinitionLevels[i] = int32(dl)
-			if table.DefinitionLevels[i] == maxDefinitionLevel {
-				table.Values[i] = values[j]
-				j++
-			}
-			if table.RepetitionLevels[i] == 0 {
+		for _, rl := range table.RepetitionLevels {
+			if rl == 0 {
 				numRows++
 			}
 		}
@@ -0,0 +1,267 @@
+package layout
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/encoding"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+func TestReadPageBitPackedLevels(t *testing.T) {
+	type Entry struct {
+		A *int32 `parquet:"name=a, type=INT32, repetitiontype=OPTIONAL"`
+	}
+	schemaHandler, err := schema.NewSchemaHandlerFromStruct(new(Entry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	definitionLevels := []interface{}{int64(1), int64(0), int64(1), int64(1), int64(0), int64(1), int64(1), int64(1)}
+	dataBuf := encoding.WriteBitPackedDeprecated(definitionLevels, 1)
+	dataBuf = append(dataBuf, encoding.WritePlainINT32([]interface{}{int32(1), int32(2), int32(3), int32(4), int32(5), int32(6)})...)
+
+	header := parquet.NewPageHeader()
+	header.Type = parquet.PageType_DATA_PAGE
+	header.CompressedPageSize = int32(len(dataBuf))
+	header.UncompressedPageSize = int32(len(dataBuf))
+	header.DataPageHeader = parquet.NewDataPageHeader()
+	header.DataPageHeader.NumValues = int32(len(definitionLevels))
+	header.DataPageHeader.Encoding = parquet.Encoding_PLAIN
+	header.DataPageHeader.DefinitionLevelEncoding = parquet.Encoding_BIT_PACKED
+	header.DataPageHeader.RepetitionLevelEncoding = parquet.Encoding_BIT_PACKED
+
+	ts := thrift.NewTSerializer()
+	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
+	headerBuf, err := ts.Write(context.TODO(), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colMetaData := parquet.NewColumnMetaData()
+	colMetaData.Type = parquet.Type_INT32
+	colMetaData.Codec = parquet.CompressionCodec_UNCOMPRESSED
+	colMetaData.PathInSchema = []string{"A"}
+
+	newReader := func() *thrift.TBufferedTransport {
+		buf := append(append([]byte{}, headerBuf...), dataBuf...)
+		return thrift.NewTBufferedTransport(thrift.NewStreamTransportR(bytes.NewReader(buf)), 4096)
+	}
+
+	page, numValues, numRows, err := ReadPage(newReader(), schemaHandler, colMetaData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numValues != 8 || numRows != 8 {
+		t.Errorf("ReadPage err, expect 8 values and 8 rows, get %v values and %v rows", numValues, numRows)
+	}
+	if fmt.Sprintf("%v", page.DataTable.DefinitionLevels) != "[1 0 1 1 0 1 1 1]" {
+		t.Errorf("ReadPage err, expect definition levels [1 0 1 1 0 1 1 1], get %v", page.DataTable.DefinitionLevels)
+	}
+	if fmt.Sprintf("%v", page.DataTable.Typed.Int32) != "[1 0 2 3 0 4 5 6]" {
+		t.Errorf("ReadPage err, expect typed values [1 0 2 3 0 4 5 6], get %v", page.DataTable.Typed.Int32)
+	}
+	page.DataTable.BoxValues()
+	if fmt.Sprintf("%v", page.DataTable.Values) != "[1 <nil> 2 3 <nil> 4 5 6]" {
+		t.Errorf("ReadPage err, expect values [1 <nil> 2 3 <nil> 4 5 6], get %v", page.DataTable.Values)
+	}
+
+	page, err = ReadPageRawData(newReader(), schemaHandler, colMetaData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = page.GetRLDLFromRawData(schemaHandler); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprintf("%v", page.DataTable.DefinitionLevels) != "[1 0 1 1 0 1 1 1]" {
+		t.Errorf("GetRLDLFromRawData err, expect definition levels [1 0 1 1 0 1 1 1], get %v", page.DataTable.DefinitionLevels)
+	}
+}
+
+func TestReadPageHeaderSize(t *testing.T) {
+	type Entry struct {
+		A int32 `parquet:"name=a, type=INT32"`
+	}
+	schemaHandler, err := schema.NewSchemaHandlerFromStruct(new(Entry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataBuf := encoding.WritePlainINT32([]interface{}{int32(1), int32(2)})
+	header := parquet.NewPageHeader()
+	header.Type = parquet.PageType_DATA_PAGE
+	header.CompressedPageSize = int32(len(dataBuf))
+	header.UncompressedPageSize = int32(len(dataBuf))
+	header.DataPageHeader = parquet.NewDataPageHeader()
+	header.DataPageHeader.NumValues = 2
+	header.DataPageHeader.Encoding = parquet.Encoding_PLAIN
+
+	ts := thrift.NewTSerializer()
+	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
+	headerBuf, err := ts.Write(context.TODO(), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a header of another writer with an unknown binary field 100 before the stop field
+	otherHeaderBuf := append(append([]byte{}, headerBuf[:len(headerBuf)-1]...), 0x08, 0xc8, 0x01, 0x01, 'x', 0x00)
+
+	colMetaData := parquet.NewColumnMetaData()
+	colMetaData.Type = parquet.Type_INT32
+	colMetaData.Codec = parquet.CompressionCodec_UNCOMPRESSED
+	colMetaData.PathInSchema = []string{"A"}
+
+	for _, buf := range [][]byte{headerBuf, otherHeaderBuf} {
+		file := append(append(append([]byte{}, buf...), dataBuf...), headerBuf...)
+		file = append(file, dataBuf...)
+		thriftReader := thrift.NewTBufferedTransport(thrift.NewStreamTransportR(bytes.NewReader(file)), 4096)
+
+		page, err := ReadPageRawData(thriftReader, schemaHandler, colMetaData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.HeaderSize != int64(len(buf)) {
+			t.Errorf("ReadPageRawData err, expect header size %v, get %v", len(buf), page.HeaderSize)
+		}
+		page, _, _, err = ReadPage(thriftReader, schemaHandler, colMetaData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.HeaderSize != int64(len(headerBuf)) {
+			t.Errorf("ReadPage err, expect header size %v, get %v", len(headerBuf), page.HeaderSize)
+		}
+	}
+}
+
+func benchmarkTable(b *testing.B, tag string, values []interface{}) *Table {
+	info, err := common.StringToTag(tag)
+	if err != nil {
+		b.Fatal(err)
+	}
+	schemaElement, err := common.NewSchemaElementFromTagMap(info)
+	if err != nil {
+		b.Fatal(err)
+	}
+	table := NewEmptyTable()
+	table.Schema = schemaElement
+	table.Info = info
+	table.Path = []string{"Parquet_go_root", info.InName}
+	table.Values = values
+	table.DefinitionLevels = make([]int32, len(values))
+	table.RepetitionLevels = make([]int32, len(values))
+	return table
+}
+
+func benchmarkInt64Values() []interface{} {
+	res := make([]interface{}, 10000)
+	for i := range res {
+		res[i] = int64(i) * 1000
+	}
+	return res
+}
+
+func benchmarkStringValues() []interface{} {
+	res := make([]interface{}, 10000)
+	for i := range res {
+		res[i] = fmt.Sprintf("value-%08d", i)
+	}
+	return res
+}
+
+var benchmarkPageCases = []struct {
+	name   string
+	tag    string
+	values func() []interface{}
+}{
+	{"PlainInt64", "name=a, type=INT64", benchmarkInt64Values},
+	{"DeltaInt64", "name=a, type=INT64, encoding=DELTA_BINARY_PACKED", benchmarkInt64Values},
+	{"PlainByteArray", "name=a, type=BYTE_ARRAY", benchmarkStringValues},
+	{"DeltaByteArray", "name=a, type=BYTE_ARRAY, encoding=DELTA_BYTE_ARRAY", benchmarkStringValues},
+}
+
+func BenchmarkTableToDataPages(b *testing.B) {
+	for _, c := range benchmarkPageCases {
+		b.Run(c.name, func(b *testing.B) {
+			table := benchmarkTable(b, c.tag, c.values())
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				TableToDataPages(table, 1024*1024, parquet.CompressionCodec_UNCOMPRESSED)
+			}
+		})
+	}
+}
+
+func BenchmarkReadPage(b *testing.B) {
+	for _, c := range benchmarkPageCases {
+		b.Run(c.name, func(b *testing.B) {
+			table := benchmarkTable(b, c.tag, c.values())
+			pages, _ := TableToDataPages(table, 1024*1024, parquet.CompressionCodec_UNCOMPRESSED)
+			numChildren := int32(1)
+			schemaHandler := schema.NewSchemaHandlerFromSchemaList([]*parquet.SchemaElement{
+				{Name: "Parquet_go_root", NumChildren: &numChildren}, table.Schema,
+			})
+			colMetaData := parquet.NewColumnMetaData()
+			colMetaData.Type = *table.Schema.Type
+			colMetaData.Codec = parquet.CompressionCodec_UNCOMPRESSED
+			colMetaData.PathInSchema = []string{table.Schema.Name}
+
+			//typed reads the typed values of the page, boxed converts them to the interface values as well
+			for _, boxed := range []bool{false, true} {
+				name := "typed"
+				if boxed {
+					name = "boxed"
+				}
+				b.Run(name, func(b *testing.B) {
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						thriftReader := thrift.NewTBufferedTransport(thrift.NewStreamTransportR(bytes.NewReader(pages[0].RawData)), 4096)
+						page, _, _, err := ReadPage(thriftReader, schemaHandler, colMetaData)
+						if err != nil {
+							b.Fatal(err)
+						}
+						if boxed {
+							page.DataTable.BoxValues()
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestTableToDataPagesRowStarts(t *testing.T) {
+	schemaElement := parquet.NewSchemaElement()
+	schemaElement.Type = parquet.TypePtr(parquet.Type_INT32)
+	schemaElement.Name = "Element"
+	table := &Table{
+		RepetitionType:     parquet.FieldRepetitionType_REPEATED,
+		Schema:             schemaElement,
+		Path:               []string{"Parquet_go_root", "List", "Element"},
+		MaxDefinitionLevel: 1,
+		MaxRepetitionLevel: 1,
+		Info:               common.NewTag(),
+	}
+	//rows of 3, 1 and 4 values
+	for i, rl := range []int32{0, 1, 1, 0, 0, 1, 1, 1} {
+		table.Values = append(table.Values, int32(i))
+		table.DefinitionLevels = append(table.DefinitionLevels, 1)
+		table.RepetitionLevels = append(table.RepetitionLevels, rl)
+	}
+
+	//each page is full after its first value, but ends at the next row
+	pages, _ := TableToDataPages(table, 1, parquet.CompressionCodec_UNCOMPRESSED)
+	numValues := []int{}
+	for _, page := range pages {
+		if page.DataTable.RepetitionLevels[0] != 0 {
+			t.Errorf("page starts in a row: %v", page.DataTable.RepetitionLevels)
+		}
+		numValues = append(numValues, len(page.DataTable.Values))
+	}
+	if fmt.Sprintf("%v", numValues) != "[3 1 4]" {
+		t.Errorf("pages of %v values, expect [3 1 4]", numValues)
+	}
+}
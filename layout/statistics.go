@@ -0,0 +1,24 @@
+package layout
+
+import (
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/encoding"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//EncodeStatisticsValue encodes a min/max value for the statistics of a column.
+//BYTE_ARRAY/FIXED_LEN_BYTE_ARRAY values in unsigned byte order are truncated to truncateLength bytes,
+//rounding min down and max up, so they stay valid bounds of the column values.
+func EncodeStatisticsValue(val interface{}, schema *parquet.SchemaElement, truncateLength int32, isMax bool) []byte {
+	buf := encoding.WritePlain([]interface{}{val}, *schema.Type)
+	if *schema.Type == parquet.Type_BYTE_ARRAY {
+		buf = buf[4:]
+	}
+	if truncateLength <= 0 || !common.IsBinaryOrdered(schema.Type, schema.ConvertedType, schema.LogicalType) {
+		return buf
+	}
+	if isMax {
+		return common.TruncateMaxStatistic(buf, int(truncateLength))
+	}
+	return common.TruncateMinStatistic(buf, int(truncateLength))
+}
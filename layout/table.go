@@ -15,6 +15,8 @@ func NewTableFromTable(src *Table) *Table {
 	table.MaxDefinitionLevel = 0
 	table.MaxRepetitionLevel = 0
 	table.Info = src.Info
+	table.StatisticsTruncateLength = src.StatisticsTruncateLength
+	table.PageChecksum = src.PageChecksum
 	return table
 }
 
@@ -39,6 +41,8 @@ type Table struct {
 
 	//Parquet values
 	Values []interface{}
+	//Typed values, the pages of the readers set them instead of Values, see BoxValues
+	Typed *TypedValues
 	//Definition Levels slice
 	DefinitionLevels []int32
 	//Repetition Levels slice
@@ -46,6 +50,61 @@ type Table struct {
 
 	//Tag info
 	Info *common.Tag
+
+	//Maximum length of BYTE_ARRAY/FIXED_LEN_BYTE_ARRAY statistics, 0 means no truncation
+	StatisticsTruncateLength int32
+	//Compute the CRC32 of the pages created from this table
+	PageChecksum bool
+}
+
+//The value at i starts a row: its repetition level is 0
+func (t *Table) rowStart(i int) bool {
+	return i >= len(t.RepetitionLevels) || t.RepetitionLevels[i] == 0
+}
+
+//Number of the values
+func (t *Table) Len() int {
+	return len(t.DefinitionLevels)
+}
+
+//Box the typed values to Values, the nulls are nil
+func (t *Table) BoxValues() {
+	if t.Typed == nil {
+		return
+	}
+	t.Values = t.Typed.Box(t.DefinitionLevels)
+	t.Typed = nil
+}
+
+//Append n null values with the levels 0, which are the rows of a column without values
+func (t *Table) AppendNulls(n int) {
+	if t.Typed != nil {
+		t.Typed.appendZeros(n)
+	} else {
+		t.Values = append(t.Values, make([]interface{}, n)...)
+	}
+	t.RepetitionLevels = append(t.RepetitionLevels, make([]int32, n)...)
+	t.DefinitionLevels = append(t.DefinitionLevels, make([]int32, n)...)
+}
+
+//Append the values of src, they are typed if both tables are typed or t is empty
+func (t *Table) mergeValues(src *Table) {
+	if src.Len() == 0 {
+		return
+	}
+	if src.Typed != nil && t.Typed == nil && t.Len() == 0 {
+		t.Typed = NewTypedValues(src.Typed.Type, src.Typed.MaxDefinitionLevel)
+	}
+	if src.Typed != nil && t.Typed != nil && src.Typed.Type == t.Typed.Type {
+		t.Typed.Append(src.Typed)
+		return
+	}
+	t.BoxValues()
+	if src.Typed != nil {
+		t.Values = append(t.Values, src.Typed.Box(src.DefinitionLevels)...)
+	} else {
+		t.Values = append(t.Values, src.Values...)
+	}
 }
 
 //Merge several tables to one table(the first table)
@@ -58,7 +117,7 @@ func (t *Table) Merge(tables ...*Table) {
 		if tables[i] == nil {
 			continue
 		}
-		t.Values = append(t.Values, tables[i].Values...)
+		t.mergeValues(tables[i])
 		t.RepetitionLevels = append(t.RepetitionLevels, tables[i].RepetitionLevels...)
 		t.DefinitionLevels = append(t.DefinitionLevels, tables[i].DefinitionLevels...)
 		if tables[i].MaxDefinitionLevel > t.MaxDefinitionLevel {
@@ -73,7 +132,7 @@ func (t *Table) Merge(tables ...*Table) {
 func (t *Table) Pop(numRows int64) *Table {
 	res := NewTableFromTable(t)
 	endIndex := int64(0)
-	ln := int64(len(t.Values))
+	ln := int64(t.Len())
 	i, num := int64(0), int64(-1)
 	for i = 0; i < ln; i++ {
 		if t.RepetitionLevels[i] == 0 {
@@ -93,11 +152,16 @@ func (t *Table) Pop(numRows int64) *Table {
 
 	res.RepetitionLevels = t.RepetitionLevels[:endIndex]
 	res.DefinitionLevels = t.DefinitionLevels[:endIndex]
-	res.Values = t.Values[:endIndex]
+	if t.Typed != nil {
+		res.Typed = t.Typed.Slice(0, int(endIndex))
+		t.Typed = t.Typed.Slice(int(endIndex), int(ln))
+	} else {
+		res.Values = t.Values[:endIndex]
+		t.Values = t.Values[endIndex:]
+	}
 
 	t.RepetitionLevels = t.RepetitionLevels[endIndex:]
 	t.DefinitionLevels = t.DefinitionLevels[endIndex:]
-	t.Values = t.Values[endIndex:]
 
 	return res
 }
@@ -0,0 +1,500 @@
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/xitongsys/parquet-go/encoding"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//TypedValues are the values of a column in the slice of the go type of its physical type:
+//Bool (BOOLEAN), Int32 (INT32), Int64 (INT64), Float (FLOAT), Double (DOUBLE) or
+//ByteArray (INT96, BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY). In a Table there is one value
+//for each level and the nulls are zero values, so the values are never boxed.
+type TypedValues struct {
+	Type parquet.Type
+	//The values at lower definition levels are nulls
+	MaxDefinitionLevel int32
+
+	Bool      []bool
+	Int32     []int32
+	Int64     []int64
+	Float     []float32
+	Double    []float64
+	ByteArray []string
+}
+
+func NewTypedValues(pT parquet.Type, maxDefinitionLevel int32) *TypedValues {
+	return &TypedValues{Type: pT, MaxDefinitionLevel: maxDefinitionLevel}
+}
+
+//Number of the values
+func (v *TypedValues) Len() int {
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		return len(v.Bool)
+	case parquet.Type_INT32:
+		return len(v.Int32)
+	case parquet.Type_INT64:
+		return len(v.Int64)
+	case parquet.Type_FLOAT:
+		return len(v.Float)
+	case parquet.Type_DOUBLE:
+		return len(v.Double)
+	default:
+		return len(v.ByteArray)
+	}
+}
+
+//The value at i as the values of Table
+func (v *TypedValues) Value(i int) interface{} {
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		return v.Bool[i]
+	case parquet.Type_INT32:
+		return v.Int32[i]
+	case parquet.Type_INT64:
+		return v.Int64[i]
+	case parquet.Type_FLOAT:
+		return v.Float[i]
+	case parquet.Type_DOUBLE:
+		return v.Double[i]
+	default:
+		return v.ByteArray[i]
+	}
+}
+
+//The values [i, j), they share the slice of v
+func (v *TypedValues) Slice(i, j int) *TypedValues {
+	res := NewTypedValues(v.Type, v.MaxDefinitionLevel)
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		res.Bool = v.Bool[i:j]
+	case parquet.Type_INT32:
+		res.Int32 = v.Int32[i:j]
+	case parquet.Type_INT64:
+		res.Int64 = v.Int64[i:j]
+	case parquet.Type_FLOAT:
+		res.Float = v.Float[i:j]
+	case parquet.Type_DOUBLE:
+		res.Double = v.Double[i:j]
+	default:
+		res.ByteArray = v.ByteArray[i:j]
+	}
+	return res
+}
+
+//Append the values of src, which has the same type
+func (v *TypedValues) Append(src *TypedValues) {
+	v.Bool = append(v.Bool, src.Bool...)
+	v.Int32 = append(v.Int32, src.Int32...)
+	v.Int64 = append(v.Int64, src.Int64...)
+	v.Float = append(v.Float, src.Float...)
+	v.Double = append(v.Double, src.Double...)
+	v.ByteArray = append(v.ByteArray, src.ByteArray...)
+}
+
+//Append n zero values
+func (v *TypedValues) appendZeros(n int) {
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		v.Bool = append(v.Bool, make([]bool, n)...)
+	case parquet.Type_INT32:
+		v.Int32 = append(v.Int32, make([]int32, n)...)
+	case parquet.Type_INT64:
+		v.Int64 = append(v.Int64, make([]int64, n)...)
+	case parquet.Type_FLOAT:
+		v.Float = append(v.Float, make([]float32, n)...)
+	case parquet.Type_DOUBLE:
+		v.Double = append(v.Double, make([]float64, n)...)
+	default:
+		v.ByteArray = append(v.ByteArray, make([]string, n)...)
+	}
+}
+
+//Copy the values of src, which has the same type, to v from i
+func (v *TypedValues) CopyAt(i int, src *TypedValues) {
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		copy(v.Bool[i:], src.Bool)
+	case parquet.Type_INT32:
+		copy(v.Int32[i:], src.Int32)
+	case parquet.Type_INT64:
+		copy(v.Int64[i:], src.Int64)
+	case parquet.Type_FLOAT:
+		copy(v.Float[i:], src.Float)
+	case parquet.Type_DOUBLE:
+		copy(v.Double[i:], src.Double)
+	default:
+		copy(v.ByteArray[i:], src.ByteArray)
+	}
+}
+
+//Box the values, the ones at lower definition levels than MaxDefinitionLevel are nil
+func (v *TypedValues) Box(definitionLevels []int32) []interface{} {
+	res := make([]interface{}, v.Len())
+	for i := range res {
+		if definitionLevels[i] == v.MaxDefinitionLevel {
+			res[i] = v.Value(i)
+		}
+	}
+	return res
+}
+
+//Move the values to the defined levels, the others get zero values
+func (v *TypedValues) spread(definitionLevels []int32) {
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		v.Bool = spread(v.Bool, definitionLevels, v.MaxDefinitionLevel)
+	case parquet.Type_INT32:
+		v.Int32 = spread(v.Int32, definitionLevels, v.MaxDefinitionLevel)
+	case parquet.Type_INT64:
+		v.Int64 = spread(v.Int64, definitionLevels, v.MaxDefinitionLevel)
+	case parquet.Type_FLOAT:
+		v.Float = spread(v.Float, definitionLevels, v.MaxDefinitionLevel)
+	case parquet.Type_DOUBLE:
+		v.Double = spread(v.Double, definitionLevels, v.MaxDefinitionLevel)
+	default:
+		v.ByteArray = spread(v.ByteArray, definitionLevels, v.MaxDefinitionLevel)
+	}
+}
+
+func spread[T any](vals []T, definitionLevels []int32, maxDefinitionLevel int32) []T {
+	if len(vals) == len(definitionLevels) {
+		return vals
+	}
+	res := make([]T, len(definitionLevels))
+	j := 0
+	for i, dl := range definitionLevels {
+		if dl == maxDefinitionLevel && j < len(vals) {
+			res[i] = vals[j]
+			j++
+		}
+	}
+	return res
+}
+
+//Look up the dictionary indexes of the defined levels in the values of v
+func (v *TypedValues) gather(indexes []int32, definitionLevels []int32, maxDefinitionLevel int32) *TypedValues {
+	res := NewTypedValues(v.Type, maxDefinitionLevel)
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		res.Bool = gather(v.Bool, indexes, definitionLevels, maxDefinitionLevel)
+	case parquet.Type_INT32:
+		res.Int32 = gather(v.Int32, indexes, definitionLevels, maxDefinitionLevel)
+	case parquet.Type_INT64:
+		res.Int64 = gather(v.Int64, indexes, definitionLevels, maxDefinitionLevel)
+	case parquet.Type_FLOAT:
+		res.Float = gather(v.Float, indexes, definitionLevels, maxDefinitionLevel)
+	case parquet.Type_DOUBLE:
+		res.Double = gather(v.Double, indexes, definitionLevels, maxDefinitionLevel)
+	default:
+		res.ByteArray = gather(v.ByteArray, indexes, definitionLevels, maxDefinitionLevel)
+	}
+	return res
+}
+
+func gather[T any](dict []T, indexes []int32, definitionLevels []int32, maxDefinitionLevel int32) []T {
+	res := make([]T, len(indexes))
+	for i, index := range indexes {
+		if definitionLevels[i] == maxDefinitionLevel {
+			res[i] = dict[index]
+		}
+	}
+	return res
+}
+
+//Typed values of the values at the definition level maxDefinitionLevel (all the values if definitionLevels is nil).
+//The integers may have other go types, like the interface{} encoders accept.
+func newTypedValuesFromValues(pT parquet.Type, values []interface{}, definitionLevels []int32, maxDefinitionLevel int32) *TypedValues {
+	res := NewTypedValues(pT, 0)
+	switch pT {
+	case parquet.Type_BOOLEAN:
+		res.Bool = convertValues(values, definitionLevels, maxDefinitionLevel, func(v interface{}) bool { return v.(bool) })
+	case parquet.Type_INT32:
+		res.Int32 = convertValues(values, definitionLevels, maxDefinitionLevel, func(v interface{}) int32 { return int32(toInt64(v)) })
+	case parquet.Type_INT64:
+		res.Int64 = convertValues(values, definitionLevels, maxDefinitionLevel, toInt64)
+	case parquet.Type_FLOAT:
+		res.Float = convertValues(values, definitionLevels, maxDefinitionLevel, func(v interface{}) float32 { return v.(float32) })
+	case parquet.Type_DOUBLE:
+		res.Double = convertValues(values, definitionLevels, maxDefinitionLevel, func(v interface{}) float64 { return v.(float64) })
+	default:
+		res.ByteArray = convertValues(values, definitionLevels, maxDefinitionLevel, func(v interface{}) string { return v.(string) })
+	}
+	return res
+}
+
+func convertValues[T any](values []interface{}, definitionLevels []int32, maxDefinitionLevel int32, convert func(interface{}) T) []T {
+	res := make([]T, 0, len(values))
+	for i, v := range values {
+		if definitionLevels == nil || definitionLevels[i] == maxDefinitionLevel {
+			res = append(res, convert(v))
+		}
+	}
+	return res
+}
+
+func toInt64(v interface{}) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case int32:
+		return int64(x)
+	case bool:
+		if x {
+			return 1
+		}
+		return 0
+	}
+	return reflect.ValueOf(v).Int()
+}
+
+//Typed values of the defined levels of the table
+func definedTypedValues(table *Table, pT parquet.Type) *TypedValues {
+	if table.Typed == nil {
+		return newTypedValuesFromValues(pT, table.Values, table.DefinitionLevels, table.MaxDefinitionLevel)
+	}
+	v, dls, maxDL := table.Typed, table.DefinitionLevels, table.MaxDefinitionLevel
+	res := NewTypedValues(v.Type, 0)
+	switch v.Type {
+	case parquet.Type_BOOLEAN:
+		res.Bool = filter(v.Bool, dls, maxDL)
+	case parquet.Type_INT32:
+		res.Int32 = filter(v.Int32, dls, maxDL)
+	case parquet.Type_INT64:
+		res.Int64 = filter(v.Int64, dls, maxDL)
+	case parquet.Type_FLOAT:
+		res.Float = filter(v.Float, dls, maxDL)
+	case parquet.Type_DOUBLE:
+		res.Double = filter(v.Double, dls, maxDL)
+	default:
+		res.ByteArray = filter(v.ByteArray, dls, maxDL)
+	}
+	return res
+}
+
+//The values at the definition level maxDefinitionLevel, vals if all of them are
+func filter[T any](vals []T, definitionLevels []int32, maxDefinitionLevel int32) []T {
+	var res []T
+	for i, dl := range definitionLevels {
+		if dl == maxDefinitionLevel {
+			if res != nil {
+				res = append(res, vals[i])
+			}
+		} else if res == nil {
+			res = make([]T, i, len(vals))
+			copy(res, vals[:i])
+		}
+	}
+	if res == nil {
+		return vals
+	}
+	return res
+}
+
+//Read the plain encoded values with the typed decoders
+func readPlainTyped(bytesReader *bytes.Reader, dataType parquet.Type, cnt uint64, bitWidth uint64) (*TypedValues, error) {
+	var err error
+	res := NewTypedValues(dataType, 0)
+	switch dataType {
+	case parquet.Type_BOOLEAN:
+		res.Bool, err = encoding.ReadPlainBOOLEANTyped(bytesReader, cnt)
+	case parquet.Type_INT32:
+		res.Int32, err = encoding.ReadPlainINT32Typed(bytesReader, cnt)
+	case parquet.Type_INT64:
+		res.Int64, err = encoding.ReadPlainINT64Typed(bytesReader, cnt)
+	case parquet.Type_INT96:
+		res.ByteArray, err = encoding.ReadPlainINT96Typed(bytesReader, cnt)
+	case parquet.Type_FLOAT:
+		res.Float, err = encoding.ReadPlainFLOATTyped(bytesReader, cnt)
+	case parquet.Type_DOUBLE:
+		res.Double, err = encoding.ReadPlainDOUBLETyped(bytesReader, cnt)
+	case parquet.Type_BYTE_ARRAY:
+		res.ByteArray, err = encoding.ReadPlainBYTE_ARRAYTyped(bytesReader, cnt)
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		res.ByteArray, err = encoding.ReadPlainFIXED_LEN_BYTE_ARRAYTyped(bytesReader, cnt, bitWidth)
+	default:
+		return nil, fmt.Errorf("unknown parquet type: %v", dataType)
+	}
+	return res, err
+}
+
+//Read the values of a data page with the typed decoders, the values of the dictionary encodings
+//are the int32 indexes. bitWidth is the length of FIXED_LEN_BYTE_ARRAY or the width of RLE.
+func ReadDataPageValuesTyped(bytesReader *bytes.Reader, encodingMethod parquet.Encoding, dataType parquet.Type, cnt uint64, bitWidth uint64) (*TypedValues, error) {
+	res := NewTypedValues(dataType, 0)
+	if cnt <= 0 {
+		return res, nil
+	}
+
+	var err error
+	switch encodingMethod {
+	case parquet.Encoding_PLAIN:
+		return readPlainTyped(bytesReader, dataType, cnt, bitWidth)
+
+	case parquet.Encoding_PLAIN_DICTIONARY, parquet.Encoding_RLE_DICTIONARY:
+		b, err := bytesReader.ReadByte()
+		if err != nil {
+			return res, err
+		}
+		indexes, err := encoding.ReadRLEBitPackedHybridInt32(bytesReader, uint64(b), uint64(bytesReader.Len()))
+		if err != nil {
+			return res, err
+		}
+		res.Type, res.Int32 = parquet.Type_INT32, indexes[:min(cnt, uint64(len(indexes)))]
+		return res, nil
+
+	case parquet.Encoding_RLE:
+		if dataType != parquet.Type_BOOLEAN && dataType != parquet.Type_INT32 && dataType != parquet.Type_INT64 {
+			return res, fmt.Errorf("The encoding method RLE can only be used with BOOLEAN, INT32 and INT64 types")
+		}
+		values, err := encoding.ReadRLEBitPackedHybridInt32(bytesReader, bitWidth, 0)
+		if err != nil {
+			return res, err
+		}
+		values = values[:min(cnt, uint64(len(values)))]
+		switch dataType {
+		case parquet.Type_BOOLEAN:
+			res.Bool = make([]bool, len(values))
+			for i, v := range values {
+				res.Bool[i] = v == 1
+			}
+		case parquet.Type_INT32:
+			res.Int32 = values
+		case parquet.Type_INT64:
+			res.Int64 = make([]int64, len(values))
+			for i, v := range values {
+				res.Int64[i] = int64(v)
+			}
+		}
+		return res, nil
+
+	case parquet.Encoding_BIT_PACKED:
+		//deprecated, read with the interface{} decoder
+		values, err := ReadDataPageValues(bytesReader, encodingMethod, dataType, -1, cnt, bitWidth)
+		if err != nil {
+			return res, err
+		}
+		return newTypedValuesFromValues(dataType, values, nil, 0), nil
+
+	case parquet.Encoding_DELTA_BINARY_PACKED:
+		switch dataType {
+		case parquet.Type_INT32:
+			res.Int32, err = encoding.ReadDeltaBinaryPackedINT32Typed(bytesReader)
+		case parquet.Type_INT64:
+			res.Int64, err = encoding.ReadDeltaBinaryPackedINT64Typed(bytesReader)
+		default:
+			return res, fmt.Errorf("The encoding method DELTA_BINARY_PACKED can only be used with int32 and int64 types")
+		}
+
+	case parquet.Encoding_DELTA_LENGTH_BYTE_ARRAY:
+		res.ByteArray, err = encoding.ReadDeltaLengthByteArrayTyped(bytesReader)
+
+	case parquet.Encoding_DELTA_BYTE_ARRAY:
+		res.ByteArray, err = encoding.ReadDeltaByteArrayTyped(bytesReader)
+
+	case parquet.Encoding_BYTE_STREAM_SPLIT:
+		switch dataType {
+		case parquet.Type_FLOAT:
+			res.Float, err = encoding.ReadByteStreamSplitFloat32Typed(bytesReader, cnt)
+		case parquet.Type_DOUBLE:
+			res.Double, err = encoding.ReadByteStreamSplitFloat64Typed(bytesReader, cnt)
+		case parquet.Type_INT32:
+			res.Int32, err = encoding.ReadByteStreamSplitINT32Typed(bytesReader, cnt)
+		case parquet.Type_INT64:
+			res.Int64, err = encoding.ReadByteStreamSplitINT64Typed(bytesReader, cnt)
+		case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+			res.ByteArray, err = encoding.ReadByteStreamSplitFIXED_LEN_BYTE_ARRAYTyped(bytesReader, cnt, bitWidth)
+		default:
+			return res, fmt.Errorf("The encoding method BYTE_STREAM_SPLIT can only be used with FLOAT, DOUBLE, INT32, INT64 and FIXED_LEN_BYTE_ARRAY types")
+		}
+
+	default:
+		return res, fmt.Errorf("Unknown Encoding method")
+	}
+	if err != nil {
+		return res, err
+	}
+	//the delta encodings store their number of values
+	if n := uint64(res.Len()); n > cnt {
+		res = res.Slice(0, int(cnt))
+	}
+	return res, nil
+}
+
+//Encode the values with the typed encoders
+func (page *Page) encodingTypedValues(values *TypedValues) []byte {
+	encodingMethod := parquet.Encoding_PLAIN
+	if page.Info.Encoding != 0 {
+		encodingMethod = page.Info.Encoding
+	}
+	pT := *page.Schema.Type
+
+	switch encodingMethod {
+	case parquet.Encoding_RLE:
+		bitWidth := page.Info.Length
+		switch pT {
+		case parquet.Type_BOOLEAN:
+			vals := make([]int32, len(values.Bool))
+			for i, v := range values.Bool {
+				if v {
+					vals[i] = 1
+				}
+			}
+			return encoding.WriteRLEBitPackedHybridInt32(vals, bitWidth)
+		case parquet.Type_INT32:
+			return encoding.WriteRLEBitPackedHybridInt32(values.Int32, bitWidth)
+		}
+
+	case parquet.Encoding_DELTA_BINARY_PACKED:
+		switch pT {
+		case parquet.Type_INT32:
+			return encoding.WriteDeltaINT32Typed(values.Int32)
+		case parquet.Type_INT64:
+			return encoding.WriteDeltaINT64Typed(values.Int64)
+		}
+
+	case parquet.Encoding_DELTA_BYTE_ARRAY:
+		return encoding.WriteDeltaByteArrayTyped(values.ByteArray)
+
+	case parquet.Encoding_DELTA_LENGTH_BYTE_ARRAY:
+		return encoding.WriteDeltaLengthByteArrayTyped(values.ByteArray)
+
+	case parquet.Encoding_BYTE_STREAM_SPLIT:
+		switch pT {
+		case parquet.Type_FLOAT:
+			return encoding.WriteByteStreamSplitFloat32Typed(values.Float)
+		case parquet.Type_DOUBLE:
+			return encoding.WriteByteStreamSplitFloat64Typed(values.Double)
+		case parquet.Type_INT32:
+			return encoding.WriteByteStreamSplitINT32Typed(values.Int32)
+		case parquet.Type_INT64:
+			return encoding.WriteByteStreamSplitINT64Typed(values.Int64)
+		case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+			return encoding.WriteByteStreamSplitFIXED_LEN_BYTE_ARRAYTyped(values.ByteArray, page.Schema.GetTypeLength())
+		}
+
+	default:
+		switch pT {
+		case parquet.Type_BOOLEAN:
+			return encoding.WritePlainBOOLEANTyped(values.Bool)
+		case parquet.Type_INT32:
+			return encoding.WritePlainINT32Typed(values.Int32)
+		case parquet.Type_INT64:
+			return encoding.WritePlainINT64Typed(values.Int64)
+		case parquet.Type_FLOAT:
+			return encoding.WritePlainFLOATTyped(values.Float)
+		case parquet.Type_DOUBLE:
+			return encoding.WritePlainDOUBLETyped(values.Double)
+		case parquet.Type_BYTE_ARRAY:
+			return encoding.WritePlainBYTE_ARRAYTyped(values.ByteArray)
+		case parquet.Type_INT96, parquet.Type_FIXED_LEN_BYTE_ARRAY:
+			return encoding.WritePlainFIXED_LEN_BYTE_ARRAYTyped(values.ByteArray)
+		}
+	}
+	//the combinations without a typed encoder
+	return page.EncodingValues(values.Box(make([]int32, values.Len())))
+}
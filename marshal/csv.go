@@ -1,6 +1,8 @@
 package marshal
 
 import (
+	"fmt"
+
 	"github.com/xitongsys/parquet-go/common"
 	"github.com/xitongsys/parquet-go/layout"
 	"github.com/xitongsys/parquet-go/parquet"
@@ -33,7 +35,7 @@ func MarshalCSV(records []interface{}, schemaHandler *schema.SchemaHandler) (*ma
 		}
 
 		table.MaxRepetitionLevel = 0
-		table.RepetitionType = parquet.FieldRepetitionType_OPTIONAL
+		table.RepetitionType = schema.GetRepetitionType()
 		table.Schema = schemaHandler.SchemaElements[schemaHandler.MapIndex[pathStr]]
 		table.Info = schemaHandler.Infos[i+1]
 		// Pre-allocate these arrays for efficiency
@@ -43,6 +45,9 @@ func MarshalCSV(records []interface{}, schemaHandler *schema.SchemaHandler) (*ma
 
 		for j := 0; j < len(records); j++ {
 			rec := records[j].([]interface{})[i]
+			if rec == nil && schema.GetRepetitionType() == parquet.FieldRepetitionType_REQUIRED {
+				return nil, fmt.Errorf("MarshalCSV: required column %v is null in record %v", table.Info.ExName, j)
+			}
 			table.Values = append(table.Values, rec)
 
 			table.RepetitionLevels = append(table.RepetitionLevels, 0)
@@ -14,8 +14,25 @@ import (
 	"github.com/xitongsys/parquet-go/types"
 )
 
-//ss is []string
+//ss is []string. The strings of binary columns are raw bytes, use MarshalJSONBase64 for the JSON of the reader.
 func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map[string]*layout.Table, err error) {
+	return marshalJSON(ss, schemaHandler, false, false)
+}
+
+//MarshalJSONBase64 is MarshalJSON with the binary values in base64, it's the inverse of the JSON encoding of the reader
+func MarshalJSONBase64(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map[string]*layout.Table, err error) {
+	return marshalJSON(ss, schemaHandler, true, false)
+}
+
+/*
+MarshalJSONStrict is MarshalJSON which rejects the records not matching the schema: unknown fields,
+values of the wrong type, numbers out of range and null REQUIRED fields. The errors name the JSON path, e.g. $.friends[1].id.
+*/
+func MarshalJSONStrict(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map[string]*layout.Table, err error) {
+	return marshalJSON(ss, schemaHandler, false, true)
+}
+
+func marshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler, base64Binary bool, strict bool) (tb *map[string]*layout.Table, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch x := r.(type) {
@@ -32,6 +49,7 @@ func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map
 	res := setupTableMap(schemaHandler, len(ss))
 	pathMap := schemaHandler.PathMap
 	nodeBuf := NewNodeBuf(1)
+	validator := &jsonValidator{schemaHandler: schemaHandler, base64Binary: base64Binary}
 
 	stack := make([]*Node, 0, 100)
 	for i := 0; i < len(ss); i++ {
@@ -51,7 +69,15 @@ func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map
 		}
 		// `useNumber`causes the Decoder to unmarshal a number into an interface{} as a Number instead of as a float64.
 		d.UseNumber()
-		d.Decode(&ui)
+		err := d.Decode(&ui)
+		if strict {
+			if err != nil {
+				return nil, err
+			}
+			if err = validator.validateDefined(ui, 0, "$"); err != nil {
+				return nil, err
+			}
+		}
 
 		node.Val = reflect.ValueOf(ui)
 		node.PathMap = pathMap
@@ -63,6 +89,18 @@ func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map
 			node = stack[ln-1]
 			stack = stack[:ln-1]
 
+			//null map values and list elements
+			if !node.Val.IsValid() {
+				for key, table := range res {
+					if common.IsChildPath(node.PathMap.Path, key) {
+						table.Values = append(table.Values, nil)
+						table.DefinitionLevels = append(table.DefinitionLevels, node.DL)
+						table.RepetitionLevels = append(table.RepetitionLevels, node.RL)
+					}
+				}
+				continue
+			}
+
 			tk := node.Val.Type().Kind()
 
 			pathStr := node.PathMap.Path
@@ -113,7 +151,7 @@ func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map
 						newPathStr := newNode.PathMap.Path // check again
 						newSchemaIndex := schemaHandler.MapIndex[newPathStr]
 						newSchema := schemaHandler.SchemaElements[newSchemaIndex]
-						if newSchema.GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL { //map value only be :optional or required
+						if newSchema.GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL && value.IsValid() { //map value only be :optional or required
 							newNode.DL++
 						}
 
@@ -126,13 +164,18 @@ func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map
 					}
 
 				} else { //struct
-					keysMap := make(map[string]int)
+					keysMap, exKeysMap := make(map[string]int), make(map[string]int)
 					for j := 0; j < len(keys); j++ {
 						//ExName to InName
 						keysMap[common.StringToVariableName(keys[j].String())] = j
+						exKeysMap[keys[j].String()] = j
 					}
 					for key, _ := range node.PathMap.Children {
-						ki, ok := keysMap[key]
+						//match the ExName of the field first, e.g. "uuid" for UUID
+						ki, ok := exKeysMap[schemaHandler.GetExName(int(schemaHandler.MapIndex[node.PathMap.Children[key].Path]))]
+						if !ok {
+							ki, ok = keysMap[key]
+						}
 
 						if ok && node.Val.MapIndex(keys[ki]).Elem().IsValid() {
 							newNode := nodeBuf.GetNode()
@@ -191,7 +234,7 @@ func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map
 						newPathStr := newNode.PathMap.Path
 						newSchemaIndex := schemaHandler.MapIndex[newPathStr]
 						newSchema := schemaHandler.SchemaElements[newSchemaIndex]
-						if newSchema.GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL { //element of LIST can only be optional or required
+						if newSchema.GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL && newNode.Val.IsValid() { //element of LIST can only be optional or required
 							newNode.DL++
 						}
 
@@ -226,8 +269,7 @@ func MarshalJSON(ss []interface{}, schemaHandler *schema.SchemaHandler) (tb *map
 
 			} else {
 				table := res[node.PathMap.Path]
-				pT, cT := schema.Type, schema.ConvertedType
-				val, err := types.JSONTypeToParquetType(node.Val, pT, cT, int(schema.GetTypeLength()), int(schema.GetScale()))
+				val, err := types.JSONValueToParquetType(node.Val, schema, base64Binary)
 				if err != nil {
 					return nil, err
 				}
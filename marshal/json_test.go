@@ -0,0 +1,90 @@
+package marshal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+var marshalJSONCompatSchema = `
+{
+  "Tag": "name=parquet_go_root, repetitiontype=REQUIRED",
+  "Fields": [
+    {"Tag": "name=name, inname=Name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+    {"Tag": "name=age, inname=Age, type=INT32, repetitiontype=OPTIONAL"},
+    {"Tag": "name=id, inname=Id, type=INT64, repetitiontype=REQUIRED"},
+    {"Tag": "name=weight, inname=Weight, type=FLOAT, repetitiontype=OPTIONAL"},
+    {"Tag": "name=flag, inname=Flag, type=BOOLEAN, repetitiontype=REQUIRED"},
+    {"Tag": "name=price, inname=Price, type=INT64, convertedtype=DECIMAL, scale=2, precision=10, repetitiontype=OPTIONAL"},
+    {"Tag": "name=raw, inname=Raw, type=BYTE_ARRAY, repetitiontype=OPTIONAL"},
+    {"Tag": "name=scores, inname=Scores, type=INT32, repetitiontype=REPEATED"},
+    {"Tag": "name=tags, inname=Tags, type=LIST, repetitiontype=OPTIONAL",
+     "Fields": [{"Tag": "name=element, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"}]},
+    {"Tag": "name=attrs, inname=Attrs, type=MAP, repetitiontype=OPTIONAL",
+     "Fields": [
+       {"Tag": "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+       {"Tag": "name=value, type=INT64, repetitiontype=OPTIONAL"}
+     ]},
+    {"Tag": "name=address, inname=Address, repetitiontype=OPTIONAL",
+     "Fields": [
+       {"Tag": "name=city, inname=City, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+       {"Tag": "name=zip, inname=Zip, type=INT32, repetitiontype=OPTIONAL"}
+     ]}
+  ]
+}
+`
+
+var marshalJSONCompatRecords = []interface{}{
+	`{"name":"a","age":1,"id":10,"weight":1.5,"flag":true,"price":12.34,"raw":"xyz","scores":[1,2],"tags":["x","y"],"attrs":{"k":1},"address":{"city":"c","zip":7}}`,
+	`{"Name":"b","Id":11,"Flag":false,"Scores":[],"Tags":[],"Attrs":{},"Address":{"City":"d"}}`,
+	`{"name":"c","id":12,"flag":true}`,
+	`{"name":"d","age":null,"id":13,"flag":false,"price":"5","tags":null,"address":null}`,
+}
+
+func dumpTables(t *testing.T, records []interface{}) string {
+	sh, err := schema.NewSchemaHandlerFromJSON(marshalJSONCompatSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, err := MarshalJSON(records, sh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{}
+	for key := range *tables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var res strings.Builder
+	for _, key := range keys {
+		table := (*tables)[key]
+		fmt.Fprintf(&res, "%v: %v %v %v\n", strings.Replace(key, "\x01", ".", -1), table.Values, table.DefinitionLevels, table.RepetitionLevels)
+	}
+	return res.String()
+}
+
+//The tables of the JSON accepted before MarshalJSON supported null list elements, null map values
+//and the original names of the fields. They are the output of MarshalJSON before that change.
+var marshalJSONCompatTables = `Parquet_go_root.Address.City: [c d <nil> <nil>] [1 1 0 0] [0 0 0 0]
+Parquet_go_root.Address.Zip: [7 <nil> <nil> <nil>] [2 1 0 0] [0 0 0 0]
+Parquet_go_root.Age: [1 <nil> <nil> <nil>] [1 0 0 0] [0 0 0 0]
+Parquet_go_root.Attrs.Key_value.Key: [k <nil> <nil> <nil>] [2 1 0 0] [0 0 0 0]
+Parquet_go_root.Attrs.Key_value.Value: [1 <nil> <nil> <nil>] [3 1 0 0] [0 0 0 0]
+Parquet_go_root.Flag: [true false true false] [0 0 0 0] [0 0 0 0]
+Parquet_go_root.Id: [10 11 12 13] [0 0 0 0] [0 0 0 0]
+Parquet_go_root.Name: [a b c d] [0 0 0 0] [0 0 0 0]
+Parquet_go_root.Price: [1234 <nil> <nil> 500] [1 0 0 1] [0 0 0 0]
+Parquet_go_root.Raw: [xyz <nil> <nil> <nil>] [1 0 0 0] [0 0 0 0]
+Parquet_go_root.Scores: [1 2 <nil> <nil> <nil>] [1 1 0 0 0] [0 1 0 0 0]
+Parquet_go_root.Tags.List.Element: [x y <nil> <nil> <nil>] [2 2 1 0 0] [0 1 0 0 0]
+Parquet_go_root.Weight: [1.5 <nil> <nil> <nil>] [1 0 0 0] [0 0 0 0]
+`
+
+func TestMarshalJSONCompat(t *testing.T) {
+	if res := dumpTables(t, marshalJSONCompatRecords); res != marshalJSONCompatTables {
+		t.Errorf("MarshalJSON err, expect\n%v\nget\n%v", marshalJSONCompatTables, res)
+	}
+}
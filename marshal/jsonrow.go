@@ -0,0 +1,134 @@
+package marshal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/types"
+)
+
+/*
+RowToJSON encodes the row as a JSON object with the original names as keys, in the order of the schema.
+The primitive values are formatted by types.ParquetTypeToJSONType, lists and repeated fields are arrays,
+maps are objects. The result can be written back with MarshalJSONBase64.
+*/
+func RowToJSON(row Row, schemaHandler *schema.SchemaHandler) ([]byte, error) {
+	e := &jsonRowEncoder{schemaHandler: schemaHandler}
+	if err := e.encodeDefined(0, GroupValue(row)); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+type jsonRowEncoder struct {
+	schemaHandler *schema.SchemaHandler
+	buf           bytes.Buffer
+}
+
+func (e *jsonRowEncoder) writeJSON(v interface{}) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.buf.Write(bs)
+	return nil
+}
+
+func (e *jsonRowEncoder) encodeList(idx int32, values []Value, defined bool) error {
+	e.buf.WriteByte('[')
+	for i, v := range values {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		var err error
+		if defined {
+			err = e.encodeDefined(idx, v)
+		} else {
+			err = e.encode(idx, v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.buf.WriteByte(']')
+	return nil
+}
+
+//Encode the value of the schema element at idx
+func (e *jsonRowEncoder) encode(idx int32, v Value) error {
+	if v.IsNull() {
+		e.buf.WriteString("null")
+		return nil
+	}
+	if e.schemaHandler.SchemaElements[idx].GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+		if v.Kind != KindList {
+			return fmt.Errorf("%v: repeated field expects LIST, get %v", e.schemaHandler.GetExName(int(idx)), v.Kind)
+		}
+		return e.encodeList(idx, v.List, true)
+	}
+	return e.encodeDefined(idx, v)
+}
+
+func (e *jsonRowEncoder) encodeDefined(idx int32, v Value) error {
+	sh := e.schemaHandler
+	se := sh.SchemaElements[idx]
+
+	if se.GetNumChildren() == 0 {
+		val, err := types.ParquetTypeToJSONType(v.Primitive, se)
+		if err != nil {
+			return err
+		}
+		return e.writeJSON(val)
+	}
+
+	if elementIdx, levels := sh.ListElementIndex(idx); levels > 0 && v.Kind == KindList {
+		return e.encodeList(elementIdx, v.List, levels == 2)
+	}
+
+	if keyIdx, valueIdx, ok := sh.MapKeyValueIndex(idx); ok && v.Kind == KindMap {
+		e.buf.WriteByte('{')
+		for i, entry := range v.Map {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			key, err := types.ParquetTypeToJSONType(entry.Key.Primitive, sh.SchemaElements[keyIdx])
+			if err != nil {
+				return err
+			}
+			//JSON keys are strings
+			if err = e.writeJSON(fmt.Sprint(key)); err != nil {
+				return err
+			}
+			e.buf.WriteByte(':')
+			if err = e.encode(valueIdx, entry.Value); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte('}')
+		return nil
+	}
+
+	if v.Kind != KindGroup {
+		return fmt.Errorf("%v: expects GROUP, get %v", sh.GetExName(int(idx)), v.Kind)
+	}
+	e.buf.WriteByte('{')
+	for i, childIdx := range sh.ChildrenIndex(idx) {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		name := sh.GetExName(int(childIdx))
+		if err := e.writeJSON(name); err != nil {
+			return err
+		}
+		e.buf.WriteByte(':')
+		child, _ := v.Group.Get(name)
+		if err := e.encode(childIdx, child); err != nil {
+			return err
+		}
+	}
+	e.buf.WriteByte('}')
+	return nil
+}
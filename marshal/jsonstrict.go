@@ -0,0 +1,186 @@
+package marshal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/types"
+)
+
+//Kind of a decoded JSON value for the errors
+func jsonKindName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+//Keys of the object in order, for the deterministic errors
+func sortedJSONKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//jsonValidator checks the decoded JSON values against the schema for MarshalJSONStrict
+type jsonValidator struct {
+	schemaHandler *schema.SchemaHandler
+	base64Binary  bool
+}
+
+func (jv *jsonValidator) mismatch(path string, idx int32, expect string, v interface{}) error {
+	return fmt.Errorf("%s: field %v expects %s, get %s", path, jv.schemaHandler.GetExName(int(idx)), expect, jsonKindName(v))
+}
+
+//Validate the value of the schema element at idx
+func (jv *jsonValidator) validate(v interface{}, idx int32, path string) error {
+	se := jv.schemaHandler.SchemaElements[idx]
+	if v == nil {
+		if se.GetRepetitionType() == parquet.FieldRepetitionType_REQUIRED {
+			return fmt.Errorf("%s: REQUIRED field %v is null", path, jv.schemaHandler.GetExName(int(idx)))
+		}
+		return nil
+	}
+	if se.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+		list, ok := v.([]interface{})
+		if !ok {
+			return jv.mismatch(path, idx, "array", v)
+		}
+		for i, e := range list {
+			if err := jv.validateDefined(e, idx, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return jv.validateDefined(v, idx, path)
+}
+
+func (jv *jsonValidator) validateDefined(v interface{}, idx int32, path string) error {
+	sh := jv.schemaHandler
+	se := sh.SchemaElements[idx]
+	if v == nil {
+		return fmt.Errorf("%s: field %v is null", path, sh.GetExName(int(idx)))
+	}
+
+	if se.GetNumChildren() == 0 {
+		return jv.validatePrimitive(v, idx, path)
+	}
+
+	if elementIdx, levels := sh.ListElementIndex(idx); levels == 3 && se.GetConvertedType() == parquet.ConvertedType_LIST {
+		list, ok := v.([]interface{})
+		if !ok {
+			return jv.mismatch(path, idx, "array", v)
+		}
+		for i, e := range list {
+			if err := jv.validate(e, elementIdx, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return jv.mismatch(path, idx, "object", v)
+	}
+
+	if keyIdx, valueIdx, ok := sh.MapKeyValueIndex(idx); ok && se.GetConvertedType() == parquet.ConvertedType_MAP {
+		for _, key := range sortedJSONKeys(obj) {
+			value := obj[key]
+			if err := jv.validatePrimitive(key, keyIdx, path+"."+key); err != nil {
+				return err
+			}
+			if err := jv.validate(value, valueIdx, path+"."+key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	//the keys are matched by the ExName, then by the InName like marshalJSON
+	children := make(map[string]int32)
+	for _, childIdx := range sh.ChildrenIndex(idx) {
+		children[sh.GetExName(int(childIdx))] = childIdx
+	}
+	for _, childIdx := range sh.ChildrenIndex(idx) {
+		inName := sh.GetInName(int(childIdx))
+		if _, ok := children[inName]; !ok {
+			children[inName] = childIdx
+		}
+	}
+	found := make(map[int32]bool)
+	for _, key := range sortedJSONKeys(obj) {
+		value := obj[key]
+		childIdx, ok := children[key]
+		if !ok {
+			childIdx, ok = children[common.StringToVariableName(key)]
+		}
+		if !ok {
+			return fmt.Errorf("%s.%s: unknown field", path, key)
+		}
+		found[childIdx] = true
+		if err := jv.validate(value, childIdx, path+"."+key); err != nil {
+			return err
+		}
+	}
+	for _, childIdx := range sh.ChildrenIndex(idx) {
+		if !found[childIdx] && sh.SchemaElements[childIdx].GetRepetitionType() == parquet.FieldRepetitionType_REQUIRED {
+			return fmt.Errorf("%s.%s: missing REQUIRED field", path, sh.GetExName(int(childIdx)))
+		}
+	}
+	return nil
+}
+
+func (jv *jsonValidator) validatePrimitive(v interface{}, idx int32, path string) error {
+	se := jv.schemaHandler.SchemaElements[idx]
+	var ok bool
+	switch se.GetType() {
+	case parquet.Type_BOOLEAN:
+		_, ok = v.(bool)
+	case parquet.Type_INT32, parquet.Type_INT64, parquet.Type_INT96, parquet.Type_FLOAT, parquet.Type_DOUBLE:
+		_, ok = v.(json.Number)
+		//times and decimals can be strings
+		if _, isString := v.(string); isString && (se.IsSetConvertedType() || se.LogicalType != nil || se.GetType() == parquet.Type_INT96) {
+			ok = true
+		}
+	default:
+		_, ok = v.(string)
+		if _, isNumber := v.(json.Number); isNumber {
+			_, _, ok = types.DecimalScale(se)
+		}
+	}
+	if !ok {
+		return jv.mismatch(path, idx, se.GetType().String(), v)
+	}
+	var err error
+	if n, isNumber := v.(json.Number); isNumber {
+		//strict parsing of the numbers, e.g. 1.5 isn't an INT64
+		_, err = types.TextToParquetType(string(n), se, "")
+	} else {
+		_, err = types.JSONValueToParquetType(reflect.ValueOf(v), se, jv.base64Binary)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
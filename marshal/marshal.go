@@ -52,6 +52,12 @@ type Marshaler interface {
 	Marshal(node *Node, nodeBuf *NodeBufType, stack []*Node) (newStack []*Node)
 }
 
+//ParquetValueMarshaler is implemented by the leaf types which store themselves as a parquet primitive value
+type ParquetValueMarshaler = types.ParquetValueMarshaler
+
+//ParquetValueUnmarshaler is implemented by the leaf types which read themselves from a parquet primitive value
+type ParquetValueUnmarshaler = types.ParquetValueUnmarshaler
+
 type ParquetPtr struct{}
 
 func (p *ParquetPtr) Marshal(node *Node, nodeBuf *NodeBufType, stack []*Node) []*Node {
@@ -263,6 +269,17 @@ func Marshal(srcInterface []interface{}, schemaHandler *schema.SchemaHandler) (t
 		}
 	}
 
+	//native types of the values, IsNativeType is checked once per type
+	nativeTypes := make(map[reflect.Type]bool)
+	isNative := func(t reflect.Type) bool {
+		native, ok := nativeTypes[t]
+		if !ok {
+			native = types.IsNativeType(t)
+			nativeTypes[t] = native
+		}
+		return native
+	}
+
 	stack := make([]*Node, 0, 100)
 	for i := 0; i < len(srcInterface); i++ {
 		stack = stack[:0]
@@ -281,9 +298,12 @@ func Marshal(srcInterface []interface{}, schemaHandler *schema.SchemaHandler) (t
 			node := stack[ln-1]
 			stack = stack[:ln-1]
 
-			tk := reflect.Interface
+			//native types are stored as leaves
+			tk, native := reflect.Interface, false
 			if node.Val.IsValid() {
-				tk = node.Val.Type().Kind()
+				if native = isNative(node.Val.Type()); !native {
+					tk = node.Val.Type().Kind()
+				}
 			}
 			var m Marshaler
 
@@ -309,6 +329,12 @@ func Marshal(srcInterface []interface{}, schemaHandler *schema.SchemaHandler) (t
 				if node.Val.IsValid() {
 					v = node.Val.Interface()
 				}
+				//the other values already have the physical types, the values of interface fields are checked
+				if native || node.Val.IsValid() && node.Val.Kind() == reflect.Interface {
+					if v, err = types.GoTypeToParquetType(v, schema); err != nil {
+						return nil, err
+					}
+				}
 				table.Values = append(table.Values, types.InterfaceToParquetType(v, schema.Type))
 				table.DefinitionLevels = append(table.DefinitionLevels, node.DL)
 				table.RepetitionLevels = append(table.RepetitionLevels, node.RL)
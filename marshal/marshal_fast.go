@@ -105,6 +105,11 @@ func (c *compiler) getEncoder(typ reflect.Type, pathMap *schema.PathMapType) enc
 }
 
 func (c *compiler) compile(typ reflect.Type, pathMap *schema.PathMapType) encoder {
+	if types.IsNativeType(reflect.ToReflectType(typ)) {
+		te := c.terminalEncoder(typ, pathMap)
+		te.native = true
+		return &te
+	}
 	switch typ.Kind() {
 	case reflect.Ptr:
 		return c.compilePointer(typ, pathMap)
@@ -133,7 +138,7 @@ func (c *compiler) terminalEncoder(typ reflect.Type, pathMap *schema.PathMapType
 	return terminalEncoder{
 		typeIface: typeIface,
 		table:     c.tableMap[path],
-		pT:        c.getSchema(path).Type,
+		schema:    c.getSchema(path),
 	}
 }
 
@@ -209,7 +214,9 @@ func toIface(typeIface interface{}, ptr unsafe.Pointer) interface{} {
 type terminalEncoder struct {
 	typeIface interface{}
 	table     *layout.Table
-	pT        *parquet.Type
+	schema    *parquet.SchemaElement
+	// native is set for the types converted by types.GoTypeToParquetType
+	native bool
 }
 
 // encode converts a pointer back to an interface of the correct type and appends it
@@ -223,7 +230,17 @@ func (e *terminalEncoder) encode(ptr unsafe.Pointer, dl, rl int32) {
 }
 
 func (e *terminalEncoder) write(v interface{}, dl, rl int32) {
-	e.table.Values = append(e.table.Values, types.InterfaceToParquetType(v, e.pT))
+	if e.native {
+		var err error
+		if v, err = types.GoTypeToParquetType(v, e.schema); err != nil {
+			panic(err)
+		}
+	}
+	var pT *parquet.Type
+	if e.schema != nil {
+		pT = e.schema.Type
+	}
+	e.table.Values = append(e.table.Values, types.InterfaceToParquetType(v, pT))
 	e.table.DefinitionLevels = append(e.table.DefinitionLevels, dl)
 	e.table.RepetitionLevels = append(e.table.RepetitionLevels, rl)
 }
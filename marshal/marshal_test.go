@@ -3,6 +3,7 @@ package marshal
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/xitongsys/parquet-go/schema"
 )
@@ -197,3 +198,29 @@ func TestMarshalFast(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalNativeTypes(t *testing.T) {
+	type Entry struct {
+		Id      int64       `parquet:"name=id, type=INT64"`
+		Date    time.Time   `parquet:"name=date, type=INT32, convertedtype=DATE"`
+		Created interface{} `parquet:"name=created, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	}
+	sh, err := schema.NewSchemaHandlerFromStruct(new(Entry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	tableMap, err := Marshal([]interface{}{Entry{1, day, day}, Entry{2, day.AddDate(0, 0, 1), day.Add(time.Second)}}, sh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, expect := range map[string][]interface{}{
+		"Parquet_go_root\x01Id":      {int64(1), int64(2)},
+		"Parquet_go_root\x01Date":    {int32(18690), int32(18691)},
+		"Parquet_go_root\x01Created": {day.UnixNano() / 1e6, day.UnixNano()/1e6 + 1000},
+	} {
+		if res := (*tableMap)[path].Values; !reflect.DeepEqual(res, expect) {
+			t.Errorf("%v: expect %v, get %v", path, expect, res)
+		}
+	}
+}
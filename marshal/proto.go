@@ -0,0 +1,308 @@
+package marshal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/schema"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+//ProtoToRow converts the message to a row of the schema, which is usually created by schema.NewSchemaHandlerFromProto
+func ProtoToRow(msg proto.Message, schemaHandler *schema.SchemaHandler) (Row, error) {
+	c := &protoConverter{schemaHandler: schemaHandler}
+	return c.row(msg.ProtoReflect(), 0)
+}
+
+//Converter of the protobuf messages to rows
+type protoConverter struct {
+	schemaHandler *schema.SchemaHandler
+}
+
+//Row of the message for the group at idx, the fields are matched by name
+func (c *protoConverter) row(m protoreflect.Message, idx int32) (Row, error) {
+	sh := c.schemaHandler
+	fields := m.Descriptor().Fields()
+	var row Row
+	for _, childIdx := range sh.ChildrenIndex(idx) {
+		name := sh.GetExName(int(childIdx))
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			//missing fields are null
+			continue
+		}
+		v, err := c.field(m, fd, childIdx)
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, Field{Name: name, Value: v})
+	}
+	return row, nil
+}
+
+func (c *protoConverter) field(m protoreflect.Message, fd protoreflect.FieldDescriptor, idx int32) (Value, error) {
+	sh := c.schemaHandler
+	if fd.IsList() {
+		elementIdx := idx
+		if i, levels := sh.ListElementIndex(idx); levels > 0 {
+			elementIdx = i
+		}
+		list := m.Get(fd).List()
+		values := make([]Value, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			v, err := c.value(fd, list.Get(i), elementIdx)
+			if err != nil {
+				return Value{}, err
+			}
+			values[i] = v
+		}
+		return ListValue(values), nil
+	}
+
+	if fd.IsMap() {
+		_, valueIdx, ok := sh.MapKeyValueIndex(idx)
+		if !ok {
+			return Value{}, fmt.Errorf("%v: map field isn't a MAP", sh.GetExName(int(idx)))
+		}
+		var entries []MapEntry
+		var keys []protoreflect.MapKey
+		mp := m.Get(fd).Map()
+		mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		//the map entries are written in order of the keys
+		sort.Slice(keys, func(i, j int) bool { return protoMapKeyLess(keys[i], keys[j]) })
+		for _, k := range keys {
+			key, err := c.value(fd.MapKey(), k.Value(), -1)
+			if err != nil {
+				return Value{}, err
+			}
+			value, err := c.value(fd.MapValue(), mp.Get(k), valueIdx)
+			if err != nil {
+				return Value{}, err
+			}
+			entries = append(entries, MapEntry{Key: key, Value: value})
+		}
+		return MapValue(entries), nil
+	}
+
+	if fd.HasPresence() && !m.Has(fd) {
+		return NullValue(), nil
+	}
+	return c.value(fd, m.Get(fd), idx)
+}
+
+func protoMapKeyLess(a, b protoreflect.MapKey) bool {
+	switch x := a.Interface().(type) {
+	case bool:
+		return !x && b.Bool()
+	case int32, int64:
+		return a.Int() < b.Int()
+	case uint32, uint64:
+		return a.Uint() < b.Uint()
+	}
+	return a.String() < b.String()
+}
+
+//Value of a singular value of fd, idx is the schema element of the value
+func (c *protoConverter) value(fd protoreflect.FieldDescriptor, v protoreflect.Value, idx int32) (Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return BooleanValue(v.Bool()), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return Int32Value(int32(v.Int())), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return Int32Value(int32(uint32(v.Uint()))), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return Int64Value(v.Int()), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return Int64Value(int64(v.Uint())), nil
+	case protoreflect.FloatKind:
+		return FloatValue(float32(v.Float())), nil
+	case protoreflect.DoubleKind:
+		return DoubleValue(v.Float()), nil
+	case protoreflect.StringKind:
+		return ByteArrayValue(v.String()), nil
+	case protoreflect.BytesKind:
+		return ByteArrayValue(string(v.Bytes())), nil
+	case protoreflect.EnumKind:
+		//the unknown values are written as numbers
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return ByteArrayValue(string(ev.Name())), nil
+		}
+		return ByteArrayValue(strconv.Itoa(int(v.Enum()))), nil
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := v.Message()
+		if m.Descriptor().FullName() == schema.ProtoTimestampName {
+			seconds, nanos := protoTimestampFields(m.Descriptor())
+			return Int64Value(m.Get(seconds).Int()*1e9 + m.Get(nanos).Int()), nil
+		}
+		if idx < 0 || c.schemaHandler.SchemaElements[idx].GetNumChildren() == 0 {
+			return Value{}, fmt.Errorf("%v: message %v isn't a group", fd.Name(), m.Descriptor().FullName())
+		}
+		row, err := c.row(m, idx)
+		if err != nil {
+			return Value{}, err
+		}
+		return GroupValue(row), nil
+	}
+	return Value{}, fmt.Errorf("%v: unknown kind %v", fd.Name(), fd.Kind())
+}
+
+func protoTimestampFields(md protoreflect.MessageDescriptor) (protoreflect.FieldDescriptor, protoreflect.FieldDescriptor) {
+	return md.Fields().ByName("seconds"), md.Fields().ByName("nanos")
+}
+
+/*
+UnmarshalProto fills the message with the row, e.g. of ParquetReader.ReadRows. The fields are matched by name,
+null values are unset fields and the columns which aren't in the message are ignored.
+The values are converted like ProtoToRow, Timestamp messages are read from INT64 nanoseconds.
+*/
+func UnmarshalProto(row Row, msg proto.Message) error {
+	return unmarshalProtoRow(row, msg.ProtoReflect())
+}
+
+func unmarshalProtoRow(row Row, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	for _, field := range row {
+		fd := fields.ByName(protoreflect.Name(field.Name))
+		if fd == nil || field.Value.IsNull() {
+			continue
+		}
+		if err := unmarshalProtoField(field.Value, m, fd); err != nil {
+			return fmt.Errorf("%v.%v", m.Descriptor().Name(), err)
+		}
+	}
+	return nil
+}
+
+func unmarshalProtoField(v Value, m protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	if fd.IsList() {
+		if v.Kind != KindList {
+			return fmt.Errorf("%v: repeated field expects LIST, get %v", fd.Name(), v.Kind)
+		}
+		list := m.Mutable(fd).List()
+		for _, e := range v.List {
+			pv, err := protoValue(e, fd, list.NewElement)
+			if err != nil {
+				return err
+			}
+			list.Append(pv)
+		}
+		return nil
+	}
+
+	if fd.IsMap() {
+		if v.Kind != KindMap {
+			return fmt.Errorf("%v: map field expects MAP, get %v", fd.Name(), v.Kind)
+		}
+		mp := m.Mutable(fd).Map()
+		for _, entry := range v.Map {
+			key, err := protoValue(entry.Key, fd.MapKey(), nil)
+			if err != nil {
+				return err
+			}
+			value, err := protoValue(entry.Value, fd.MapValue(), mp.NewValue)
+			if err != nil {
+				return err
+			}
+			mp.Set(key.MapKey(), value)
+		}
+		return nil
+	}
+
+	pv, err := protoValue(v, fd, func() protoreflect.Value { return m.NewField(fd) })
+	if err != nil {
+		return err
+	}
+	m.Set(fd, pv)
+	return nil
+}
+
+//Protobuf value of the value of fd, newMessage creates the message values
+func protoValue(v Value, fd protoreflect.FieldDescriptor, newMessage func() protoreflect.Value) (protoreflect.Value, error) {
+	mismatch := func() (protoreflect.Value, error) {
+		return protoreflect.Value{}, fmt.Errorf("%v: can't read %v as %v", fd.Name(), v.Kind, fd.Kind())
+	}
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		pv := newMessage()
+		m := pv.Message()
+		if m.Descriptor().FullName() == schema.ProtoTimestampName {
+			n, ok := v.Primitive.(int64)
+			if !ok {
+				return mismatch()
+			}
+			t := time.Unix(0, n)
+			seconds, nanos := protoTimestampFields(m.Descriptor())
+			m.Set(seconds, protoreflect.ValueOfInt64(t.Unix()))
+			m.Set(nanos, protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+			return pv, nil
+		}
+		if v.Kind != KindGroup {
+			return mismatch()
+		}
+		return pv, unmarshalProtoRow(v.Group, m)
+
+	case protoreflect.EnumKind:
+		s, ok := v.Primitive.(string)
+		if !ok {
+			return mismatch()
+		}
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%v: unknown value %q of enum %v", fd.Name(), s, fd.Enum().FullName())
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	}
+
+	switch x := v.Primitive.(type) {
+	case bool:
+		if fd.Kind() == protoreflect.BoolKind {
+			return protoreflect.ValueOfBool(x), nil
+		}
+	case int32:
+		switch fd.Kind() {
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+			return protoreflect.ValueOfInt32(x), nil
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+			return protoreflect.ValueOfUint32(uint32(x)), nil
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			return protoreflect.ValueOfInt64(int64(x)), nil
+		}
+	case int64:
+		switch fd.Kind() {
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			return protoreflect.ValueOfInt64(x), nil
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			return protoreflect.ValueOfUint64(uint64(x)), nil
+		}
+	case float32:
+		switch fd.Kind() {
+		case protoreflect.FloatKind:
+			return protoreflect.ValueOfFloat32(x), nil
+		case protoreflect.DoubleKind:
+			return protoreflect.ValueOfFloat64(float64(x)), nil
+		}
+	case float64:
+		if fd.Kind() == protoreflect.DoubleKind {
+			return protoreflect.ValueOfFloat64(x), nil
+		}
+	case string:
+		switch fd.Kind() {
+		case protoreflect.StringKind:
+			return protoreflect.ValueOfString(x), nil
+		case protoreflect.BytesKind:
+			return protoreflect.ValueOfBytes([]byte(x)), nil
+		}
+	}
+	return mismatch()
+}
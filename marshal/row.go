@@ -0,0 +1,458 @@
+package marshal
+
+import (
+	"fmt"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/layout"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+//Kind of a dynamic Value
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindBoolean
+	KindInt32
+	KindInt64
+	KindInt96
+	KindFloat
+	KindDouble
+	KindByteArray
+	KindFixedLenByteArray
+	KindGroup
+	KindList
+	KindMap
+)
+
+var kindNames = []string{"NULL", "BOOLEAN", "INT32", "INT64", "INT96", "FLOAT", "DOUBLE", "BYTE_ARRAY", "FIXED_LEN_BYTE_ARRAY", "GROUP", "LIST", "MAP"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+/*
+Value is a dynamically typed value of a field.
+Primitive holds the parquet value of the primitive kinds: bool, int32, int64, float32, float64
+or string (INT96, BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY). Group, List and Map hold the nested kinds.
+*/
+type Value struct {
+	Kind      Kind
+	Primitive interface{}
+	Group     Row
+	List      []Value
+	Map       []MapEntry
+}
+
+//Field of a Row, Name is the original (external) name of the schema element
+type Field struct {
+	Name  string
+	Value Value
+}
+
+//MapEntry is a key/value pair of a map Value
+type MapEntry struct {
+	Key   Value
+	Value Value
+}
+
+//Row is a record or a nested group, the fields are in the order of the schema
+type Row []Field
+
+//Get the value of the field with the original name
+func (r Row) Get(name string) (Value, bool) {
+	for _, f := range r {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return Value{}, false
+}
+
+func NullValue() Value                      { return Value{Kind: KindNull} }
+func BooleanValue(v bool) Value             { return Value{Kind: KindBoolean, Primitive: v} }
+func Int32Value(v int32) Value              { return Value{Kind: KindInt32, Primitive: v} }
+func Int64Value(v int64) Value              { return Value{Kind: KindInt64, Primitive: v} }
+func Int96Value(v string) Value             { return Value{Kind: KindInt96, Primitive: v} }
+func FloatValue(v float32) Value            { return Value{Kind: KindFloat, Primitive: v} }
+func DoubleValue(v float64) Value           { return Value{Kind: KindDouble, Primitive: v} }
+func ByteArrayValue(v string) Value         { return Value{Kind: KindByteArray, Primitive: v} }
+func FixedLenByteArrayValue(v string) Value { return Value{Kind: KindFixedLenByteArray, Primitive: v} }
+func GroupValue(fields Row) Value           { return Value{Kind: KindGroup, Group: fields} }
+func ListValue(values []Value) Value        { return Value{Kind: KindList, List: values} }
+func MapValue(entries []MapEntry) Value     { return Value{Kind: KindMap, Map: entries} }
+
+func (v Value) IsNull() bool {
+	return v.Kind == KindNull
+}
+
+func primitiveKind(pT parquet.Type) Kind {
+	switch pT {
+	case parquet.Type_BOOLEAN:
+		return KindBoolean
+	case parquet.Type_INT32:
+		return KindInt32
+	case parquet.Type_INT64:
+		return KindInt64
+	case parquet.Type_INT96:
+		return KindInt96
+	case parquet.Type_FLOAT:
+		return KindFloat
+	case parquet.Type_DOUBLE:
+		return KindDouble
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		return KindFixedLenByteArray
+	}
+	return KindByteArray
+}
+
+//Check the Go type of the primitive value against the parquet type
+func checkPrimitive(v interface{}, pT parquet.Type) bool {
+	switch v.(type) {
+	case bool:
+		return pT == parquet.Type_BOOLEAN
+	case int32:
+		return pT == parquet.Type_INT32
+	case int64:
+		return pT == parquet.Type_INT64
+	case float32:
+		return pT == parquet.Type_FLOAT
+	case float64:
+		return pT == parquet.Type_DOUBLE
+	case string:
+		return pT == parquet.Type_INT96 || pT == parquet.Type_BYTE_ARRAY || pT == parquet.Type_FIXED_LEN_BYTE_ARRAY
+	}
+	return false
+}
+
+type rowMarshaler struct {
+	schemaHandler *schema.SchemaHandler
+	tableMap      map[string]*layout.Table
+	//max repetition level and the leaves of each schema element
+	repetitionLevels []int32
+	leaves           [][]*layout.Table
+}
+
+func newRowMarshaler(schemaHandler *schema.SchemaHandler, tableMap map[string]*layout.Table) *rowMarshaler {
+	ln := len(schemaHandler.SchemaElements)
+	m := &rowMarshaler{
+		schemaHandler:    schemaHandler,
+		tableMap:         tableMap,
+		repetitionLevels: make([]int32, ln),
+		leaves:           make([][]*layout.Table, ln),
+	}
+	for idx := 0; idx < ln; idx++ {
+		path := common.StrToPath(schemaHandler.IndexMap[int32(idx)])
+		m.repetitionLevels[idx], _ = schemaHandler.MaxRepetitionLevel(path)
+	}
+	for pathStr, table := range tableMap {
+		path := common.StrToPath(pathStr)
+		for i := 1; i <= len(path); i++ {
+			idx := schemaHandler.MapIndex[common.PathToStr(path[:i])]
+			m.leaves[idx] = append(m.leaves[idx], table)
+		}
+	}
+	return m
+}
+
+func (m *rowMarshaler) name(idx int32) string {
+	return m.schemaHandler.GetExName(int(idx))
+}
+
+//Write null (or an empty list) for all the columns under idx
+func (m *rowMarshaler) writeNull(idx int32, rl int32, dl int32) {
+	for _, table := range m.leaves[idx] {
+		table.Values = append(table.Values, nil)
+		table.RepetitionLevels = append(table.RepetitionLevels, rl)
+		table.DefinitionLevels = append(table.DefinitionLevels, dl)
+	}
+}
+
+//Write the value of the schema element at idx. dl is the definition level of its parent.
+func (m *rowMarshaler) write(idx int32, v Value, rl int32, dl int32) error {
+	switch m.schemaHandler.SchemaElements[idx].GetRepetitionType() {
+	case parquet.FieldRepetitionType_OPTIONAL:
+		if v.IsNull() {
+			m.writeNull(idx, rl, dl)
+			return nil
+		}
+		return m.writeDefined(idx, v, rl, dl+1)
+
+	case parquet.FieldRepetitionType_REPEATED:
+		if v.IsNull() {
+			m.writeNull(idx, rl, dl)
+			return nil
+		}
+		if v.Kind != KindList {
+			return fmt.Errorf("%v: repeated field expects LIST, get %v", m.name(idx), v.Kind)
+		}
+		return m.writeRepeated(idx, len(v.List), rl, dl, func(i int, rl int32, dl int32) error {
+			return m.writeDefined(idx, v.List[i], rl, dl)
+		})
+
+	default:
+		if v.IsNull() && idx > 0 {
+			return fmt.Errorf("%v: required field is null", m.name(idx))
+		}
+		return m.writeDefined(idx, v, rl, dl)
+	}
+}
+
+//Write the n elements of the repeated field at idx
+func (m *rowMarshaler) writeRepeated(idx int32, n int, rl int32, dl int32, fn func(i int, rl int32, dl int32) error) error {
+	if n == 0 {
+		m.writeNull(idx, rl, dl)
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			rl = m.repetitionLevels[idx]
+		}
+		if err := fn(i, rl, dl+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *rowMarshaler) writeDefined(idx int32, v Value, rl int32, dl int32) error {
+	sh := m.schemaHandler
+	se := sh.SchemaElements[idx]
+
+	if se.GetNumChildren() == 0 {
+		if !checkPrimitive(v.Primitive, se.GetType()) {
+			return fmt.Errorf("%v: can't write %T as %v", m.name(idx), v.Primitive, se.GetType())
+		}
+		table := m.tableMap[sh.IndexMap[idx]]
+		table.Values = append(table.Values, v.Primitive)
+		table.RepetitionLevels = append(table.RepetitionLevels, rl)
+		table.DefinitionLevels = append(table.DefinitionLevels, dl)
+		return nil
+	}
+
+	if elementIdx, levels := sh.ListElementIndex(idx); levels > 0 {
+		if v.Kind != KindList {
+			return fmt.Errorf("%v: expects LIST, get %v", m.name(idx), v.Kind)
+		}
+		return m.writeRepeated(idx+1, len(v.List), rl, dl, func(i int, rl int32, dl int32) error {
+			if levels == 2 {
+				return m.writeDefined(elementIdx, v.List[i], rl, dl)
+			}
+			return m.write(elementIdx, v.List[i], rl, dl)
+		})
+	}
+
+	if keyIdx, valueIdx, ok := sh.MapKeyValueIndex(idx); ok {
+		if v.Kind != KindMap {
+			return fmt.Errorf("%v: expects MAP, get %v", m.name(idx), v.Kind)
+		}
+		return m.writeRepeated(idx+1, len(v.Map), rl, dl, func(i int, rl int32, dl int32) error {
+			if err := m.write(keyIdx, v.Map[i].Key, rl, dl); err != nil {
+				return err
+			}
+			return m.write(valueIdx, v.Map[i].Value, rl, dl)
+		})
+	}
+
+	if v.Kind != KindGroup {
+		return fmt.Errorf("%v: expects GROUP, get %v", m.name(idx), v.Kind)
+	}
+	for _, childIdx := range sh.ChildrenIndex(idx) {
+		//missing fields are null
+		child, _ := v.Group.Get(m.name(childIdx))
+		if err := m.write(childIdx, child, rl, dl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Convert the rows (Row or *Row) to table map
+func MarshalRows(records []interface{}, schemaHandler *schema.SchemaHandler) (*map[string]*layout.Table, error) {
+	res := setupTableMap(schemaHandler, len(records))
+	m := newRowMarshaler(schemaHandler, res)
+	for _, record := range records {
+		var row Row
+		switch r := record.(type) {
+		case Row:
+			row = r
+		case *Row:
+			row = *r
+		default:
+			return nil, fmt.Errorf("MarshalRows: can't marshal %T", record)
+		}
+		if err := m.write(0, GroupValue(row), 0, 0); err != nil {
+			return nil, err
+		}
+	}
+	return &res, nil
+}
+
+//Node of a record while it's assembled from the columns
+type rowNode struct {
+	value    interface{}
+	children map[int32]*rowNode
+	//elements of a repeated field and the element of the current column,
+	//index is reset when the node is reached by another column
+	elements []*rowNode
+	index    int
+	column   int
+}
+
+func (n *rowNode) child(idx int32) *rowNode {
+	if n.children == nil {
+		n.children = make(map[int32]*rowNode)
+	}
+	c, ok := n.children[idx]
+	if !ok {
+		c = &rowNode{index: -1}
+		n.children[idx] = c
+	}
+	return c
+}
+
+//Convert the table map to rows, the tables must contain all the columns of the schema
+func UnmarshalRows(tableMap *map[string]*layout.Table, bgn int, end int, schemaHandler *schema.SchemaHandler) ([]Row, error) {
+	roots := make([]*rowNode, 0, end-bgn)
+
+	column := 0
+	for _, table := range *tableMap {
+		column++
+		table.BoxValues()
+		path := table.Path
+		schemaIndexs := make([]int32, len(path))
+		repetitionLevels, definitionLevels := make([]int32, len(path)), make([]int32, len(path))
+		isRepeated := make([]bool, len(path))
+		for i := 0; i < len(path); i++ {
+			schemaIndexs[i] = schemaHandler.MapIndex[common.PathToStr(path[:i+1])]
+			repetitionLevels[i], _ = schemaHandler.MaxRepetitionLevel(path[:i+1])
+			definitionLevels[i], _ = schemaHandler.MaxDefinitionLevel(path[:i+1])
+			isRepeated[i] = schemaHandler.SchemaElements[schemaIndexs[i]].GetRepetitionType() == parquet.FieldRepetitionType_REPEATED
+		}
+
+		num := -1
+		for i := 0; i < len(table.Values); i++ {
+			rl, dl := table.RepetitionLevels[i], table.DefinitionLevels[i]
+			if rl == 0 {
+				num++
+			}
+			if num < bgn {
+				continue
+			}
+			if num >= end {
+				break
+			}
+			for len(roots) <= num-bgn {
+				roots = append(roots, &rowNode{index: -1})
+			}
+
+			node := roots[num-bgn]
+			for j := 1; j < len(path); j++ {
+				if isRepeated[j] {
+					c := node.child(schemaIndexs[j])
+					if definitionLevels[j] > dl {
+						break
+					}
+					if c.column != column {
+						c.index, c.column = -1, column
+					}
+					if rl == repetitionLevels[j] || c.index < 0 {
+						c.index++
+					}
+					if c.index >= len(c.elements) {
+						c.elements = append(c.elements, &rowNode{index: -1})
+					}
+					node = c.elements[c.index]
+				} else {
+					if definitionLevels[j] > dl {
+						break
+					}
+					node = node.child(schemaIndexs[j])
+				}
+				if j == len(path)-1 {
+					node.value = table.Values[i]
+				}
+			}
+		}
+	}
+
+	u := &rowUnmarshaler{schemaHandler: schemaHandler}
+	res := make([]Row, len(roots))
+	for i, root := range roots {
+		res[i] = u.value(0, root).Group
+	}
+	return res, nil
+}
+
+type rowUnmarshaler struct {
+	schemaHandler *schema.SchemaHandler
+}
+
+//Value of the schema element at idx, node is nil if it's not defined
+func (u *rowUnmarshaler) value(idx int32, node *rowNode) Value {
+	if node == nil {
+		return NullValue()
+	}
+	if u.schemaHandler.SchemaElements[idx].GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+		values := make([]Value, len(node.elements))
+		for i, e := range node.elements {
+			values[i] = u.definedValue(idx, e)
+		}
+		return ListValue(values)
+	}
+	return u.definedValue(idx, node)
+}
+
+func (u *rowUnmarshaler) definedValue(idx int32, node *rowNode) Value {
+	sh := u.schemaHandler
+	se := sh.SchemaElements[idx]
+
+	if se.GetNumChildren() == 0 {
+		if node.value == nil {
+			return NullValue()
+		}
+		return Value{Kind: primitiveKind(se.GetType()), Primitive: node.value}
+	}
+
+	if elementIdx, levels := sh.ListElementIndex(idx); levels > 0 {
+		values := []Value{}
+		if repeated := node.children[idx+1]; repeated != nil {
+			for _, e := range repeated.elements {
+				if levels == 2 {
+					values = append(values, u.definedValue(elementIdx, e))
+				} else {
+					values = append(values, u.value(elementIdx, e.children[elementIdx]))
+				}
+			}
+		}
+		return ListValue(values)
+	}
+
+	if keyIdx, valueIdx, ok := sh.MapKeyValueIndex(idx); ok {
+		entries := []MapEntry{}
+		if keyValue := node.children[idx+1]; keyValue != nil {
+			for _, e := range keyValue.elements {
+				entries = append(entries, MapEntry{
+					Key:   u.value(keyIdx, e.children[keyIdx]),
+					Value: u.value(valueIdx, e.children[valueIdx]),
+				})
+			}
+		}
+		return MapValue(entries)
+	}
+
+	childrenIndex := sh.ChildrenIndex(idx)
+	row := make(Row, 0, len(childrenIndex))
+	for _, childIdx := range childrenIndex {
+		row = append(row, Field{
+			Name:  sh.GetExName(int(childIdx)),
+			Value: u.value(childIdx, node.children[childIdx]),
+		})
+	}
+	return GroupValue(row)
+}
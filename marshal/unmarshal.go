@@ -9,6 +9,7 @@ import (
 	"github.com/xitongsys/parquet-go/layout"
 	"github.com/xitongsys/parquet-go/parquet"
 	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/types"
 )
 
 //Record Map KeyValue pair
@@ -51,7 +52,7 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 
 		tableNeeds[name] = table
 
-		ln := len(table.Values)
+		ln := table.Len()
 		num := -1
 		tableBgn[name], tableEnd[name] = -1, -1
 		for i := 0; i < ln; i++ {
@@ -92,6 +93,19 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 			schemaIndexs[i] = int(schemaHandler.MapIndex[curPathStr])
 		}
 
+		//levels of the lists and the keys of the maps on the path, the names of their fields may differ in legacy files
+		listLevels, isMapKey := make([]int, len(path)), make([]bool, len(path))
+		for i := 0; i < len(path); i++ {
+			_, listLevels[i] = schemaHandler.ListElementIndex(int32(schemaIndexs[i]))
+			if i+2 < len(path) {
+				if keyIdx, _, ok := schemaHandler.MapKeyValueIndex(int32(schemaIndexs[i])); ok {
+					isMapKey[i+2] = schemaIndexs[i+2] == int(keyIdx)
+				} else {
+					isMapKey[i+2] = strings.ToLower(path[i+2]) == "key"
+				}
+			}
+		}
+
 		repetitionLevels, definitionLevels := make([]int32, len(path)), make([]int32, len(path))
 		for i := 0; i < len(path); i++ {
 			repetitionLevels[i], _ = schemaHandler.MaxRepetitionLevel(path[:i+1])
@@ -113,17 +127,25 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 		var prevSliceRecord *SliceRecord
 
 		for i := bgn; i < end; i++ {
-			rl, dl, val := table.RepetitionLevels[i], table.DefinitionLevels[i], table.Values[i]
+			rl, dl := table.RepetitionLevels[i], table.DefinitionLevels[i]
 			po, index := root, prefixIndex
 		OuterLoop:
 			for index < len(path) {
 				schemaIndex := schemaIndexs[index]
-				_, cT := schemaHandler.SchemaElements[schemaIndex].Type, schemaHandler.SchemaElements[schemaIndex].ConvertedType
 
 				poType := po.Type()
+				if index == len(path)-1 && types.IsNativeType(poType) {
+					value, err := types.ParquetTypeToGoType(tableValue(table, i), poType, schemaHandler.SchemaElements[schemaIndex])
+					if err != nil {
+						return err
+					}
+					po.Set(value)
+					break OuterLoop
+				}
+
 				switch poType.Kind() {
 				case reflect.Slice:
-					cTIsList := cT != nil && *cT == parquet.ConvertedType_LIST
+					listLevel := listLevels[index]
 
 					if po.IsNil() {
 						po.Set(reflect.MakeSlice(poType, 0, 0))
@@ -145,7 +167,7 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 						prevSliceRecord = sliceRec
 					}
 
-					if cTIsList {
+					if listLevel > 0 {
 						index++
 						if definitionLevels[index] > dl {
 							break OuterLoop
@@ -162,7 +184,8 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 
 					po = sliceRec.Values[sliceRec.Index]
 
-					if cTIsList {
+					//the repeated field is the element of the 2-level lists
+					if listLevel == 3 {
 						index++
 						if definitionLevels[index] > dl {
 							break OuterLoop
@@ -200,7 +223,7 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 							})
 					}
 
-					if strings.ToLower(path[index+1]) == "key" {
+					if isMapKey[index+1] {
 						po = mapRec.KeyValues[mapRec.Index].Key
 
 					} else {
@@ -235,11 +258,7 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 					po = po.FieldByIndex(prevFieldIndex)
 
 				default:
-					value := reflect.ValueOf(val)
-					if po.Type() != value.Type() {
-						value = value.Convert(poType)
-					}
-					po.Set(value)
+					setTableValue(po, table, i)
 					break OuterLoop
 				}
 			}
@@ -262,3 +281,66 @@ func Unmarshal(tableMap *map[string]*layout.Table, bgn int, end int, dstInterfac
 
 	return nil
 }
+
+//The value at i of the table
+func tableValue(table *layout.Table, i int) interface{} {
+	if table.Typed != nil {
+		if table.DefinitionLevels[i] != table.Typed.MaxDefinitionLevel {
+			return nil
+		}
+		return table.Typed.Value(i)
+	}
+	return table.Values[i]
+}
+
+//Set po to the value at i of the table, the typed values are set without boxing them
+func setTableValue(po reflect.Value, table *layout.Table, i int) {
+	if v := table.Typed; v != nil {
+		switch po.Kind() {
+		case reflect.Bool:
+			if v.Type == parquet.Type_BOOLEAN {
+				po.SetBool(v.Bool[i])
+				return
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			switch v.Type {
+			case parquet.Type_INT32:
+				po.SetInt(int64(v.Int32[i]))
+				return
+			case parquet.Type_INT64:
+				po.SetInt(v.Int64[i])
+				return
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			//like the conversion of the signed values
+			switch v.Type {
+			case parquet.Type_INT32:
+				po.SetUint(uint64(int64(v.Int32[i])))
+				return
+			case parquet.Type_INT64:
+				po.SetUint(uint64(v.Int64[i]))
+				return
+			}
+		case reflect.Float32, reflect.Float64:
+			switch v.Type {
+			case parquet.Type_FLOAT:
+				po.SetFloat(float64(v.Float[i]))
+				return
+			case parquet.Type_DOUBLE:
+				po.SetFloat(v.Double[i])
+				return
+			}
+		case reflect.String:
+			switch v.Type {
+			case parquet.Type_BYTE_ARRAY, parquet.Type_FIXED_LEN_BYTE_ARRAY, parquet.Type_INT96:
+				po.SetString(v.ByteArray[i])
+				return
+			}
+		}
+	}
+	value := reflect.ValueOf(tableValue(table, i))
+	if po.Type() != value.Type() {
+		value = value.Convert(po.Type())
+	}
+	po.Set(value)
+}
@@ -1201,6 +1201,70 @@ func (p *UUIDType) String() string {
 	return fmt.Sprintf("UUIDType(%+v)", *p)
 }
 
+type Float16Type struct {
+}
+
+func NewFloat16Type() *Float16Type {
+	return &Float16Type{}
+}
+
+func (p *Float16Type) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+			return err
+		}
+		if err := iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *Float16Type) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "Float16Type"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *Float16Type) Equals(other *Float16Type) bool {
+	if p == other {
+		return true
+	} else if p == nil || other == nil {
+		return false
+	}
+	return true
+}
+
+func (p *Float16Type) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("Float16Type(%+v)", *p)
+}
+
 type MapType struct {
 }
 
@@ -2822,6 +2886,7 @@ func (p *BsonType) String() string {
 //  - JSON
 //  - BSON
 //  - UUID
+//  - FLOAT16
 type LogicalType struct {
 	STRING    *StringType    `thrift:"STRING,1" db:"STRING" json:"STRING,omitempty"`
 	MAP       *MapType       `thrift:"MAP,2" db:"MAP" json:"MAP,omitempty"`
@@ -2832,11 +2897,12 @@ type LogicalType struct {
 	TIME      *TimeType      `thrift:"TIME,7" db:"TIME" json:"TIME,omitempty"`
 	TIMESTAMP *TimestampType `thrift:"TIMESTAMP,8" db:"TIMESTAMP" json:"TIMESTAMP,omitempty"`
 	// unused field # 9
-	INTEGER *IntType  `thrift:"INTEGER,10" db:"INTEGER" json:"INTEGER,omitempty"`
-	UNKNOWN *NullType `thrift:"UNKNOWN,11" db:"UNKNOWN" json:"UNKNOWN,omitempty"`
-	JSON    *JsonType `thrift:"JSON,12" db:"JSON" json:"JSON,omitempty"`
-	BSON    *BsonType `thrift:"BSON,13" db:"BSON" json:"BSON,omitempty"`
-	UUID    *UUIDType `thrift:"UUID,14" db:"UUID" json:"UUID,omitempty"`
+	INTEGER *IntType     `thrift:"INTEGER,10" db:"INTEGER" json:"INTEGER,omitempty"`
+	UNKNOWN *NullType    `thrift:"UNKNOWN,11" db:"UNKNOWN" json:"UNKNOWN,omitempty"`
+	JSON    *JsonType    `thrift:"JSON,12" db:"JSON" json:"JSON,omitempty"`
+	BSON    *BsonType    `thrift:"BSON,13" db:"BSON" json:"BSON,omitempty"`
+	UUID    *UUIDType    `thrift:"UUID,14" db:"UUID" json:"UUID,omitempty"`
+	FLOAT16 *Float16Type `thrift:"FLOAT16,15" db:"FLOAT16" json:"FLOAT16,omitempty"`
 }
 
 func NewLogicalType() *LogicalType {
@@ -2959,6 +3025,15 @@ func (p *LogicalType) GetUUID() *UUIDType {
 	}
 	return p.UUID
 }
+
+var LogicalType_FLOAT16_DEFAULT *Float16Type
+
+func (p *LogicalType) GetFLOAT16() *Float16Type {
+	if !p.IsSetFLOAT16() {
+		return LogicalType_FLOAT16_DEFAULT
+	}
+	return p.FLOAT16
+}
 func (p *LogicalType) CountSetFieldsLogicalType() int {
 	count := 0
 	if p.IsSetSTRING() {
@@ -3000,6 +3075,9 @@ func (p *LogicalType) CountSetFieldsLogicalType() int {
 	if p.IsSetUUID() {
 		count++
 	}
+	if p.IsSetFLOAT16() {
+		count++
+	}
 	return count
 
 }
@@ -3056,6 +3134,10 @@ func (p *LogicalType) IsSetUUID() bool {
 	return p.UUID != nil
 }
 
+func (p *LogicalType) IsSetFLOAT16() bool {
+	return p.FLOAT16 != nil
+}
+
 func (p *LogicalType) Read(ctx context.Context, iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(ctx); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -3200,6 +3282,16 @@ func (p *LogicalType) Read(ctx context.Context, iprot thrift.TProtocol) error {
 					return err
 				}
 			}
+		case 15:
+			if fieldTypeId == thrift.STRUCT {
+				if err := p.ReadField15(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
 		default:
 			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
 				return err
@@ -3319,6 +3411,14 @@ func (p *LogicalType) ReadField14(ctx context.Context, iprot thrift.TProtocol) e
 	return nil
 }
 
+func (p *LogicalType) ReadField15(ctx context.Context, iprot thrift.TProtocol) error {
+	p.FLOAT16 = &Float16Type{}
+	if err := p.FLOAT16.Read(ctx, iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.FLOAT16), err)
+	}
+	return nil
+}
+
 func (p *LogicalType) Write(ctx context.Context, oprot thrift.TProtocol) error {
 	if c := p.CountSetFieldsLogicalType(); c != 1 {
 		return fmt.Errorf("%T write union: exactly one field must be set (%d set).", p, c)
@@ -3366,6 +3466,9 @@ func (p *LogicalType) Write(ctx context.Context, oprot thrift.TProtocol) error {
 		if err := p.writeField14(ctx, oprot); err != nil {
 			return err
 		}
+		if err := p.writeField15(ctx, oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(ctx); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -3571,6 +3674,21 @@ func (p *LogicalType) writeField14(ctx context.Context, oprot thrift.TProtocol)
 	return err
 }
 
+func (p *LogicalType) writeField15(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetFLOAT16() {
+		if err := oprot.WriteFieldBegin(ctx, "FLOAT16", thrift.STRUCT, 15); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 15:FLOAT16: ", p), err)
+		}
+		if err := p.FLOAT16.Write(ctx, oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.FLOAT16), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 15:FLOAT16: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *LogicalType) Equals(other *LogicalType) bool {
 	if p == other {
 		return true
@@ -3616,6 +3734,9 @@ func (p *LogicalType) Equals(other *LogicalType) bool {
 	if !p.UUID.Equals(other.UUID) {
 		return false
 	}
+	if !p.FLOAT16.Equals(other.FLOAT16) {
+		return false
+	}
 	return true
 }
 
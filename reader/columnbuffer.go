@@ -1,8 +1,11 @@
 package reader
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/xitongsys/parquet-go/common"
@@ -29,9 +32,24 @@ type ColumnBufferType struct {
 
 	DataTable        *layout.Table
 	DataTableNumRows int64
+
+	//Verify the page checksums and track the offset of the next page for the errors
+	VerifyChecksum bool
+	PageOffset     int64
+
+	//Set if the column is read with the file schema and converted to SchemaHandler
+	resolution *columnResolution
+
+	//Row groups to read (nil for all) and the number of rows left to read (-1 for all)
+	rowGroups []bool
+	rowsLeft  int64
 }
 
 func NewColumnBuffer(pFile source.ParquetFile, footer *parquet.FileMetaData, schemaHandler *schema.SchemaHandler, pathStr string) (*ColumnBufferType, error) {
+	return newColumnBuffer(pFile, footer, schemaHandler, pathStr, nil, false, nil)
+}
+
+func newColumnBuffer(pFile source.ParquetFile, footer *parquet.FileMetaData, schemaHandler *schema.SchemaHandler, pathStr string, resolution *columnResolution, verifyChecksum bool, rowGroups []bool) (*ColumnBufferType, error) {
 	newPFile, err := pFile.Open("")
 	if err != nil {
 		return nil, err
@@ -42,6 +60,10 @@ func NewColumnBuffer(pFile source.ParquetFile, footer *parquet.FileMetaData, sch
 		SchemaHandler:    schemaHandler,
 		PathStr:          pathStr,
 		DataTableNumRows: -1,
+		VerifyChecksum:   verifyChecksum,
+		resolution:       resolution,
+		rowGroups:        rowGroups,
+		rowsLeft:         -1,
 	}
 
 	if err = res.NextRowGroup(); err == io.EOF {
@@ -50,10 +72,21 @@ func NewColumnBuffer(pFile source.ParquetFile, footer *parquet.FileMetaData, sch
 	return res, err
 }
 
+//Schema handler and path of the column in the file
+func (cbt *ColumnBufferType) fileColumn() (*schema.SchemaHandler, string) {
+	if cbt.resolution != nil {
+		return cbt.resolution.schemaHandler, cbt.resolution.pathStr
+	}
+	return cbt.SchemaHandler, cbt.PathStr
+}
+
 func (cbt *ColumnBufferType) NextRowGroup() error {
 	var err error
 	rowGroups := cbt.Footer.GetRowGroups()
 	ln := int64(len(rowGroups))
+	for cbt.rowGroups != nil && cbt.RowGroupIndex < ln && !cbt.rowGroups[cbt.RowGroupIndex] {
+		cbt.RowGroupIndex++
+	}
 	if cbt.RowGroupIndex >= ln {
 		cbt.DataTableNumRows++ //very important, because DataTableNumRows is one smaller than real rows number
 		return io.EOF
@@ -61,15 +94,22 @@ func (cbt *ColumnBufferType) NextRowGroup() error {
 
 	cbt.RowGroupIndex++
 
+	schemaHandler, pathStr := cbt.fileColumn()
+	if cbt.resolution != nil && pathStr == "" {
+		cbt.ChunkHeader = nil
+		cbt.appendMissingRows(rowGroups[cbt.RowGroupIndex-1].GetNumRows())
+		return nil
+	}
+
 	columnChunks := rowGroups[cbt.RowGroupIndex-1].GetColumns()
 	i := int64(0)
 	ln = int64(len(columnChunks))
 	for i = 0; i < ln; i++ {
 		path := make([]string, 0)
-		path = append(path, cbt.SchemaHandler.GetRootInName())
+		path = append(path, schemaHandler.GetRootInName())
 		path = append(path, columnChunks[i].MetaData.GetPathInSchema()...)
 
-		if cbt.PathStr == common.PathToStr(path) {
+		if pathStr == common.PathToStr(path) {
 			break
 		}
 	}
@@ -97,6 +137,7 @@ func (cbt *ColumnBufferType) NextRowGroup() error {
 	}
 
 	cbt.ThriftReader = source.ConvertToThriftReader(cbt.PFile, offset)
+	cbt.PageOffset = offset
 	cbt.ChunkReadValues = 0
 	cbt.DictPage = nil
 	return nil
@@ -104,8 +145,9 @@ func (cbt *ColumnBufferType) NextRowGroup() error {
 
 func (cbt *ColumnBufferType) ReadPage() error {
 	if cbt.ChunkHeader != nil && cbt.ChunkHeader.MetaData != nil && cbt.ChunkReadValues < cbt.ChunkHeader.MetaData.NumValues {
-		page, numValues, numRows, err := layout.ReadPage(cbt.ThriftReader, cbt.SchemaHandler, cbt.ChunkHeader.MetaData)
-		if err != nil {
+		schemaHandler, _ := cbt.fileColumn()
+		page, numValues, numRows, err := layout.ReadPageWithChecksum(cbt.ThriftReader, schemaHandler, cbt.ChunkHeader.MetaData, cbt.VerifyChecksum)
+		if err = cbt.checkPage(page, err); err != nil {
 			//data is nil and rl/dl=0, no pages in file
 			if err == io.EOF {
 				if cbt.DataTable == nil {
@@ -118,11 +160,9 @@ func (cbt *ColumnBufferType) ReadPage() error {
 
 				cbt.DataTableNumRows = cbt.ChunkHeader.MetaData.NumValues
 
-				for cbt.ChunkReadValues < cbt.ChunkHeader.MetaData.NumValues {
-					cbt.DataTable.Values = append(cbt.DataTable.Values, nil)
-					cbt.DataTable.RepetitionLevels = append(cbt.DataTable.RepetitionLevels, int32(0))
-					cbt.DataTable.DefinitionLevels = append(cbt.DataTable.DefinitionLevels, int32(0))
-					cbt.ChunkReadValues++
+				if n := cbt.ChunkHeader.MetaData.NumValues - cbt.ChunkReadValues; n > 0 {
+					cbt.DataTable.AppendNulls(int(n))
+					cbt.ChunkReadValues += n
 				}
 			}
 
@@ -135,12 +175,13 @@ func (cbt *ColumnBufferType) ReadPage() error {
 		}
 
 		page.Decode(cbt.DictPage)
+		table := cbt.resolveTable(page.DataTable)
 
 		if cbt.DataTable == nil {
-			cbt.DataTable = layout.NewTableFromTable(page.DataTable)
+			cbt.DataTable = layout.NewTableFromTable(table)
 		}
 
-		cbt.DataTable.Merge(page.DataTable)
+		cbt.DataTable.Merge(table)
 		cbt.ChunkReadValues += numValues
 
 		cbt.DataTableNumRows += numRows
@@ -148,6 +189,10 @@ func (cbt *ColumnBufferType) ReadPage() error {
 		if err := cbt.NextRowGroup(); err != nil {
 			return err
 		}
+		//rows of a missing column
+		if cbt.ChunkHeader == nil {
+			return nil
+		}
 
 		return cbt.ReadPage()
 	}
@@ -155,29 +200,48 @@ func (cbt *ColumnBufferType) ReadPage() error {
 	return nil
 }
 
+// checkPage records the offset of a corrupted page and advances PageOffset after a valid one
+func (cbt *ColumnBufferType) checkPage(page *layout.Page, err error) error {
+	var checksumErr *layout.PageChecksumError
+	if errors.As(err, &checksumErr) {
+		checksumErr.Offset = cbt.PageOffset
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	cbt.PageOffset += page.HeaderSize + int64(page.Header.GetCompressedPageSize())
+	return nil
+}
+
 func (cbt *ColumnBufferType) ReadPageForSkip() (*layout.Page, error) {
 	if cbt.ChunkHeader != nil && cbt.ChunkHeader.MetaData != nil && cbt.ChunkReadValues < cbt.ChunkHeader.MetaData.NumValues {
-		page, err := layout.ReadPageRawData(cbt.ThriftReader, cbt.SchemaHandler, cbt.ChunkHeader.MetaData)
-		if err != nil {
+		schemaHandler, _ := cbt.fileColumn()
+		page, err := layout.ReadPageRawData(cbt.ThriftReader, schemaHandler, cbt.ChunkHeader.MetaData)
+		if err == nil && cbt.VerifyChecksum {
+			err = layout.VerifyPageChecksum(page.Header, page.RawData, common.PathToStr(page.Path))
+		}
+		if err = cbt.checkPage(page, err); err != nil {
 			return nil, err
 		}
 
-		numValues, numRows, err := page.GetRLDLFromRawData(cbt.SchemaHandler)
+		numValues, numRows, err := page.GetRLDLFromRawData(schemaHandler)
 		if err != nil {
 			return nil, err
 		}
 
 		if page.Header.GetType() == parquet.PageType_DICTIONARY_PAGE {
-			page.GetValueFromRawData(cbt.SchemaHandler)
+			page.GetValueFromRawData(schemaHandler)
 			cbt.DictPage = page
 			return page, nil
 		}
 
+		table := cbt.resolveTable(page.DataTable)
 		if cbt.DataTable == nil {
-			cbt.DataTable = layout.NewTableFromTable(page.DataTable)
+			cbt.DataTable = layout.NewTableFromTable(table)
 		}
 
-		cbt.DataTable.Merge(page.DataTable)
+		cbt.DataTable.Merge(table)
 		cbt.ChunkReadValues += numValues
 		cbt.DataTableNumRows += numRows
 		return page, nil
@@ -186,52 +250,89 @@ func (cbt *ColumnBufferType) ReadPageForSkip() (*layout.Page, error) {
 		if err := cbt.NextRowGroup(); err != nil {
 			return nil, err
 		}
+		//rows of a missing column
+		if cbt.ChunkHeader == nil {
+			return nil, nil
+		}
 
 		return cbt.ReadPageForSkip()
 	}
 }
 
-func (cbt *ColumnBufferType) SkipRows(num int64) int64 {
+//SkipRows skips num rows and returns the number of skipped rows, the error is nil at the end of the column
+func (cbt *ColumnBufferType) SkipRows(num int64) (int64, error) {
 	var (
 		err  error
 		page *layout.Page
 	)
+	if cbt.rowsLeft >= 0 && num > cbt.rowsLeft {
+		num = cbt.rowsLeft
+	}
+	if num <= 0 {
+		return 0, nil
+	}
 
 	for cbt.DataTableNumRows < num && err == nil {
 		page, err = cbt.ReadPageForSkip()
 	}
+	if err == io.EOF {
+		err = nil
+	}
 
 	if num > cbt.DataTableNumRows {
 		num = cbt.DataTableNumRows
 	}
 
 	if page != nil {
-		if err = page.GetValueFromRawData(cbt.SchemaHandler); err != nil {
-			return 0
+		schemaHandler, _ := cbt.fileColumn()
+		if err = page.GetValueFromRawData(schemaHandler); err != nil {
+			return 0, err
 		}
 
 		page.Decode(cbt.DictPage)
-		i, j := len(cbt.DataTable.Values)-1, len(page.DataTable.Values)-1
-		for i >= 0 && j >= 0 {
-			cbt.DataTable.Values[i] = page.DataTable.Values[j]
-			i, j = i-1, j-1
+		//the values of the last page replace its nulls at the end of DataTable
+		table := cbt.resolveTable(page.DataTable)
+		if n := cbt.DataTable.Len() - table.Len(); table.Typed != nil && cbt.DataTable.Typed != nil && n >= 0 {
+			cbt.DataTable.Typed.CopyAt(n, table.Typed)
+		} else {
+			table.BoxValues()
+			cbt.DataTable.BoxValues()
+			i, j := len(cbt.DataTable.Values)-1, len(table.Values)-1
+			for i >= 0 && j >= 0 {
+				cbt.DataTable.Values[i] = table.Values[j]
+				i, j = i-1, j-1
+			}
 		}
 	}
 
 	cbt.DataTable.Pop(num)
 	cbt.DataTableNumRows -= num
+	if cbt.rowsLeft >= 0 {
+		cbt.rowsLeft -= num
+	}
 	if cbt.DataTableNumRows <= 0 {
 		tmp := cbt.DataTable
 		cbt.DataTable = layout.NewTableFromTable(tmp)
 		cbt.DataTable.Merge(tmp)
 	}
 
-	return num
+	return num, err
+}
+
+//ReadRows reads num rows and returns them with their number, the error is nil at the end of the column
+func (cbt *ColumnBufferType) ReadRows(num int64) (*layout.Table, int64, error) {
+	table, num, err := cbt.readRows(num)
+	table.BoxValues()
+	return table, num, err
 }
 
-func (cbt *ColumnBufferType) ReadRows(num int64) (*layout.Table, int64) {
-	if cbt.Footer.NumRows == 0 {
-		return &layout.Table{}, 0
+//Read num rows like ReadRows, the values of the pages stay typed
+func (cbt *ColumnBufferType) readRows(num int64) (*layout.Table, int64, error) {
+	if cbt.Footer.NumRows == 0 || cbt.rowsLeft == 0 {
+		return &layout.Table{}, 0, nil
+	}
+	if cbt.rowsLeft > 0 && num > cbt.rowsLeft {
+		num = cbt.rowsLeft
 	}
 
 	var err error
@@ -239,6 +340,9 @@ func (cbt *ColumnBufferType) ReadRows(num int64) (*layout.Table, int64) {
 	for cbt.DataTableNumRows < num && err == nil {
 		err = cbt.ReadPage()
 	}
+	if err == io.EOF {
+		err = nil
+	}
 
 	if cbt.DataTableNumRows < 0 {
 		cbt.DataTableNumRows = 0
@@ -251,12 +355,116 @@ func (cbt *ColumnBufferType) ReadRows(num int64) (*layout.Table, int64) {
 
 	res := cbt.DataTable.Pop(num)
 	cbt.DataTableNumRows -= num
+	if cbt.rowsLeft > 0 {
+		cbt.rowsLeft -= num
+	}
 
 	if cbt.DataTableNumRows <= 0 { //release previous slice memory
 		tmp := cbt.DataTable
 		cbt.DataTable = layout.NewTableFromTable(tmp)
 		cbt.DataTable.Merge(tmp)
 	}
-	return res, num
+	return res, num, err
+
+}
+
+//Move to the row of the row group, the next reads return at most rowsLeft rows (-1 for all)
+func (cbt *ColumnBufferType) seekToRow(rowGroupIndex int64, row int64, rowsLeft int64) error {
+	cbt.RowGroupIndex = rowGroupIndex
+	cbt.DataTable = nil
+	cbt.DataTableNumRows = -1
+	cbt.rowsLeft = -1
+	if err := cbt.NextRowGroup(); err != nil {
+		return err
+	}
+
+	if cbt.ChunkHeader != nil && row > 0 {
+		firstRow, err := cbt.seekPage(row)
+		if err != nil {
+			return err
+		}
+		row -= firstRow
+	}
+	if _, err := cbt.SkipRows(row); err != nil {
+		return err
+	}
+	cbt.rowsLeft = rowsLeft
+	return nil
+}
+
+/*
+seekPage jumps to the page of the row in the column chunk with the offset index and returns the first row of the page.
+The pages of repeated columns start at rows, their values before the page are counted from the page headers.
+Older versions of this library wrote the number of values before the page as the first row of repeated columns,
+these offset indexes can't be told from the ones of rows with one value, so such pages are skipped by SkipRows.
+*/
+func (cbt *ColumnBufferType) seekPage(row int64) (int64, error) {
+	if cbt.ChunkHeader.OffsetIndexOffset == nil {
+		return 0, nil
+	}
+	schemaHandler, pathStr := cbt.fileColumn()
+	maxRL, _ := schemaHandler.MaxRepetitionLevel(common.StrToPath(pathStr))
+
+	chunkOffset := cbt.PageOffset
+	protocol := thrift.NewTCompactProtocol(source.ConvertToThriftReader(cbt.PFile, cbt.ChunkHeader.GetOffsetIndexOffset()))
+	offsetIndex := parquet.NewOffsetIndex()
+	if err := offsetIndex.Read(context.TODO(), protocol); err != nil {
+		return 0, err
+	}
+	locations := offsetIndex.GetPageLocations()
+	p := sort.Search(len(locations), func(i int) bool { return locations[i].FirstRowIndex > row }) - 1
+
+	//values read before the page
+	readValues := int64(0)
+	if p > 0 {
+		readValues = locations[p].FirstRowIndex
+	}
+	if p > 0 && maxRL > 0 {
+		values, err := cbt.pageValues(locations[:p])
+		if err != nil {
+			return 0, err
+		}
+		if values == locations[p].FirstRowIndex {
+			p = 0
+		}
+		readValues = values
+	}
 
+	cbt.ThriftReader = source.ConvertToThriftReader(cbt.PFile, chunkOffset)
+	if p <= 0 {
+		return 0, nil
+	}
+
+	//the dictionary page is before the first data page
+	meta := cbt.ChunkHeader.MetaData
+	if meta.DictionaryPageOffset != nil && meta.GetDictionaryPageOffset() < locations[0].Offset {
+		if err := cbt.ReadPage(); err != nil {
+			return 0, err
+		}
+		if cbt.DictPage == nil {
+			return 0, fmt.Errorf("[seekPage] no dictionary page at %v: %v", meta.GetDictionaryPageOffset(), cbt.PathStr)
+		}
+	}
+
+	cbt.ThriftReader = source.ConvertToThriftReader(cbt.PFile, locations[p].Offset)
+	cbt.PageOffset = locations[p].Offset
+	cbt.ChunkReadValues = readValues
+	return locations[p].FirstRowIndex, nil
+}
+
+//Number of values of the pages, read from their headers
+func (cbt *ColumnBufferType) pageValues(locations []*parquet.PageLocation) (int64, error) {
+	var res int64
+	for _, location := range locations {
+		header, err := layout.ReadPageHeader(source.ConvertToThriftReader(cbt.PFile, location.Offset))
+		if err != nil {
+			return 0, err
+		}
+		if header.IsSetDataPageHeaderV2() {
+			res += int64(header.GetDataPageHeaderV2().GetNumValues())
+		} else {
+			res += int64(header.GetDataPageHeader().GetNumValues())
+		}
+	}
+	return res, nil
 }
@@ -0,0 +1,74 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type benchmarkEntry struct {
+	PlainInt64     int64  `parquet:"name=plain_int64, type=INT64"`
+	DeltaInt64     int64  `parquet:"name=delta_int64, type=INT64, encoding=DELTA_BINARY_PACKED"`
+	PlainByteArray string `parquet:"name=plain_byte_array, type=BYTE_ARRAY"`
+	DeltaByteArray string `parquet:"name=delta_byte_array, type=BYTE_ARRAY, encoding=DELTA_BYTE_ARRAY"`
+}
+
+func BenchmarkColumnBufferReadRows(b *testing.B) {
+	const numRows = 100000
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriter(writerfile.NewWriterFile(&buf), new(benchmarkEntry), 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < numRows; i++ {
+		s := fmt.Sprintf("value-%08d", i)
+		if err = pw.Write(benchmarkEntry{int64(i) * 1000, int64(i) * 1000, s, s}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err = pw.WriteStop(); err != nil {
+		b.Fatal(err)
+	}
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	if err != nil {
+		b.Fatal(err)
+	}
+	pr, err := NewParquetReader(pf, nil, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for i, name := range []string{"plain_int64", "delta_int64", "plain_byte_array", "delta_byte_array"} {
+		pathStr := pr.SchemaHandler.ValueColumns[i]
+		//typed is the path of the reader to the structs, boxed is the exported ReadRows
+		b.Run(name+"/typed", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				cb, err := NewColumnBuffer(pf, pr.Footer, pr.SchemaHandler, pathStr)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, n, err := cb.readRows(numRows); err != nil || n != numRows {
+					b.Fatalf("read %v rows: %v", n, err)
+				}
+			}
+		})
+		b.Run(name+"/boxed", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				cb, err := NewColumnBuffer(pf, pr.Footer, pr.SchemaHandler, pathStr)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, n, err := cb.ReadRows(numRows); err != nil || n != numRows {
+					b.Fatalf("read %v rows: %v", n, err)
+				}
+			}
+		})
+	}
+}
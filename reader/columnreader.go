@@ -2,26 +2,39 @@ package reader
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/xitongsys/parquet-go/schema"
 	"github.com/xitongsys/parquet-go/source"
 )
 
 // NewParquetColumnReader creates a parquet column reader
-func NewParquetColumnReader(pFile source.ParquetFile, np int64) (*ParquetReader, error) {
+func NewParquetColumnReader(pFile source.ParquetFile, np int64, opts ...ParquetReaderOption) (*ParquetReader, error) {
 	res := new(ParquetReader)
 	res.NP = np
 	res.PFile = pFile
+	for _, opt := range opts {
+		opt(res)
+	}
 	if err := res.ReadFooter(); err != nil {
 		return nil, err
 	}
+	if err := res.setRowSelection(); err != nil {
+		return nil, err
+	}
 	res.ColumnBuffers = make(map[string]*ColumnBufferType)
 	res.SchemaHandler = schema.NewSchemaHandlerFromSchemaList(res.Footer.GetSchema())
+	res.fileSchemaHandler = res.SchemaHandler
 	res.RenameSchema()
 
 	return res, nil
 }
 
+// NewParquetColumnReaderFromReaderAt creates a parquet column reader of the size bytes of r, see NewParquetReaderFromReaderAt
+func NewParquetColumnReaderFromReaderAt(r io.ReaderAt, size int64, np int64, opts ...ParquetReaderOption) (*ParquetReader, error) {
+	return NewParquetColumnReader(source.NewReaderAtFile(r, size), np, opts...)
+}
+
 func (pr *ParquetReader) SkipRowsByPath(pathStr string, num int64) error {
 	errPathNotFound := fmt.Errorf("path %v not found", pathStr)
 
@@ -36,19 +49,16 @@ func (pr *ParquetReader) SkipRowsByPath(pathStr string, num int64) error {
 
 	if _, ok := pr.ColumnBuffers[pathStr]; !ok {
 		var err error
-		if pr.ColumnBuffers[pathStr], err = NewColumnBuffer(pr.PFile, pr.Footer, pr.SchemaHandler, pathStr); err != nil {
+		if pr.ColumnBuffers[pathStr], err = pr.newColumnBuffer(pathStr); err != nil {
 			return err
 		}
 	}
 
 	if cb, ok := pr.ColumnBuffers[pathStr]; ok {
-		cb.SkipRows(int64(num))
-
-	} else {
-		return errPathNotFound
+		_, err = cb.SkipRows(int64(num))
+		return err
 	}
-
-	return nil
+	return errPathNotFound
 }
 
 func (pr *ParquetReader) SkipRowsByIndex(index int64, num int64) {
@@ -74,14 +84,14 @@ func (pr *ParquetReader) ReadColumnByPath(pathStr string, num int64) (values []i
 
 	if _, ok := pr.ColumnBuffers[pathStr]; !ok {
 		var err error
-		if pr.ColumnBuffers[pathStr], err = NewColumnBuffer(pr.PFile, pr.Footer, pr.SchemaHandler, pathStr); err != nil {
+		if pr.ColumnBuffers[pathStr], err = pr.newColumnBuffer(pathStr); err != nil {
 			return []interface{}{}, []int32{}, []int32{}, err
 		}
 	}
 
 	if cb, ok := pr.ColumnBuffers[pathStr]; ok {
-		table, _ := cb.ReadRows(int64(num))
-		return table.Values, table.RepetitionLevels, table.DefinitionLevels, nil
+		table, _, err := cb.ReadRows(int64(num))
+		return table.Values, table.RepetitionLevels, table.DefinitionLevels, err
 	}
 	return []interface{}{}, []int32{}, []int32{}, errPathNotFound
 }
@@ -95,3 +105,21 @@ func (pr *ParquetReader) ReadColumnByIndex(index int64, num int64) (values []int
 	pathStr := pr.SchemaHandler.ValueColumns[index]
 	return pr.ReadColumnByPath(pathStr, num)
 }
+
+// SkipRowsByFieldID skips rows of the column with the field_id.
+func (pr *ParquetReader) SkipRowsByFieldID(fieldID int32, num int64) error {
+	pathStr, err := pr.SchemaHandler.GetPathByFieldID(fieldID)
+	if err != nil {
+		return err
+	}
+	return pr.SkipRowsByPath(pathStr, num)
+}
+
+// ReadColumnByFieldID reads the column with the field_id, e.g. the field_id of an Iceberg column.
+func (pr *ParquetReader) ReadColumnByFieldID(fieldID int32, num int64) (values []interface{}, rls []int32, dls []int32, err error) {
+	pathStr, err := pr.SchemaHandler.GetPathByFieldID(fieldID)
+	if err != nil {
+		return []interface{}{}, []int32{}, []int32{}, err
+	}
+	return pr.ReadColumnByPath(pathStr, num)
+}
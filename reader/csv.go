@@ -0,0 +1,169 @@
+package reader
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/types"
+)
+
+//CSVReader reads the rows of flat parquet files as CSV records (RFC 4180)
+type CSVReader struct {
+	ParquetReader
+
+	delimiter       rune
+	header          bool
+	nullToken       string
+	timestampLayout string
+	flatten         bool
+	readerOpts      []ParquetReaderOption
+
+	//column paths and names in the order of the schema
+	pathStrs      []string
+	names         []string
+	headerWritten bool
+}
+
+type CSVReaderOption func(*CSVReader)
+
+//WithCSVDelimiter sets the field delimiter, default is ','
+func WithCSVDelimiter(delimiter rune) CSVReaderOption {
+	return func(r *CSVReader) {
+		r.delimiter = delimiter
+	}
+}
+
+//WithCSVHeader writes the column names as the first record, default is true
+func WithCSVHeader(header bool) CSVReaderOption {
+	return func(r *CSVReader) {
+		r.header = header
+	}
+}
+
+//WithCSVNullToken sets the text of the null values, default is empty
+func WithCSVNullToken(nullToken string) CSVReaderOption {
+	return func(r *CSVReader) {
+		r.nullToken = nullToken
+	}
+}
+
+//WithCSVTimestampFormat sets the time layout of the TIMESTAMP and INT96 values, default is RFC3339
+func WithCSVTimestampFormat(layout string) CSVReaderOption {
+	return func(r *CSVReader) {
+		r.timestampLayout = layout
+	}
+}
+
+//WithCSVFlatten reads the columns of nested groups, which are named by their dotted paths. Repeated fields can't be flattened.
+func WithCSVFlatten(flatten bool) CSVReaderOption {
+	return func(r *CSVReader) {
+		r.flatten = flatten
+	}
+}
+
+//WithCSVParquetReaderOptions sets the options of the underlying ParquetReader
+func WithCSVParquetReaderOptions(opts ...ParquetReaderOption) CSVReaderOption {
+	return func(r *CSVReader) {
+		r.readerOpts = append(r.readerOpts, opts...)
+	}
+}
+
+//Create CSV reader
+func NewCSVReader(pFile source.ParquetFile, np int64, opts ...CSVReaderOption) (*CSVReader, error) {
+	res := &CSVReader{
+		delimiter: ',',
+		header:    true,
+	}
+	for _, opt := range opts {
+		opt(res)
+	}
+
+	pr, err := NewParquetReader(pFile, nil, np, res.readerOpts...)
+	if err != nil {
+		return nil, err
+	}
+	res.ParquetReader = *pr
+
+	sh := res.SchemaHandler
+	for idx := int32(1); idx < int32(len(sh.SchemaElements)); idx++ {
+		se := sh.SchemaElements[idx]
+		exPath := common.StrToPath(sh.InPathToExPath[sh.IndexMap[idx]])[1:]
+		name := strings.Join(exPath, ".")
+		if se.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+			return nil, fmt.Errorf("can't read repeated field %v as CSV", name)
+		}
+		if se.GetNumChildren() > 0 {
+			if !res.flatten {
+				return nil, fmt.Errorf("can't read nested field %v as CSV, flatten it with WithCSVFlatten", name)
+			}
+			continue
+		}
+		res.pathStrs = append(res.pathStrs, sh.IndexMap[idx])
+		res.names = append(res.names, name)
+	}
+	return res, nil
+}
+
+//Column names of the CSV records
+func (r *CSVReader) Header() []string {
+	return r.names
+}
+
+//ReadCSV reads num rows and writes them to w as CSV records, the header is written before the first row.
+//It returns the number of written rows, which is 0 at the end of the file.
+//The records written before an error are flushed to w.
+func (r *CSVReader) ReadCSV(w io.Writer, num int) (int, error) {
+	cw := csv.NewWriter(w)
+	cw.Comma = r.delimiter
+	n, err := r.writeRecords(cw, num)
+	cw.Flush()
+	if err != nil {
+		return n, err
+	}
+	return n, cw.Error()
+}
+
+func (r *CSVReader) writeRecords(cw *csv.Writer, num int) (int, error) {
+	if r.header && !r.headerWritten {
+		if err := cw.Write(r.names); err != nil {
+			return 0, err
+		}
+		r.headerWritten = true
+	}
+
+	tmap, err := r.readTables(num, "")
+	if err != nil {
+		return 0, err
+	}
+
+	for _, table := range tmap {
+		table.BoxValues()
+	}
+	ln := 0
+	if len(r.pathStrs) > 0 {
+		ln = len(tmap[r.pathStrs[0]].Values)
+	}
+	record := make([]string, len(r.pathStrs))
+	for i := 0; i < ln; i++ {
+		for j, pathStr := range r.pathStrs {
+			table := tmap[pathStr]
+			if table.Values[i] == nil {
+				record[j] = r.nullToken
+				continue
+			}
+			se := r.SchemaHandler.SchemaElements[r.SchemaHandler.MapIndex[pathStr]]
+			if record[j], err = types.ParquetTypeToText(table.Values[i], se, r.timestampLayout); err != nil {
+				return i, err
+			}
+		}
+		if err = cw.Write(record); err != nil {
+			return i, err
+		}
+	}
+	return ln, nil
+}
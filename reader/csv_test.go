@@ -0,0 +1,100 @@
+package reader
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+func TestCSVReader(t *testing.T) {
+	md := []string{
+		"name=Name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		"name=Age, type=INT32, repetitiontype=OPTIONAL",
+		"name=Time, type=INT64, convertedtype=TIMESTAMP_MILLIS",
+		"name=Price, type=INT64, convertedtype=DECIMAL, scale=2, precision=10",
+	}
+	var buf bytes.Buffer
+	cw, err := writer.NewCSVWriterFromWriter(md, &buf, 1)
+	assert.NoError(t, err)
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+	assert.NoError(t, cw.Write([]interface{}{"Harry \"S\", Truman", int32(38), ts, int64(12345)}))
+	assert.NoError(t, cw.Write([]interface{}{nil, nil, ts, int64(-5)}))
+	assert.NoError(t, cw.WriteStop())
+
+	testData := []struct {
+		opts     []CSVReaderOption
+		expected string
+	}{
+		{nil, "Name,Age,Time,Price\n\"Harry \"\"S\"\", Truman\",38,2021-03-04T05:06:07,123.45\n,,2021-03-04T05:06:07,-0.05\n"},
+		{
+			[]CSVReaderOption{WithCSVDelimiter(';'), WithCSVHeader(false), WithCSVNullToken("NULL"), WithCSVTimestampFormat("2006-01-02 15:04")},
+			"\"Harry \"\"S\"\", Truman\";38;2021-03-04 05:06;123.45\nNULL;NULL;2021-03-04 05:06;-0.05\n",
+		},
+	}
+
+	for _, data := range testData {
+		pf, err := buffer.NewBufferFile(buf.Bytes())
+		assert.NoError(t, err)
+		cr, err := NewCSVReader(pf, 1, data.opts...)
+		assert.NoError(t, err)
+		var out bytes.Buffer
+		n, err := cr.ReadCSV(&out, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		n, err = cr.ReadCSV(&out, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		n, err = cr.ReadCSV(&out, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, data.expected, out.String())
+	}
+}
+
+func TestCSVReaderNested(t *testing.T) {
+	type Entry struct {
+		ID    int64 `parquet:"name=id, type=INT64"`
+		Child *struct {
+			Name string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		} `parquet:"name=child"`
+	}
+	type ListEntry struct {
+		Tags []int32 `parquet:"name=tags, type=INT32, repetitiontype=REPEATED"`
+	}
+
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(Entry), 1)
+	assert.NoError(t, err)
+	entry := Entry{ID: 1}
+	entry.Child = &struct {
+		Name string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	}{"a"}
+	assert.NoError(t, pw.Write(entry))
+	assert.NoError(t, pw.Write(Entry{ID: 2}))
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	_, err = NewCSVReader(pf, 1)
+	assert.EqualError(t, err, "can't read nested field child as CSV, flatten it with WithCSVFlatten")
+
+	cr, err := NewCSVReader(pf, 1, WithCSVFlatten(true))
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	_, err = cr.ReadCSV(&out, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,child.name\n1,a\n2,\n", out.String())
+
+	buf.Reset()
+	pw, err = writer.NewParquetWriterFromWriter(&buf, new(ListEntry), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, pw.WriteStop())
+	pf, err = buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	_, err = NewCSVReader(pf, 1, WithCSVFlatten(true))
+	assert.EqualError(t, err, "can't read repeated field tags as CSV")
+}
@@ -1,6 +1,7 @@
 package reader
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"io"
@@ -28,18 +29,86 @@ type ParquetReader struct {
 	//One reader can only read one type objects
 	ObjType        reflect.Type
 	ObjPartialType reflect.Type
+
+	verifyPageChecksum bool
+	matchByFieldID     bool
+
+	//Paths and field IDs of the fields to read, and their leaf columns (nil for all the columns)
+	projection         []string
+	projectionFieldIDs []int32
+	projectedColumns   map[string]bool
+
+	//Row groups and rows [rowStart, rowEnd) to read, the selected row groups (nil for all)
+	//and the row the new column buffers start at
+	rowGroups         []int
+	rowStart, rowEnd  int64
+	hasRowRange       bool
+	selectedRowGroups []bool
+	seekRow           int64
+	//Row groups of the footer, see RowGroups
+	rowGroupInfos []RowGroupInfo
+
+	//Schema of the file and how the columns of SchemaHandler are read from it
+	fileSchemaHandler *schema.SchemaHandler
+	resolutions       map[string]*columnResolution
+}
+
+type ParquetReaderOption func(*ParquetReader)
+
+// WithVerifyPageChecksum enables the verification of page CRC32 checksums.
+// A mismatch is returned as *layout.PageChecksumError.
+func WithVerifyPageChecksum(verify bool) ParquetReaderOption {
+	return func(pr *ParquetReader) {
+		pr.verifyPageChecksum = verify
+	}
+}
+
+// WithMatchByFieldID matches the columns of the target schema with the columns of the file
+// by field_id instead of name. The fields without field_id are still matched by name.
+func WithMatchByFieldID(match bool) ParquetReaderOption {
+	return func(pr *ParquetReader) {
+		pr.matchByFieldID = match
+	}
+}
+
+// WithProjection reads only the columns under the paths (with the root, like ReadColumnByPath,
+// e.g. common.ReformPathStr("parquet_go_root.address")). The column chunks of the other columns
+// aren't read and their fields are left at zero values.
+func WithProjection(paths ...string) ParquetReaderOption {
+	return func(pr *ParquetReader) {
+		pr.projection = append(pr.projection, paths...)
+	}
 }
 
-//Create a parquet reader: obj is a object with schema tags or a JSON schema string
-func NewParquetReader(pFile source.ParquetFile, obj interface{}, np int64) (*ParquetReader, error) {
+// WithProjectionFieldIDs reads only the columns under the fields with the field IDs, like WithProjection.
+func WithProjectionFieldIDs(fieldIDs ...int32) ParquetReaderOption {
+	return func(pr *ParquetReader) {
+		pr.projectionFieldIDs = append(pr.projectionFieldIDs, fieldIDs...)
+	}
+}
+
+/*
+Create a parquet reader: obj is a object with schema tags or a JSON schema string.
+The schema of obj can differ from the schema of the file: the columns missing in the file
+are read as null, the other columns of the file are ignored and INT32/FLOAT columns can be
+read as INT64/DOUBLE. Incompatible columns are returned as *SchemaConflictError.
+*/
+func NewParquetReader(pFile source.ParquetFile, obj interface{}, np int64, opts ...ParquetReaderOption) (*ParquetReader, error) {
 	var err error
 	res := new(ParquetReader)
 	res.NP = np
 	res.PFile = pFile
+	for _, opt := range opts {
+		opt(res)
+	}
 	if err = res.ReadFooter(); err != nil {
 		return nil, err
 	}
+	if err = res.setRowSelection(); err != nil {
+		return nil, err
+	}
 	res.ColumnBuffers = make(map[string]*ColumnBufferType)
+	res.fileSchemaHandler = schema.NewSchemaHandlerFromSchemaList(res.Footer.Schema)
 
 	if obj != nil {
 		if sa, ok := obj.(string); ok {
@@ -58,15 +127,24 @@ func NewParquetReader(pFile source.ParquetFile, obj interface{}, np int64) (*Par
 		}
 
 	} else {
-		res.SchemaHandler = schema.NewSchemaHandlerFromSchemaList(res.Footer.Schema)
+		res.SchemaHandler = res.fileSchemaHandler
 	}
 
+	if err = res.resolveSchema(); err != nil {
+		return res, err
+	}
 	res.RenameSchema()
+	if err = res.setProjection(); err != nil {
+		return res, err
+	}
 	for i := 0; i < len(res.SchemaHandler.SchemaElements); i++ {
 		schema := res.SchemaHandler.SchemaElements[i]
 		if schema.GetNumChildren() == 0 {
 			pathStr := res.SchemaHandler.IndexMap[int32(i)]
-			if res.ColumnBuffers[pathStr], err = NewColumnBuffer(pFile, res.Footer, res.SchemaHandler, pathStr); err != nil {
+			if !res.isProjected(pathStr) {
+				continue
+			}
+			if res.ColumnBuffers[pathStr], err = res.newColumnBuffer(pathStr); err != nil {
 				return res, err
 			}
 		}
@@ -75,19 +153,36 @@ func NewParquetReader(pFile source.ParquetFile, obj interface{}, np int64) (*Par
 	return res, nil
 }
 
+// NewParquetReaderFromReaderAt creates a parquet reader of the size bytes of r like NewParquetReader.
+// The column buffers share r with positioned reads instead of reopening the file, see source.ReaderAtFile.
+func NewParquetReaderFromReaderAt(r io.ReaderAt, size int64, obj interface{}, np int64, opts ...ParquetReaderOption) (*ParquetReader, error) {
+	return NewParquetReader(source.NewReaderAtFile(r, size), obj, np, opts...)
+}
+
 func (pr *ParquetReader) SetSchemaHandlerFromJSON(jsonSchema string) error {
 	var err error
 
 	if pr.SchemaHandler, err = schema.NewSchemaHandlerFromJSON(jsonSchema); err != nil {
 		return err
 	}
+	if err = pr.resolveSchema(); err != nil {
+		return err
+	}
 
+	pr.ReadStop()
+	pr.ColumnBuffers = make(map[string]*ColumnBufferType)
 	pr.RenameSchema()
+	if err = pr.setProjection(); err != nil {
+		return err
+	}
 	for i := 0; i < len(pr.SchemaHandler.SchemaElements); i++ {
 		schemaElement := pr.SchemaHandler.SchemaElements[i]
 		if schemaElement.GetNumChildren() == 0 {
 			pathStr := pr.SchemaHandler.IndexMap[int32(i)]
-			if pr.ColumnBuffers[pathStr], err = NewColumnBuffer(pr.PFile, pr.Footer, pr.SchemaHandler, pathStr); err != nil {
+			if !pr.isProjected(pathStr) {
+				continue
+			}
+			if pr.ColumnBuffers[pathStr], err = pr.newColumnBuffer(pathStr); err != nil {
 				return err
 			}
 		}
@@ -97,27 +192,105 @@ func (pr *ParquetReader) SetSchemaHandlerFromJSON(jsonSchema string) error {
 
 //Rename schema name to inname
 func (pr *ParquetReader) RenameSchema() {
-	for i := 0; i < len(pr.SchemaHandler.Infos); i++ {
-		pr.Footer.Schema[i].Name = pr.SchemaHandler.Infos[i].InName
+	fileSchemaHandler := pr.fileSchemaHandler
+	if fileSchemaHandler == nil {
+		fileSchemaHandler = pr.SchemaHandler
+	}
+	for i := 0; i < len(fileSchemaHandler.Infos) && i < len(pr.Footer.Schema); i++ {
+		pr.Footer.Schema[i].Name = fileSchemaHandler.Infos[i].InName
 	}
 	for _, rowGroup := range pr.Footer.RowGroups {
 		for _, chunk := range rowGroup.Columns {
 			exPath := make([]string, 0)
-			exPath = append(exPath, pr.SchemaHandler.GetRootExName())
+			exPath = append(exPath, fileSchemaHandler.GetRootExName())
 			exPath = append(exPath, chunk.MetaData.GetPathInSchema()...)
 			exPathStr := common.PathToStr(exPath)
 
-			inPathStr := pr.SchemaHandler.ExPathToInPath[exPathStr]
+			//the path is already renamed
+			inPathStr, ok := fileSchemaHandler.ExPathToInPath[exPathStr]
+			if !ok {
+				continue
+			}
 			inPath := common.StrToPath(inPathStr)[1:]
 			chunk.MetaData.PathInSchema = inPath
 		}
 	}
 }
 
+//Resolve the projection to the leaf columns of the schema handler
+func (pr *ParquetReader) setProjection() error {
+	pr.projectedColumns = nil
+	if len(pr.projection) == 0 && len(pr.projectionFieldIDs) == 0 {
+		return nil
+	}
+
+	prefixPaths := make([]string, 0, len(pr.projection)+len(pr.projectionFieldIDs))
+	for _, pathStr := range pr.projection {
+		inPathStr, err := pr.SchemaHandler.ConvertToInPathStr(pathStr)
+		if err != nil {
+			return err
+		}
+		prefixPaths = append(prefixPaths, inPathStr)
+	}
+	for _, fieldID := range pr.projectionFieldIDs {
+		inPathStr, err := pr.SchemaHandler.GetPathByFieldID(fieldID)
+		if err != nil {
+			return err
+		}
+		prefixPaths = append(prefixPaths, inPathStr)
+	}
+
+	pr.projectedColumns = make(map[string]bool)
+	for _, pathStr := range pr.SchemaHandler.ValueColumns {
+		for _, prefixPath := range prefixPaths {
+			if common.IsChildPath(prefixPath, pathStr) {
+				pr.projectedColumns[pathStr] = true
+			}
+		}
+	}
+	return nil
+}
+
+// isProjected reports whether the column of pathStr is read by Read and SkipRows
+func (pr *ParquetReader) isProjected(pathStr string) bool {
+	return pr.projectedColumns == nil || pr.projectedColumns[pathStr]
+}
+
+// newColumnBuffer creates the column buffer of pathStr with the options of the reader
+func (pr *ParquetReader) newColumnBuffer(pathStr string) (*ColumnBufferType, error) {
+	cb, err := newColumnBuffer(pr.PFile, pr.Footer, pr.SchemaHandler, pathStr, pr.resolutions[pathStr], pr.verifyPageChecksum, pr.selectedRowGroups)
+	if err != nil || (pr.selectedRowGroups == nil && pr.seekRow == 0) {
+		return cb, err
+	}
+	return cb, pr.seekColumnBuffer(cb, pr.seekRow)
+}
+
+// GetNumRows returns the number of rows of the file, or of the selected row groups and row range
 func (pr *ParquetReader) GetNumRows() int64 {
+	if pr.selectedRowGroups != nil {
+		return pr.selectedRows(0)
+	}
 	return pr.Footer.GetNumRows()
 }
 
+//Get the value of the key-value metadata of the footer
+func (pr *ParquetReader) GetKeyValueMetadata(key string) (string, bool) {
+	for _, kv := range pr.Footer.GetKeyValueMetadata() {
+		if kv.GetKey() == key {
+			return kv.GetValue(), true
+		}
+	}
+	return "", false
+}
+
+//Get the Avro schema of the file, it's the parquet.avro.schema key-value metadata or converted from the parquet schema
+func (pr *ParquetReader) GetAvroSchema() (string, error) {
+	if avsc, ok := pr.GetKeyValueMetadata(schema.AvroSchemaKey); ok {
+		return avsc, nil
+	}
+	return schema.ConvertToAvroSchema(pr.SchemaHandler)
+}
+
 //Get the footer size
 func (pr *ParquetReader) GetFooterSize() (uint32, error) {
 	var err error
@@ -146,7 +319,11 @@ func (pr *ParquetReader) ReadFooter() error {
 	thriftReader := thrift.NewStreamTransportR(pr.PFile)
 	bufferReader := thrift.NewTBufferedTransport(thriftReader, int(size))
 	protocol := pf.GetProtocol(bufferReader)
-	return pr.Footer.Read(context.TODO(), protocol)
+	if err = pr.Footer.Read(context.TODO(), protocol); err != nil {
+		return err
+	}
+	pr.rowGroupInfos = rowGroupInfos(pr.Footer)
+	return nil
 }
 
 //Skip rows of parquet file
@@ -155,13 +332,14 @@ func (pr *ParquetReader) SkipRows(num int64) error {
 	if num <= 0 {
 		return nil
 	}
+	locker := new(sync.Mutex)
 	doneChan := make(chan int, pr.NP)
 	taskChan := make(chan string, len(pr.SchemaHandler.ValueColumns))
 	stopChan := make(chan int)
 
 	for _, pathStr := range pr.SchemaHandler.ValueColumns {
-		if _, ok := pr.ColumnBuffers[pathStr]; !ok {
-			if pr.ColumnBuffers[pathStr], err = NewColumnBuffer(pr.PFile, pr.Footer, pr.SchemaHandler, pathStr); err != nil {
+		if _, ok := pr.ColumnBuffers[pathStr]; !ok && pr.isProjected(pathStr) {
+			if pr.ColumnBuffers[pathStr], err = pr.newColumnBuffer(pathStr); err != nil {
 				return err
 			}
 		}
@@ -175,7 +353,13 @@ func (pr *ParquetReader) SkipRows(num int64) error {
 					return
 				case pathStr := <-taskChan:
 					cb := pr.ColumnBuffers[pathStr]
-					cb.SkipRows(int64(num))
+					if _, err2 := cb.SkipRows(int64(num)); err2 != nil {
+						locker.Lock()
+						if err == nil {
+							err = err2
+						}
+						locker.Unlock()
+					}
 					doneChan <- 0
 				}
 			}
@@ -236,6 +420,16 @@ func (pr *ParquetReader) ReadPartial(dstInterface interface{}, prefixPath string
 	return pr.read(dstInterface, prefixPath)
 }
 
+//Read rows of the field with the field_id and unmarshal them to dst, like ReadPartial
+func (pr *ParquetReader) ReadPartialByFieldID(dstInterface interface{}, fieldID int32) error {
+	prefixPath, err := pr.SchemaHandler.GetPathByFieldID(fieldID)
+	if err != nil {
+		return err
+	}
+
+	return pr.read(dstInterface, prefixPath)
+}
+
 // Read maxReadNumber partial objects
 func (pr *ParquetReader) ReadPartialByNumber(maxReadNumber int, prefixPath string) ([]interface{}, error) {
 	var err error
@@ -264,15 +458,60 @@ func (pr *ParquetReader) ReadPartialByNumber(maxReadNumber int, prefixPath strin
 
 //Read rows of parquet file with a prefixPath
 func (pr *ParquetReader) read(dstInterface interface{}, prefixPath string) error {
-	var err error
-	tmap := make(map[string]*layout.Table)
-	locker := new(sync.Mutex)
 	ot := reflect.TypeOf(dstInterface).Elem().Elem()
 	num := reflect.ValueOf(dstInterface).Elem().Len()
 	if num <= 0 {
 		return nil
 	}
 
+	tmap, err := pr.readTables(num, prefixPath)
+	if err != nil {
+		return err
+	}
+
+	dstList := make([]interface{}, pr.NP)
+	delta := (int64(num) + pr.NP - 1) / pr.NP
+
+	var wg sync.WaitGroup
+	for c := int64(0); c < pr.NP; c++ {
+		bgn := c * delta
+		end := bgn + delta
+		if end > int64(num) {
+			end = int64(num)
+		}
+		if bgn >= int64(num) {
+			bgn, end = int64(num), int64(num)
+		}
+		wg.Add(1)
+		go func(b, e, index int) {
+			defer func() {
+				wg.Done()
+			}()
+
+			dstList[index] = reflect.New(reflect.SliceOf(ot)).Interface()
+			if err2 := marshal.Unmarshal(&tmap, b, e, dstList[index], pr.SchemaHandler, prefixPath); err2 != nil {
+				err = err2
+			}
+		}(int(bgn), int(end), int(c))
+	}
+
+	wg.Wait()
+
+	dstValue := reflect.ValueOf(dstInterface).Elem()
+	dstValue.SetLen(0)
+	for _, dst := range dstList {
+		dstValue.Set(reflect.AppendSlice(dstValue, reflect.ValueOf(dst).Elem()))
+	}
+
+	return err
+}
+
+//Read num rows of the columns with a prefixPath to a table map
+func (pr *ParquetReader) readTables(num int, prefixPath string) (map[string]*layout.Table, error) {
+	tmap := make(map[string]*layout.Table)
+	locker := new(sync.Mutex)
+	var err error
+
 	doneChan := make(chan int, pr.NP)
 	taskChan := make(chan string, len(pr.ColumnBuffers))
 	stopChan := make(chan int)
@@ -285,8 +524,11 @@ func (pr *ParquetReader) read(dstInterface interface{}, prefixPath string) error
 					return
 				case pathStr := <-taskChan:
 					cb := pr.ColumnBuffers[pathStr]
-					table, _ := cb.ReadRows(int64(num))
+					table, _, err2 := cb.readRows(int64(num))
 					locker.Lock()
+					if err2 != nil && err == nil {
+						err = err2
+					}
 					if _, ok := tmap[pathStr]; ok {
 						tmap[pathStr].Merge(table)
 					} else {
@@ -315,41 +557,77 @@ func (pr *ParquetReader) read(dstInterface interface{}, prefixPath string) error
 		stopChan <- 0
 	}
 
-	dstList := make([]interface{}, pr.NP)
-	delta := (int64(num) + pr.NP - 1) / pr.NP
-
-	var wg sync.WaitGroup
-	for c := int64(0); c < pr.NP; c++ {
-		bgn := c * delta
-		end := bgn + delta
-		if end > int64(num) {
-			end = int64(num)
-		}
-		if bgn >= int64(num) {
-			bgn, end = int64(num), int64(num)
-		}
-		wg.Add(1)
-		go func(b, e, index int) {
-			defer func() {
-				wg.Done()
-			}()
-
-			dstList[index] = reflect.New(reflect.SliceOf(ot)).Interface()
-			if err2 := marshal.Unmarshal(&tmap, b, e, dstList[index], pr.SchemaHandler, prefixPath); err2 != nil {
-				err = err2
-			}
-		}(int(bgn), int(end), int(c))
+	if err != nil {
+		return nil, err
 	}
+	return tmap, nil
+}
 
-	wg.Wait()
+//Read num rows as dynamic rows without reflection, the fields are keyed by their original names
+func (pr *ParquetReader) ReadRows(num int) ([]marshal.Row, error) {
+	if num <= 0 {
+		return []marshal.Row{}, nil
+	}
+	tmap, err := pr.readTables(num, "")
+	if err != nil {
+		return nil, err
+	}
+	return marshal.UnmarshalRows(&tmap, 0, num, pr.SchemaHandler)
+}
 
-	dstValue := reflect.ValueOf(dstInterface).Elem()
-	dstValue.SetLen(0)
-	for _, dst := range dstList {
-		dstValue.Set(reflect.AppendSlice(dstValue, reflect.ValueOf(dst).Elem()))
+//Read num rows as JSON objects with the original names as keys, the logical types are formatted by types.ParquetTypeToJSONType
+//The binary values are base64, the rows are written back by marshal.MarshalJSONBase64, not by marshal.MarshalJSON
+func (pr *ParquetReader) ReadJSON(num int) ([]string, error) {
+	rows, err := pr.ReadRows(num)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, len(rows))
+	for i, row := range rows {
+		bs, err := marshal.RowToJSON(row, pr.SchemaHandler)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = string(bs)
 	}
+	return res, nil
+}
 
-	return err
+//Number of rows read and written at a time by ReadNDJSON
+const ndjsonBatchSize = 1024
+
+//Read num rows and write them to w as NDJSON, one JSON object per line. It returns the number of written rows.
+//The rows are read and written in batches, so only a batch is kept in memory.
+func (pr *ParquetReader) ReadNDJSON(w io.Writer, num int) (int, error) {
+	written := 0
+	var line bytes.Buffer
+	for written < num {
+		batch := num - written
+		if batch > ndjsonBatchSize {
+			batch = ndjsonBatchSize
+		}
+		rows, err := pr.ReadRows(batch)
+		if err != nil {
+			return written, err
+		}
+		line.Reset()
+		for _, row := range rows {
+			bs, err := marshal.RowToJSON(row, pr.SchemaHandler)
+			if err != nil {
+				return written, err
+			}
+			line.Write(bs)
+			line.WriteByte('\n')
+		}
+		if _, err = w.Write(line.Bytes()); err != nil {
+			return written, err
+		}
+		written += len(rows)
+		if len(rows) < batch {
+			break
+		}
+	}
+	return written, nil
 }
 
 //Stop Read
@@ -0,0 +1,511 @@
+package reader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+//testdata/byte_stream_split.parquet is written by testdata/gen_byte_stream_split.py,
+//which doesn't use the encoders of parquet-go
+func TestReadByteStreamSplitFixture(t *testing.T) {
+	type Entry struct {
+		Int32 int32  `parquet:"name=int32, type=INT32"`
+		Int64 *int64 `parquet:"name=int64, type=INT64, repetitiontype=OPTIONAL"`
+		Flba  string `parquet:"name=flba, type=FIXED_LEN_BYTE_ARRAY, length=3"`
+	}
+
+	buf, err := os.ReadFile("testdata/byte_stream_split.parquet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pf, err := buffer.NewBufferFile(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := NewParquetReader(pf, new(Entry), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.ReadStop()
+
+	for _, column := range pr.Footer.RowGroups[0].Columns {
+		if !reflect.DeepEqual(column.MetaData.Encodings, []parquet.Encoding{parquet.Encoding_BYTE_STREAM_SPLIT, parquet.Encoding_RLE}) {
+			t.Errorf("%v: expect BYTE_STREAM_SPLIT encoding, get %v", column.MetaData.PathInSchema, column.MetaData.Encodings)
+		}
+	}
+
+	rows := make([]Entry, pr.GetNumRows())
+	if err = pr.Read(&rows); err != nil {
+		t.Fatal(err)
+	}
+	i64 := func(v int64) *int64 { return &v }
+	expect := []Entry{
+		{1, i64(1), "abc"},
+		{-2, nil, "\x00\x01\x02"},
+		{300000, i64(-1), "xyz"},
+		{2147483647, i64(1 << 40), "\xff\xfe\xfd"},
+		{-2147483648, nil, "123"},
+	}
+	if !reflect.DeepEqual(rows, expect) {
+		t.Errorf("expect %v, get %v", expect, rows)
+	}
+}
+
+func TestResolveSchema(t *testing.T) {
+	type Entry struct {
+		Id    int32   `parquet:"name=id, type=INT32"`
+		Name  *string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Score float32 `parquet:"name=score, type=FLOAT"`
+	}
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriter(writerfile.NewWriterFile(&buf), new(Entry), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "a"
+	for i := 0; i < 3; i++ {
+		if err = pw.Write(Entry{int32(i), &name, float32(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = pw.WriteStop(); err != nil {
+		t.Fatal(err)
+	}
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the same schema is read without resolutions
+	pr, err := NewParquetReader(pf, new(Entry), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.resolutions) != 0 {
+		t.Errorf("expect no resolutions, get %v", pr.resolutions)
+	}
+
+	type EntryV2 struct {
+		Id       int32   `parquet:"name=id, type=INT32"`
+		Name     *string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Score    float64 `parquet:"name=score, type=DOUBLE"`
+		Int96    string  `parquet:"name=int96, type=INT96"`
+		Flba     string  `parquet:"name=flba, type=FIXED_LEN_BYTE_ARRAY, length=4"`
+		Optional *int32  `parquet:"name=optional, type=INT32, repetitiontype=OPTIONAL"`
+	}
+	pr, err = NewParquetReader(pf, new(EntryV2), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{}
+	for pathStr := range pr.resolutions {
+		keys = append(keys, pr.SchemaHandler.GetExName(int(pr.SchemaHandler.MapIndex[pathStr])))
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"flba", "int96", "optional", "score"}) {
+		t.Errorf("expect resolutions of flba, int96, optional and score, get %v", keys)
+	}
+
+	rows := make([]EntryV2, 3)
+	if err = pr.Read(&rows); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		expect := EntryV2{int32(i), &name, float64(i), strings.Repeat("\x00", 12), strings.Repeat("\x00", 4), nil}
+		if !reflect.DeepEqual(row, expect) {
+			t.Errorf("expect %v, get %v", expect, row)
+		}
+	}
+}
+
+func TestResolveSchemaPromotion(t *testing.T) {
+	type Entry struct {
+		Unsigned  int32 `parquet:"name=unsigned, type=INT32, convertedtype=UINT_32"`
+		Signed    int32 `parquet:"name=signed, type=INT32"`
+		Timestamp int64 `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+		Date      int32 `parquet:"name=date, type=INT32, convertedtype=DATE"`
+	}
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriter(writerfile.NewWriterFile(&buf), new(Entry), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = pw.Write(Entry{-1, -1, 1000, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err = pw.WriteStop(); err != nil {
+		t.Fatal(err)
+	}
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the unsigned values are zero-extended, the signed ones are sign-extended
+	type Promoted struct {
+		Unsigned int64 `parquet:"name=unsigned, type=INT64, convertedtype=UINT_64"`
+		Signed   int64 `parquet:"name=signed, type=INT64"`
+	}
+	pr, err := NewParquetReader(pf, new(Promoted), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := make([]Promoted, 1)
+	if err = pr.Read(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if expect := (Promoted{4294967295, -1}); rows[0] != expect {
+		t.Errorf("expect %v, get %v", expect, rows[0])
+	}
+
+	//the values of other time units and of DATE aren't timestamps of the target
+	type Retimed struct {
+		Unsigned  int32 `parquet:"name=unsigned, type=INT32"`
+		Timestamp int64 `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+		Date      int64 `parquet:"name=date, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	}
+	_, err = NewParquetReader(pf, new(Retimed), 1)
+	conflictErr, ok := err.(*SchemaConflictError)
+	if !ok {
+		t.Fatalf("expect SchemaConflictError, get %v", err)
+	}
+	expect := []string{
+		"date: can't read DATE as TIMESTAMP(MILLIS,true)",
+		"timestamp: can't read TIMESTAMP(MILLIS,true) as TIMESTAMP(MICROS,true)",
+		"unsigned: can't read INTEGER(32,false) as no logical type",
+	}
+	if !reflect.DeepEqual(conflictErr.Conflicts, expect) {
+		t.Errorf("expect %v, get %v", expect, conflictErr.Conflicts)
+	}
+}
+
+func TestSchemaEvolution(t *testing.T) {
+	type EntryV1 struct {
+		Id    int32    `parquet:"name=id, type=INT32, fieldid=1"`
+		Name  string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=2"`
+		Score *float32 `parquet:"name=score, type=FLOAT, fieldid=3"`
+		Old   string   `parquet:"name=old, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=4"`
+		Tags  []int32  `parquet:"name=tags, type=LIST, valuetype=INT32"`
+	}
+	type EntryV2 struct {
+		Id    int64    `parquet:"name=id, type=INT64"`
+		Name  *string  `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Score *float64 `parquet:"name=score, type=DOUBLE"`
+		New   *string  `parquet:"name=new, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Added int32    `parquet:"name=added, type=INT32"`
+		Tags  []int64  `parquet:"name=tags, type=LIST, valuetype=INT64"`
+	}
+
+	rows := make([]EntryV1, 20)
+	expected := make([]EntryV2, len(rows))
+	for i := range rows {
+		rows[i] = EntryV1{Id: int32(i), Name: fmt.Sprintf("name_%d", i), Old: "old"}
+		name := rows[i].Name
+		expected[i] = EntryV2{Id: int64(i), Name: &name, Tags: []int64{}}
+		if i%2 == 0 {
+			score := float32(i) / 2
+			rows[i].Score = &score
+			score64 := float64(score)
+			expected[i].Score = &score64
+		}
+		for j := 0; j < i%3; j++ {
+			rows[i].Tags = append(rows[i].Tags, int32(j))
+			expected[i].Tags = append(expected[i].Tags, int64(j))
+		}
+	}
+	pf, _ := writeTestFile(t, new(EntryV1), rows, 10, 0)
+
+	pr, err := NewParquetReader(pf, new(EntryV2), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(pr.Footer.RowGroups))
+	entries := make([]EntryV2, len(expected))
+	assert.NoError(t, pr.Read(&entries))
+	assert.Equal(t, expected, entries)
+
+	pr, err = NewParquetReader(pf, new(EntryV2), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, pr.SkipRows(12))
+	entries = make([]EntryV2, 5)
+	assert.NoError(t, pr.Read(&entries))
+	assert.Equal(t, expected[12:17], entries)
+
+	//columns renamed, matched by field_id
+	type EntryV3 struct {
+		Key     int32    `parquet:"name=key, type=INT32, fieldid=1"`
+		Label   string   `parquet:"name=label, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=2"`
+		Legacy  string   `parquet:"name=legacy, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=4"`
+		Unknown *float32 `parquet:"name=unknown, type=FLOAT, fieldid=5"`
+	}
+	pr, err = NewParquetReader(pf, new(EntryV3), 1, WithMatchByFieldID(true))
+	assert.NoError(t, err)
+	entriesV3 := make([]EntryV3, 2)
+	assert.NoError(t, pr.Read(&entriesV3))
+	assert.Equal(t, []EntryV3{{Key: 0, Label: "name_0", Legacy: "old"}, {Key: 1, Label: "name_1", Legacy: "old"}}, entriesV3)
+
+	type EntryConflict struct {
+		Id    string  `parquet:"name=id, type=BYTE_ARRAY"`
+		Score float32 `parquet:"name=score, type=FLOAT"`
+		Name  int64   `parquet:"name=name, type=INT64"`
+	}
+	_, err = NewParquetReader(pf, new(EntryConflict), 1)
+	var conflictErr *SchemaConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, []string{
+		"id: can't read INT32 as BYTE_ARRAY",
+		"name: can't read BYTE_ARRAY as INT64",
+		"score: can't read OPTIONAL as REQUIRED",
+	}, conflictErr.Conflicts)
+}
+
+func TestProjection(t *testing.T) {
+	type Address struct {
+		City string `parquet:"name=city, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Zip  int32  `parquet:"name=zip, type=INT32, fieldid=12"`
+	}
+	type Entry struct {
+		Id      int64    `parquet:"name=id, type=INT64, fieldid=1"`
+		Name    string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Names   []string `parquet:"name=names, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+		Address *Address `parquet:"name=address, fieldid=2"`
+	}
+
+	expected := make([]Entry, 10)
+	for i := range expected {
+		expected[i] = Entry{
+			Id:      int64(i),
+			Name:    fmt.Sprintf("name_%d", i),
+			Names:   []string{"a"},
+			Address: &Address{City: fmt.Sprintf("city_%d", i), Zip: int32(i)},
+		}
+	}
+	pf, _ := writeTestFile(t, new(Entry), expected, 0, 0)
+
+	//"name" doesn't select "names"
+	pr, err := NewParquetReader(pf, new(Entry), 2,
+		WithProjection(common.ReformPathStr("parquet_go_root.name"), "Parquet_go_root\x01Address\x01City"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(pr.ColumnBuffers))
+	assert.NoError(t, pr.SkipRows(2))
+	entries := make([]Entry, 3)
+	assert.NoError(t, pr.Read(&entries))
+	for i, entry := range entries {
+		assert.Equal(t, Entry{Name: expected[i+2].Name, Address: &Address{City: expected[i+2].Address.City}}, entry)
+	}
+
+	pr, err = NewParquetReader(pf, nil, 1, WithProjectionFieldIDs(1, 12))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(pr.ColumnBuffers))
+	records, err := pr.ReadJSON(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`{"id":0,"name":null,"names":null,"address":{"city":null,"zip":0}}`,
+		`{"id":1,"name":null,"names":null,"address":{"city":null,"zip":1}}`,
+	}, records)
+
+	_, err = NewParquetReader(pf, new(Entry), 1, WithProjectionFieldIDs(3))
+	assert.Error(t, err)
+	_, err = NewParquetReader(pf, new(Entry), 1, WithProjection("parquet_go_root\x01unknown"))
+	assert.Error(t, err)
+}
+
+
+func TestRowGroupSelection(t *testing.T) {
+	type Entry struct {
+		Id   int64   `parquet:"name=id, type=INT64"`
+		Name *string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+		Tags []int32 `parquet:"name=tags, type=LIST, valuetype=INT32"`
+	}
+
+	expected := make([]Entry, 400)
+	for i := range expected {
+		expected[i] = Entry{Id: int64(i), Tags: []int32{}}
+		if i%3 != 0 {
+			name := fmt.Sprintf("name_%d", i%7)
+			expected[i].Name = &name
+		}
+		for j := 0; j < i%4; j++ {
+			expected[i].Tags = append(expected[i].Tags, int32(i*10+j))
+		}
+	}
+	pf, pw := writeTestFile(t, new(Entry), expected, 100, 128)
+	//the first rows of the pages are rows of the row group, not values of the repeated column
+	assert.Equal(t, 12, len(pw.OffsetIndexes))
+	for i, offsetIndex := range pw.OffsetIndexes {
+		locations := offsetIndex.PageLocations
+		if i%3 == 2 {
+			assert.True(t, len(locations) > 1)
+		}
+		assert.Equal(t, int64(0), locations[0].FirstRowIndex)
+		assert.True(t, locations[len(locations)-1].FirstRowIndex < 100)
+		//the pages start at rows, a row isn't split across pages
+		for j := 1; j < len(locations); j++ {
+			assert.True(t, locations[j-1].FirstRowIndex < locations[j].FirstRowIndex)
+		}
+	}
+
+	read := func(pr *ParquetReader, num int) []Entry {
+		entries := make([]Entry, num)
+		assert.NoError(t, pr.Read(&entries))
+		return entries
+	}
+
+	pr, err := NewParquetReader(pf, new(Entry), 1)
+	assert.NoError(t, err)
+	rowGroups := pr.RowGroups()
+	assert.Equal(t, 4, len(rowGroups))
+	for i, rowGroup := range rowGroups {
+		assert.Equal(t, i, rowGroup.Index)
+		assert.Equal(t, int64(i*100), rowGroup.FirstRow)
+		assert.Equal(t, int64(100), rowGroup.NumRows)
+		assert.True(t, rowGroup.CompressedSize > 0)
+		if i > 0 {
+			assert.Equal(t, rowGroups[i-1].Offset+rowGroups[i-1].CompressedSize, rowGroup.Offset)
+		}
+	}
+
+	//seek forward and back, across pages and row groups
+	for _, row := range []int64{250, 37, 399, 0, 199, 101} {
+		assert.NoError(t, pr.SeekToRow(row))
+		end := row + 3
+		if end > 400 {
+			end = 400
+		}
+		assert.Equal(t, expected[row:end], read(pr, 3), "row %d", row)
+	}
+	assert.Error(t, pr.SeekToRow(400))
+	//the pages of the repeated column are found by the offset index
+	for row := int64(399); row >= 0; row-- {
+		assert.NoError(t, pr.SeekToRow(row))
+		assert.Equal(t, expected[row:row+1], read(pr, 1), "row %d", row)
+	}
+
+	pr, err = NewParquetReader(pf, new(Entry), 2, WithRowGroups(3, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(200), pr.GetNumRows())
+	assert.Equal(t, append(append([]Entry{}, expected[100:200]...), expected[300:400]...), read(pr, 250))
+	assert.Equal(t, []Entry{}, read(pr, 10))
+	assert.Error(t, pr.SeekToRow(250))
+
+	pr, err = NewParquetReader(pf, new(Entry), 2, WithRowRange(150, 260))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(110), pr.GetNumRows())
+	assert.NoError(t, pr.SkipRows(5))
+	assert.Equal(t, expected[155:260], read(pr, 200))
+	assert.NoError(t, pr.SeekToRow(255))
+	assert.Equal(t, expected[255:260], read(pr, 10))
+	assert.Error(t, pr.SeekToRow(260))
+
+	pr, err = NewParquetReader(pf, new(Entry), 1, WithRowGroups(0, 2), WithRowRange(90, 350))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(110), pr.GetNumRows())
+	assert.Equal(t, append(append([]Entry{}, expected[90:100]...), expected[200:300]...), read(pr, 200))
+
+	pr, err = NewParquetColumnReader(pf, 1, WithRowRange(398, 500))
+	assert.NoError(t, err)
+	values, _, _, err := pr.ReadColumnByPath(common.ReformPathStr("parquet_go_root.id"), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(398), int64(399)}, values)
+
+	_, err = NewParquetReader(pf, new(Entry), 1, WithRowGroups(4))
+	assert.Error(t, err)
+	_, err = NewParquetReader(pf, new(Entry), 1, WithRowRange(10, 5))
+	assert.Error(t, err)
+}
+
+
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestReadNDJSONBatches(t *testing.T) {
+	type Entry struct {
+		Id int64 `parquet:"name=id, type=INT64"`
+	}
+	numRows := 2*ndjsonBatchSize + 10
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriter(writerfile.NewWriterFile(&buf), new(Entry), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < numRows; i++ {
+		if err = pw.Write(Entry{int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = pw.WriteStop(); err != nil {
+		t.Fatal(err)
+	}
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := NewParquetReader(pf, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var w countingWriter
+	n, err := pr.ReadNDJSON(&w, numRows+100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != numRows || w.writes != 3 {
+		t.Errorf("expect %v rows in 3 writes, get %v rows in %v writes", numRows, n, w.writes)
+	}
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	if len(lines) != numRows || lines[numRows-1] != fmt.Sprintf(`{"id":%d}`, numRows-1) {
+		t.Errorf("expect %v lines ending with id %v, get %v lines ending with %v", numRows, numRows-1, len(lines), lines[len(lines)-1])
+	}
+}
+
+//writeTestFile writes the rows, a slice of obj, to a file in memory.
+//A row group is flushed every rowGroupRows rows if it isn't 0, pageSize 0 is the default page size.
+func writeTestFile(t *testing.T, obj interface{}, rows interface{}, rowGroupRows int, pageSize int64) (source.ParquetFile, *writer.ParquetWriter) {
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriter(writerfile.NewWriterFile(&buf), obj, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pageSize > 0 {
+		pw.PageSize = pageSize
+	}
+	values := reflect.ValueOf(rows)
+	for i := 0; i < values.Len(); i++ {
+		if err = pw.Write(values.Index(i).Interface()); err != nil {
+			t.Fatal(err)
+		}
+		if rowGroupRows > 0 && (i+1)%rowGroupRows == 0 {
+			if err = pw.Flush(true); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err = pw.WriteStop(); err != nil {
+		t.Fatal(err)
+	}
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pf, pw
+}
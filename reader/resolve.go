@@ -0,0 +1,259 @@
+package reader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/layout"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+//SchemaConflictError is returned when the file can't be read with the target schema
+type SchemaConflictError struct {
+	Conflicts []string
+}
+
+func (e *SchemaConflictError) Error() string {
+	return "schema conflicts: " + strings.Join(e.Conflicts, "; ")
+}
+
+//columnResolution maps a column of the target schema to the column of the file
+type columnResolution struct {
+	//File schema and the in path of the column in it. pathStr is empty if the file doesn't have the column.
+	schemaHandler *schema.SchemaHandler
+	pathStr       string
+
+	//Target definition level of each definition level of the file, nil if they are the same
+	definitionLevels []int32
+	//Promote INT32 to INT64 and FLOAT to DOUBLE, the unsigned INT32 values are zero-extended
+	promote  bool
+	unsigned bool
+	//Value of a missing column without optional or repeated fields
+	zero interface{}
+}
+
+func (r *columnResolution) value(v interface{}) interface{} {
+	if !r.promote {
+		return v
+	}
+	switch x := v.(type) {
+	case int32:
+		if r.unsigned {
+			return int64(uint32(x))
+		}
+		return int64(x)
+	case float32:
+		return float64(x)
+	}
+	return v
+}
+
+//Key of the schema element to match the file and the target schemas: field_id or name.
+//Older files of this library have field_id 0 for all the fields, it's the same as no field_id, see schema.LegacyFieldIDs.
+func elementKey(sh *schema.SchemaHandler, idx int32, matchByFieldID bool) string {
+	if matchByFieldID && sh.HasFieldID(idx) {
+		return fmt.Sprintf("#%d", sh.SchemaElements[idx].GetFieldID())
+	}
+	return sh.GetExName(int(idx))
+}
+
+//Keys of all the elements under the root, joined by the path delimiter
+func elementKeyPaths(sh *schema.SchemaHandler, matchByFieldID bool) map[string]int32 {
+	res := make(map[string]int32)
+	for idx, pathStr := range sh.IndexMap {
+		path := common.StrToPath(pathStr)
+		keys := make([]string, 0, len(path)-1)
+		for i := 2; i <= len(path); i++ {
+			keys = append(keys, elementKey(sh, sh.MapIndex[common.PathToStr(path[:i])], matchByFieldID))
+		}
+		res[common.PathToStr(keys)] = idx
+	}
+	return res
+}
+
+/*
+resolveSchema matches the columns of the target schema (pr.SchemaHandler) with the columns of the file.
+Columns missing in the file are read as null (or zero values if they are required),
+the columns of the file missing in the target schema are ignored.
+The columns are matched by name, or by field_id if the reader is created WithMatchByFieldID.
+*/
+func (pr *ParquetReader) resolveSchema() error {
+	pr.resolutions = make(map[string]*columnResolution)
+	if pr.fileSchemaHandler == nil || pr.fileSchemaHandler == pr.SchemaHandler {
+		return nil
+	}
+
+	fileSH, targetSH := pr.fileSchemaHandler, pr.SchemaHandler
+	fileKeys := elementKeyPaths(fileSH, pr.matchByFieldID)
+	conflicts := []string{}
+
+	for targetKey, targetIdx := range elementKeyPaths(targetSH, pr.matchByFieldID) {
+		if targetIdx == 0 {
+			continue
+		}
+		targetPathStr := targetSH.IndexMap[targetIdx]
+		exPath := common.StrToPath(targetSH.InPathToExPath[targetPathStr])
+		name := strings.Join(exPath[1:], ".")
+		target := targetSH.SchemaElements[targetIdx]
+		targetIsLeaf := target.GetNumChildren() == 0
+
+		fileIdx, ok := fileKeys[targetKey]
+		if !ok {
+			if targetIsLeaf {
+				pr.resolutions[targetPathStr] = missingColumnResolution(fileSH, targetSH, targetPathStr)
+			}
+			continue
+		}
+		file := fileSH.SchemaElements[fileIdx]
+
+		if fileConflicts := schema.ReadConflicts(file, target); len(fileConflicts) > 0 {
+			for _, conflict := range fileConflicts {
+				conflicts = append(conflicts, name+": "+conflict)
+			}
+			continue
+		}
+		if !targetIsLeaf {
+			continue
+		}
+
+		resolution := &columnResolution{
+			schemaHandler: fileSH,
+			pathStr:       fileSH.IndexMap[fileIdx],
+			promote:       file.GetType() != target.GetType(),
+			unsigned:      isUnsigned(file),
+		}
+
+		//the target can have more optional fields on the path
+		filePath, targetPath := common.StrToPath(resolution.pathStr), common.StrToPath(targetPathStr)
+		var fileDL, targetDL int32
+		for i := 2; i <= len(targetPath); i++ {
+			if fileSH.SchemaElements[fileSH.MapIndex[common.PathToStr(filePath[:i])]].GetRepetitionType() != parquet.FieldRepetitionType_REQUIRED {
+				resolution.definitionLevels = append(resolution.definitionLevels, targetDL)
+				fileDL++
+			}
+			if targetSH.SchemaElements[targetSH.MapIndex[common.PathToStr(targetPath[:i])]].GetRepetitionType() != parquet.FieldRepetitionType_REQUIRED {
+				targetDL++
+			}
+		}
+		resolution.definitionLevels = append(resolution.definitionLevels, targetDL)
+		if sameLevels(resolution.definitionLevels) {
+			resolution.definitionLevels = nil
+		}
+		//the column is read as it is
+		if !resolution.promote && resolution.definitionLevels == nil && resolution.pathStr == targetPathStr {
+			continue
+		}
+		pr.resolutions[targetPathStr] = resolution
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return &SchemaConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+//UINT_* converted types or unsigned INTEGER logical types
+func isUnsigned(se *parquet.SchemaElement) bool {
+	lt := se.LogicalType
+	if lt == nil {
+		lt = common.NewLogicalTypeFromConvertedType(se, &common.Tag{})
+	}
+	return lt != nil && lt.IsSetINTEGER() && !lt.INTEGER.IsSigned
+}
+
+func sameLevels(levels []int32) bool {
+	for i, dl := range levels {
+		if dl != int32(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func missingColumnResolution(fileSH *schema.SchemaHandler, targetSH *schema.SchemaHandler, pathStr string) *columnResolution {
+	res := &columnResolution{schemaHandler: fileSH}
+	if maxDL, _ := targetSH.MaxDefinitionLevel(common.StrToPath(pathStr)); maxDL == 0 {
+		res.zero = zeroValue(targetSH.SchemaElements[targetSH.MapIndex[pathStr]])
+	}
+	return res
+}
+
+func zeroValue(se *parquet.SchemaElement) interface{} {
+	switch se.GetType() {
+	case parquet.Type_BOOLEAN:
+		return false
+	case parquet.Type_INT32:
+		return int32(0)
+	case parquet.Type_INT64:
+		return int64(0)
+	case parquet.Type_FLOAT:
+		return float32(0)
+	case parquet.Type_DOUBLE:
+		return float64(0)
+	case parquet.Type_INT96:
+		return strings.Repeat("\x00", 12)
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		return strings.Repeat("\x00", int(se.GetTypeLength()))
+	}
+	return ""
+}
+
+//Convert the table read with the file schema to the target schema
+func (cbt *ColumnBufferType) resolveTable(src *layout.Table) *layout.Table {
+	r := cbt.resolution
+	if r == nil || src == nil {
+		return src
+	}
+	index := cbt.SchemaHandler.MapIndex[cbt.PathStr]
+	path := common.StrToPath(cbt.PathStr)
+	//only the path differs, the values and the levels are kept
+	if !r.promote && r.definitionLevels == nil {
+		src.Path = path
+		src.Schema = cbt.SchemaHandler.SchemaElements[index]
+		return src
+	}
+	src.BoxValues()
+	res := &layout.Table{
+		RepetitionType:   cbt.SchemaHandler.SchemaElements[index].GetRepetitionType(),
+		Schema:           cbt.SchemaHandler.SchemaElements[index],
+		Path:             path,
+		Values:           make([]interface{}, len(src.Values)),
+		DefinitionLevels: make([]int32, len(src.DefinitionLevels)),
+		RepetitionLevels: append([]int32{}, src.RepetitionLevels...),
+		Info:             src.Info,
+	}
+	res.MaxDefinitionLevel, _ = cbt.SchemaHandler.MaxDefinitionLevel(path)
+	res.MaxRepetitionLevel, _ = cbt.SchemaHandler.MaxRepetitionLevel(path)
+	for i, v := range src.Values {
+		res.Values[i] = r.value(v)
+	}
+	for i, dl := range src.DefinitionLevels {
+		if int(dl) < len(r.definitionLevels) {
+			dl = r.definitionLevels[dl]
+		}
+		res.DefinitionLevels[i] = dl
+	}
+	return res
+}
+
+//Append the rows of a column missing in the file
+func (cbt *ColumnBufferType) appendMissingRows(numRows int64) {
+	if cbt.DataTable == nil {
+		index := cbt.SchemaHandler.MapIndex[cbt.PathStr]
+		cbt.DataTable = layout.NewEmptyTable()
+		cbt.DataTable.Schema = cbt.SchemaHandler.SchemaElements[index]
+		cbt.DataTable.RepetitionType = cbt.DataTable.Schema.GetRepetitionType()
+		cbt.DataTable.Path = common.StrToPath(cbt.PathStr)
+	}
+	cbt.DataTable.BoxValues()
+	for i := int64(0); i < numRows; i++ {
+		cbt.DataTable.Values = append(cbt.DataTable.Values, cbt.resolution.zero)
+	}
+	cbt.DataTable.RepetitionLevels = append(cbt.DataTable.RepetitionLevels, make([]int32, numRows)...)
+	cbt.DataTable.DefinitionLevels = append(cbt.DataTable.DefinitionLevels, make([]int32, numRows)...)
+	cbt.DataTableNumRows += numRows
+}
@@ -0,0 +1,193 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//RowGroupInfo is the position of a row group in the file
+type RowGroupInfo struct {
+	Index int
+	//Index of the first row of the row group in the file and its number of rows
+	FirstRow int64
+	NumRows  int64
+	//Byte range [Offset, Offset+CompressedSize) of the column chunks
+	Offset         int64
+	CompressedSize int64
+}
+
+// WithRowGroups reads only the row groups with the indexes, see ParquetReader.RowGroups.
+func WithRowGroups(indexes ...int) ParquetReaderOption {
+	return func(pr *ParquetReader) {
+		pr.rowGroups = append(pr.rowGroups, indexes...)
+	}
+}
+
+// WithRowRange reads only the rows [start, end) of the file, the row groups without these rows aren't read.
+// With WithRowGroups the rows must also be in the selected row groups.
+func WithRowRange(start, end int64) ParquetReaderOption {
+	return func(pr *ParquetReader) {
+		pr.rowStart, pr.rowEnd, pr.hasRowRange = start, end, true
+	}
+}
+
+// RowGroups returns the row groups of the file with their rows and byte ranges
+func (pr *ParquetReader) RowGroups() []RowGroupInfo {
+	return pr.rowGroupInfos
+}
+
+//Row groups of the footer, computed once when the footer is read
+func rowGroupInfos(footer *parquet.FileMetaData) []RowGroupInfo {
+	res := make([]RowGroupInfo, 0, len(footer.GetRowGroups()))
+	firstRow := int64(0)
+	for i, rowGroup := range footer.GetRowGroups() {
+		info := RowGroupInfo{Index: i, FirstRow: firstRow, NumRows: rowGroup.GetNumRows()}
+		begin, end := int64(-1), int64(0)
+		for _, chunk := range rowGroup.GetColumns() {
+			meta := chunk.GetMetaData()
+			if meta == nil {
+				continue
+			}
+			offset := meta.GetDataPageOffset()
+			if meta.DictionaryPageOffset != nil && meta.GetDictionaryPageOffset() < offset {
+				offset = meta.GetDictionaryPageOffset()
+			}
+			if begin < 0 || offset < begin {
+				begin = offset
+			}
+			if offset+meta.GetTotalCompressedSize() > end {
+				end = offset + meta.GetTotalCompressedSize()
+			}
+		}
+		if begin >= 0 {
+			info.Offset, info.CompressedSize = begin, end-begin
+		}
+		res = append(res, info)
+		firstRow += rowGroup.GetNumRows()
+	}
+	return res
+}
+
+/*
+SeekToRow moves all the columns to the row of the file, the index of the first row is 0. The row must be in the
+selected row groups and row range. The row group of the row is found by the row counts of the row groups and the
+page of the row by the offset index, so only the pages from the row are read. The files without offset index
+read the levels of the pages before the row in its row group.
+*/
+func (pr *ParquetReader) SeekToRow(row int64) error {
+	if !pr.isSelectedRow(row) {
+		return fmt.Errorf("row %v isn't in the selected rows", row)
+	}
+	pr.seekRow = row
+	for _, cb := range pr.ColumnBuffers {
+		if err := pr.seekColumnBuffer(cb, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Check the row groups and the row range and select the row groups to read
+func (pr *ParquetReader) setRowSelection() error {
+	pr.selectedRowGroups, pr.seekRow = nil, 0
+	if len(pr.rowGroups) == 0 && !pr.hasRowRange {
+		return nil
+	}
+
+	rowGroups := pr.Footer.GetRowGroups()
+	numRows := pr.Footer.GetNumRows()
+	if !pr.hasRowRange {
+		pr.rowStart, pr.rowEnd = 0, numRows
+	}
+	if pr.rowStart < 0 || pr.rowEnd < pr.rowStart {
+		return fmt.Errorf("invalid row range [%v, %v)", pr.rowStart, pr.rowEnd)
+	}
+	if pr.rowEnd > numRows {
+		pr.rowEnd = numRows
+	}
+
+	inRowGroups := make([]bool, len(rowGroups))
+	for _, index := range pr.rowGroups {
+		if index < 0 || index >= len(rowGroups) {
+			return fmt.Errorf("row group %v out of range %v", index, len(rowGroups))
+		}
+		inRowGroups[index] = true
+	}
+
+	pr.selectedRowGroups = make([]bool, len(rowGroups))
+	pr.seekRow = numRows
+	for _, info := range pr.RowGroups() {
+		if len(pr.rowGroups) > 0 && !inRowGroups[info.Index] {
+			continue
+		}
+		begin, end := pr.selectedRowsOf(info, 0)
+		if begin < end {
+			pr.selectedRowGroups[info.Index] = true
+			if begin < pr.seekRow {
+				pr.seekRow = begin
+			}
+		}
+	}
+	return nil
+}
+
+//Selected rows [begin, end) of the row group from the row
+func (pr *ParquetReader) selectedRowsOf(info RowGroupInfo, row int64) (int64, int64) {
+	begin, end := info.FirstRow, info.FirstRow+info.NumRows
+	if pr.selectedRowGroups != nil {
+		if begin < pr.rowStart {
+			begin = pr.rowStart
+		}
+		if end > pr.rowEnd {
+			end = pr.rowEnd
+		}
+	}
+	if begin < row {
+		begin = row
+	}
+	return begin, end
+}
+
+//Number of the rows to read from the row
+func (pr *ParquetReader) selectedRows(row int64) int64 {
+	res := int64(0)
+	for _, info := range pr.RowGroups() {
+		if pr.selectedRowGroups != nil && !pr.selectedRowGroups[info.Index] {
+			continue
+		}
+		if begin, end := pr.selectedRowsOf(info, row); begin < end {
+			res += end - begin
+		}
+	}
+	return res
+}
+
+func (pr *ParquetReader) isSelectedRow(row int64) bool {
+	for _, info := range pr.RowGroups() {
+		if row >= info.FirstRow && row < info.FirstRow+info.NumRows {
+			if pr.selectedRowGroups == nil {
+				return true
+			}
+			begin, end := pr.selectedRowsOf(info, row)
+			return pr.selectedRowGroups[info.Index] && begin == row && row < end
+		}
+	}
+	return false
+}
+
+//Move the column buffer to the row and limit it to the selected rows from the row
+func (pr *ParquetReader) seekColumnBuffer(cb *ColumnBufferType, row int64) error {
+	rowsLeft := int64(-1)
+	if pr.selectedRowGroups != nil {
+		rowsLeft = pr.selectedRows(row)
+	}
+	for _, info := range pr.RowGroups() {
+		if row >= info.FirstRow && row < info.FirstRow+info.NumRows {
+			return cb.seekToRow(int64(info.Index), row-info.FirstRow, rowsLeft)
+		}
+	}
+	//no rows are selected
+	cb.rowsLeft = 0
+	return nil
+}
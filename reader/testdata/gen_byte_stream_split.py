@@ -0,0 +1,164 @@
+#!/usr/bin/env python3
+"""Write byte_stream_split.parquet without parquet-go.
+
+The file has one row group with one uncompressed DATA_PAGE per column, all
+encoded as BYTE_STREAM_SPLIT. The thrift compact protocol and the encodings
+are written here from the parquet-format spec, so the fixture doesn't depend
+on the encoders of parquet-go.
+"""
+import struct
+import sys
+
+INT32_VALUES = [1, -2, 300000, 2147483647, -2147483648]
+INT64_VALUES = [1, None, -1, 1 << 40, None]
+FLBA_VALUES = [b"abc", b"\x00\x01\x02", b"xyz", b"\xff\xfe\xfd", b"123"]
+
+T_I32, T_I64, T_BINARY, T_LIST, T_STRUCT = 5, 6, 8, 9, 12
+
+
+def varint(n):
+    out = bytearray()
+    while True:
+        b = n & 0x7F
+        n >>= 7
+        if n:
+            out.append(b | 0x80)
+        else:
+            out.append(b)
+            return bytes(out)
+
+
+def zigzag(n):
+    return (n << 1) ^ (n >> 63)
+
+
+class Struct:
+    def __init__(self):
+        self.buf = bytearray()
+        self.last = 0
+
+    def header(self, fid, ftype):
+        delta = fid - self.last
+        if 0 < delta <= 15:
+            self.buf.append((delta << 4) | ftype)
+        else:
+            self.buf.append(ftype)
+            self.buf += varint(zigzag(fid))
+        self.last = fid
+
+    def i32(self, fid, v):
+        self.header(fid, T_I32)
+        self.buf += varint(zigzag(v))
+        return self
+
+    def i64(self, fid, v):
+        self.header(fid, T_I64)
+        self.buf += varint(zigzag(v))
+        return self
+
+    def binary(self, fid, v):
+        if isinstance(v, str):
+            v = v.encode()
+        self.header(fid, T_BINARY)
+        self.buf += varint(len(v)) + v
+        return self
+
+    def struct(self, fid, s):
+        self.header(fid, T_STRUCT)
+        self.buf += s.end()
+        return self
+
+    def list(self, fid, etype, items):
+        self.header(fid, T_LIST)
+        if len(items) < 15:
+            self.buf.append((len(items) << 4) | etype)
+        else:
+            self.buf.append(0xF0 | etype)
+            self.buf += varint(len(items))
+        for item in items:
+            if etype == T_STRUCT:
+                self.buf += item.end()
+            elif etype == T_BINARY:
+                item = item.encode()
+                self.buf += varint(len(item)) + item
+            else:
+                self.buf += varint(zigzag(item))
+        return self
+
+    def end(self):
+        return bytes(self.buf) + b"\x00"
+
+
+def byte_stream_split(plain, width):
+    n = len(plain) // width
+    return bytes(plain[i * width + k] for k in range(width) for i in range(n))
+
+
+def rle_levels(levels):
+    # RLE runs of the bit width 1, prefixed with the 4 byte length
+    runs = bytearray()
+    i = 0
+    while i < len(levels):
+        j = i
+        while j < len(levels) and levels[j] == levels[i]:
+            j += 1
+        runs += varint((j - i) << 1) + bytes([levels[i]])
+        i = j
+    return struct.pack("<I", len(runs)) + bytes(runs)
+
+
+def column(name, ptype, plain, width, num_values, def_levels=None):
+    data = b""
+    if def_levels is not None:
+        data += rle_levels(def_levels)
+    data += byte_stream_split(plain, width)
+    data_page_header = (
+        Struct().i32(1, num_values).i32(2, 9).i32(3, 3).i32(4, 3)
+    )
+    page_header = (
+        Struct().i32(1, 0).i32(2, len(data)).i32(3, len(data))
+        .struct(5, data_page_header).end()
+    )
+    return name, ptype, page_header + data, num_values
+
+
+def main(path):
+    int64_plain = b"".join(struct.pack("<q", v) for v in INT64_VALUES if v is not None)
+    columns = [
+        column("int32", 1, b"".join(struct.pack("<i", v) for v in INT32_VALUES), 4, 5),
+        column("int64", 2, int64_plain, 8, 5, [0 if v is None else 1 for v in INT64_VALUES]),
+        column("flba", 7, b"".join(FLBA_VALUES), 3, 5),
+    ]
+
+    out = bytearray(b"PAR1")
+    chunks = []
+    total = 0
+    for name, ptype, chunk, num_values in columns:
+        offset = len(out)
+        out += chunk
+        total += len(chunk)
+        meta = (
+            Struct().i32(1, ptype).list(2, T_I32, [9, 3]).list(3, T_BINARY, [name])
+            .i32(4, 0).i64(5, num_values).i64(6, len(chunk)).i64(7, len(chunk))
+            .i64(9, offset)
+        )
+        chunks.append(Struct().i64(2, offset).struct(3, meta))
+
+    schema = [
+        Struct().binary(4, "schema").i32(5, 3),
+        Struct().i32(1, 1).i32(3, 0).binary(4, "int32"),
+        Struct().i32(1, 2).i32(3, 1).binary(4, "int64"),
+        Struct().i32(1, 7).i32(2, 3).i32(3, 0).binary(4, "flba"),
+    ]
+    row_group = Struct().list(1, T_STRUCT, chunks).i64(2, total).i64(3, 5)
+    footer = (
+        Struct().i32(1, 1).list(2, T_STRUCT, schema).i64(3, 5)
+        .list(4, T_STRUCT, [row_group]).binary(6, "gen_byte_stream_split.py").end()
+    )
+    out += footer + struct.pack("<I", len(footer)) + b"PAR1"
+    with open(path, "wb") as f:
+        f.write(out)
+
+
+if __name__ == "__main__":
+    main(sys.argv[1] if len(sys.argv) > 1 else "byte_stream_split.parquet")
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/apache/arrow/go/arrow"
 	"github.com/xitongsys/parquet-go/common"
@@ -14,7 +15,17 @@ const (
 	convertedMetaDataTemplate = "name=%s, type=%s, convertedtype=%s, " +
 		"repetitiontype=%s"
 	primitiveMetaDataTemplate = "name=%s, type=%s, repetitiontype=%s"
-	rootNodeName              = "Parquet45go45root"
+	float16MetaDataTemplate   = "name=%s, type=FIXED_LEN_BYTE_ARRAY, length=2, " +
+		"logicaltype=FLOAT16, repetitiontype=%s"
+	nullMetaDataTemplate = "name=%s, type=INT32, logicaltype=UNKNOWN, " +
+		"repetitiontype=OPTIONAL"
+	timeNanosMetaDataTemplate = "name=%s, type=INT64, logicaltype=TIME, " +
+		"logicaltype.isadjustedtoutc=false, logicaltype.unit=NANOS, " +
+		"repetitiontype=%s"
+	rootNodeName = "Parquet45go45root"
+
+	//ArrowFieldIDKey is the key of the field_id in the arrow field metadata
+	ArrowFieldIDKey = "PARQUET:field_id"
 )
 
 // ConvertArrowToParquetSchema converts arrow schema to representation
@@ -67,6 +78,11 @@ func ConvertArrowToParquetSchema(schema *arrow.Schema) ([]string, error) {
 		case arrow.PrimitiveTypes.Float64.Name():
 			metaData[k] = fmt.Sprintf(primitiveMetaDataTemplate, v.Name,
 				parquet.Type_DOUBLE, repetitionType)
+		case arrow.FixedWidthTypes.Float16.Name():
+			metaData[k] = fmt.Sprintf(float16MetaDataTemplate, v.Name,
+				repetitionType)
+		case arrow.Null.Name():
+			metaData[k] = fmt.Sprintf(nullMetaDataTemplate, v.Name)
 		case arrow.PrimitiveTypes.Date32.Name(),
 			arrow.PrimitiveTypes.Date64.Name():
 			metaData[k] = fmt.Sprintf(convertedMetaDataTemplate, v.Name,
@@ -88,6 +104,15 @@ func ConvertArrowToParquetSchema(schema *arrow.Schema) ([]string, error) {
 			metaData[k] = fmt.Sprintf(convertedMetaDataTemplate, v.Name,
 				parquet.Type_INT32, parquet.ConvertedType_TIME_MILLIS,
 				repetitionType)
+		case arrow.FixedWidthTypes.Time64us.Name():
+			if fieldType.(*arrow.Time64Type).Unit == arrow.Nanosecond {
+				metaData[k] = fmt.Sprintf(timeNanosMetaDataTemplate, v.Name,
+					repetitionType)
+			} else {
+				metaData[k] = fmt.Sprintf(convertedMetaDataTemplate, v.Name,
+					parquet.Type_INT64, parquet.ConvertedType_TIME_MICROS,
+					repetitionType)
+			}
 		case arrow.FixedWidthTypes.Timestamp_ms.Name():
 			tsType := fieldType.(*arrow.TimestampType)
 			if tsType.Unit != arrow.Millisecond {
@@ -100,10 +125,106 @@ func ConvertArrowToParquetSchema(schema *arrow.Schema) ([]string, error) {
 			return nil,
 				fmt.Errorf("Unsupported arrow format: %s", fieldType.Name())
 		}
+		if i := v.Metadata.FindKey(ArrowFieldIDKey); i >= 0 {
+			fieldID, err := strconv.ParseInt(v.Metadata.Values()[i], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s of %s: %s", ArrowFieldIDKey, v.Name, err.Error())
+			}
+			metaData[k] += fmt.Sprintf(", fieldid=%d", fieldID)
+		}
 	}
 	return metaData, err
 }
 
+// ConvertParquetToArrowSchema converts a flat parquet schema to arrow schema,
+// the reverse of ConvertArrowToParquetSchema. The field_id of the columns is
+// kept in the field metadata.
+func ConvertParquetToArrowSchema(sh *SchemaHandler) (*arrow.Schema, error) {
+	fields := make([]arrow.Field, 0, len(sh.SchemaElements))
+	for i := 1; i < len(sh.SchemaElements); i++ {
+		se := sh.SchemaElements[i]
+		if se.GetNumChildren() > 0 ||
+			se.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+			return nil, fmt.Errorf("Unsupported parquet field: %s is nested", sh.GetExName(i))
+		}
+		dataType := parquetToArrowType(se)
+		if dataType == nil {
+			return nil, fmt.Errorf("Unsupported parquet field: %s", sh.GetExName(i))
+		}
+		field := arrow.Field{
+			Name:     sh.GetExName(i),
+			Type:     dataType,
+			Nullable: se.GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL,
+		}
+		if sh.HasFieldID(int32(i)) {
+			field.Metadata = arrow.NewMetadata([]string{ArrowFieldIDKey},
+				[]string{strconv.Itoa(int(se.GetFieldID()))})
+		}
+		fields = append(fields, field)
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+func parquetToArrowType(se *parquet.SchemaElement) arrow.DataType {
+	lT := se.LogicalType
+	switch {
+	case lT != nil && lT.IsSetFLOAT16():
+		return arrow.FixedWidthTypes.Float16
+	case lT != nil && lT.IsSetUNKNOWN():
+		return arrow.Null
+	case lT != nil && lT.IsSetTIME() && lT.TIME.Unit != nil && lT.TIME.Unit.IsSetNANOS():
+		return arrow.FixedWidthTypes.Time64ns
+	}
+
+	if se.ConvertedType != nil {
+		switch se.GetConvertedType() {
+		case parquet.ConvertedType_INT_8:
+			return arrow.PrimitiveTypes.Int8
+		case parquet.ConvertedType_INT_16:
+			return arrow.PrimitiveTypes.Int16
+		case parquet.ConvertedType_UINT_8:
+			return arrow.PrimitiveTypes.Uint8
+		case parquet.ConvertedType_UINT_16:
+			return arrow.PrimitiveTypes.Uint16
+		case parquet.ConvertedType_UINT_32:
+			return arrow.PrimitiveTypes.Uint32
+		case parquet.ConvertedType_UINT_64:
+			return arrow.PrimitiveTypes.Uint64
+		case parquet.ConvertedType_DATE:
+			return arrow.FixedWidthTypes.Date32
+		case parquet.ConvertedType_UTF8:
+			return arrow.BinaryTypes.String
+		case parquet.ConvertedType_TIME_MILLIS:
+			return arrow.FixedWidthTypes.Time32ms
+		case parquet.ConvertedType_TIME_MICROS:
+			return arrow.FixedWidthTypes.Time64us
+		case parquet.ConvertedType_TIMESTAMP_MILLIS:
+			return arrow.FixedWidthTypes.Timestamp_ms
+		case parquet.ConvertedType_INT_32:
+			return arrow.PrimitiveTypes.Int32
+		case parquet.ConvertedType_INT_64:
+			return arrow.PrimitiveTypes.Int64
+		}
+		return nil
+	}
+
+	switch se.GetType() {
+	case parquet.Type_BOOLEAN:
+		return arrow.FixedWidthTypes.Boolean
+	case parquet.Type_INT32:
+		return arrow.PrimitiveTypes.Int32
+	case parquet.Type_INT64:
+		return arrow.PrimitiveTypes.Int64
+	case parquet.Type_FLOAT:
+		return arrow.PrimitiveTypes.Float32
+	case parquet.Type_DOUBLE:
+		return arrow.PrimitiveTypes.Float64
+	case parquet.Type_BYTE_ARRAY:
+		return arrow.BinaryTypes.Binary
+	}
+	return nil
+}
+
 // NewSchemaHandlerFromArrow creates a schema handler from arrow format.
 // This handler is needed since the base ParquetWriter does not understand
 // arrow schema and we need to translate it to the native format which the
@@ -160,14 +160,33 @@ func TestTypeConversion(t *testing.T) {
 			expectedErr: false,
 		},
 		{
-			title: "test non supported types",
+			title: "test float16, null and time64 type conversion",
 			testSchema: arrow.NewSchema([]arrow.Field{
+				{Name: "f1-f16", Type: arrow.FixedWidthTypes.Float16},
+				{Name: "f1-null", Type: arrow.Null, Nullable: true},
 				{Name: "f1-t64us", Type: arrow.FixedWidthTypes.Time64us},
+				{Name: "null-t64ns", Type: arrow.FixedWidthTypes.Time64ns,
+					Nullable: true},
+			}, nil),
+			expectedParquetMetaData: []string{
+				"name=f1-f16, type=FIXED_LEN_BYTE_ARRAY, length=2, " +
+					"logicaltype=FLOAT16, repetitiontype=REQUIRED",
+				"name=f1-null, type=INT32, logicaltype=UNKNOWN, " +
+					"repetitiontype=OPTIONAL",
+				"name=f1-t64us, type=INT64, convertedtype=TIME_MICROS, " +
+					"repetitiontype=REQUIRED",
+				"name=null-t64ns, type=INT64, logicaltype=TIME, " +
+					"logicaltype.isadjustedtoutc=false, logicaltype.unit=NANOS, " +
+					"repetitiontype=OPTIONAL",
+			},
+			expectedErr: false,
+		},
+		{
+			title: "test non supported types",
+			testSchema: arrow.NewSchema([]arrow.Field{
 				{Name: "f1-t32s", Type: arrow.FixedWidthTypes.Time32s},
 				{Name: "f1-tsns", Type: arrow.FixedWidthTypes.Timestamp_ns},
 				{Name: "f1-tss", Type: arrow.FixedWidthTypes.Timestamp_s},
-				{Name: "null-t64us", Type: arrow.FixedWidthTypes.Time64us,
-					Nullable: true},
 				{Name: "null-t32s", Type: arrow.FixedWidthTypes.Time32s,
 					Nullable: true},
 				{Name: "null-tsns", Type: arrow.FixedWidthTypes.Timestamp_ns,
@@ -194,3 +213,42 @@ func TestTypeConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestArrowFieldID(t *testing.T) {
+	fieldID := func(id string) arrow.Metadata {
+		return arrow.NewMetadata([]string{ArrowFieldIDKey}, []string{id})
+	}
+	arrowSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Metadata: fieldID("1")},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true, Metadata: fieldID("2")},
+		{Name: "half", Type: arrow.FixedWidthTypes.Float16},
+		{Name: "t", Type: arrow.FixedWidthTypes.Time64ns, Nullable: true, Metadata: fieldID("4")},
+	}, nil)
+
+	metaData, err := ConvertArrowToParquetSchema(arrowSchema)
+	assert.NoError(t, err)
+	assert.Equal(t, "name=id, type=INT64, repetitiontype=REQUIRED, fieldid=1", metaData[0])
+
+	sh, err := NewSchemaHandlerFromArrow(arrowSchema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int32{
+		"Parquet45go45root\x01Id":   1,
+		"Parquet45go45root\x01Name": 2,
+		"Parquet45go45root\x01T":    4,
+	}, sh.GetFieldIDs())
+	path, err := sh.GetPathByFieldID(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "Parquet45go45root\x01Name", path)
+	_, err = sh.GetPathByFieldID(3)
+	assert.Error(t, err)
+
+	res, err := ConvertParquetToArrowSchema(sh)
+	assert.NoError(t, err)
+	assert.True(t, arrowSchema.Equal(res), res.String())
+	assert.Equal(t, arrowSchema.Fields(), res.Fields())
+
+	_, err = ConvertArrowToParquetSchema(arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Metadata: fieldID("x")},
+	}, nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,507 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//AvroSchemaKey is the key-value metadata key of the Avro schema in the files written by parquet-avro
+const AvroSchemaKey = "parquet.avro.schema"
+
+//Converter of an Avro schema to a JSON schema
+type avroImporter struct {
+	//named types (record, enum and fixed) by full and simple names
+	namedTypes map[string]map[string]interface{}
+	//records being converted, the recursive records can't be converted
+	visiting map[string]bool
+}
+
+/*
+NewSchemaHandlerFromAvro creates a schema handler from an Avro schema (.avsc JSON) of a record.
+It follows the conventions of parquet-avro: a union of null and a type is an OPTIONAL field,
+other unions are groups of OPTIONAL member0, member1... fields, arrays are 3-level LISTs, maps are MAPs with string keys.
+The logical types date, time-millis, time-micros, timestamp-millis, timestamp-micros, local-timestamp-millis,
+local-timestamp-micros and decimal are converted, uuid and enums are UTF8 and ENUM strings.
+The root is named by the full name of the record, e.g. com.example.User. The Avro schema is kept in
+AvroSchema of the handler, the writer stores it in the parquet.avro.schema key-value metadata.
+*/
+func NewSchemaHandlerFromAvro(avsc string) (*SchemaHandler, error) {
+	item, err := AvroToJSONSchema(avsc)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	sh, err := NewSchemaHandlerFromJSON(string(bs))
+	if err != nil {
+		return nil, err
+	}
+	sh.AvroSchema = avsc
+	return sh, nil
+}
+
+//AvroToJSONSchema converts an Avro schema to the JSON schema of NewSchemaHandlerFromJSON, see NewSchemaHandlerFromAvro
+func AvroToJSONSchema(avsc string) (*JSONSchemaItemType, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(avsc), &root); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %v", err)
+	}
+	record, ok := root.(map[string]interface{})
+	if !ok || record["type"] != "record" {
+		return nil, fmt.Errorf("Avro schema must be a record")
+	}
+
+	r := &avroImporter{namedTypes: make(map[string]map[string]interface{}), visiting: make(map[string]bool)}
+	fullName, _ := avroFullName(record, "")
+	item, err := r.convertRecord(record, fullName, "REQUIRED", "")
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func avroFullName(t map[string]interface{}, namespace string) (string, string) {
+	name, _ := t["name"].(string)
+	if ns, ok := t["namespace"].(string); ok {
+		namespace = ns
+	}
+	if strings.Contains(name, ".") {
+		return name, name[:strings.LastIndex(name, ".")]
+	}
+	if namespace == "" {
+		return name, namespace
+	}
+	return namespace + "." + name, namespace
+}
+
+func (r *avroImporter) define(t map[string]interface{}, namespace string) (string, error) {
+	fullName, namespace := avroFullName(t, namespace)
+	if fullName == "" {
+		return namespace, fmt.Errorf("Avro %v without name", t["type"])
+	}
+	r.namedTypes[fullName] = t
+	r.namedTypes[fullName[strings.LastIndex(fullName, ".")+1:]] = t
+	return namespace, nil
+}
+
+func (r *avroImporter) convertRecord(t map[string]interface{}, name string, repetitionType string, namespace string) (*JSONSchemaItemType, error) {
+	fullName, _ := avroFullName(t, namespace)
+	if r.visiting[fullName] {
+		return nil, fmt.Errorf("recursive Avro record %v isn't supported", fullName)
+	}
+	r.visiting[fullName] = true
+	defer delete(r.visiting, fullName)
+
+	namespace, err := r.define(t, namespace)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := t["fields"].([]interface{})
+	if !ok || len(fields) == 0 {
+		return nil, fmt.Errorf("Avro record %v without fields", fullName)
+	}
+
+	item := NewJSONSchemaItem()
+	item.Tag = fmt.Sprintf("name=%s, repetitiontype=%s", name, repetitionType)
+	for _, f := range fields {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid field of Avro record %v", fullName)
+		}
+		fieldName, _ := field["name"].(string)
+		if fieldName == "" {
+			return nil, fmt.Errorf("Avro record %v has a field without name", fullName)
+		}
+		child, err := r.convertField(field["type"], fieldName, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %v", name, fieldName, err)
+		}
+		item.Fields = append(item.Fields, child)
+	}
+	return item, nil
+}
+
+//Convert a field type, the nullable unions are OPTIONAL
+func (r *avroImporter) convertField(t interface{}, name string, namespace string) (*JSONSchemaItemType, error) {
+	union, ok := t.([]interface{})
+	if !ok {
+		return r.convertType(t, name, "REQUIRED", namespace)
+	}
+
+	types, nullable := make([]interface{}, 0, len(union)), false
+	for _, ut := range union {
+		if ut == "null" {
+			nullable = true
+		} else {
+			types = append(types, ut)
+		}
+	}
+	repetitionType := "REQUIRED"
+	if nullable {
+		repetitionType = "OPTIONAL"
+	}
+	switch len(types) {
+	case 0:
+		return nil, fmt.Errorf("union of null only isn't supported")
+	case 1:
+		return r.convertType(types[0], name, repetitionType, namespace)
+	}
+
+	//a group of the members, only one of them is set
+	item := NewJSONSchemaItem()
+	item.Tag = fmt.Sprintf("name=%s, repetitiontype=%s", name, repetitionType)
+	for i, ut := range types {
+		member, err := r.convertType(ut, fmt.Sprintf("member%d", i), "OPTIONAL", namespace)
+		if err != nil {
+			return nil, err
+		}
+		item.Fields = append(item.Fields, member)
+	}
+	return item, nil
+}
+
+var avroPrimitiveTags = map[string]string{
+	"boolean": "type=BOOLEAN",
+	"int":     "type=INT32",
+	"long":    "type=INT64",
+	"float":   "type=FLOAT",
+	"double":  "type=DOUBLE",
+	"bytes":   "type=BYTE_ARRAY",
+	"string":  "type=BYTE_ARRAY, convertedtype=UTF8",
+}
+
+var avroLogicalTags = map[string]string{
+	"int/date":                    "type=INT32, convertedtype=DATE",
+	"int/time-millis":             "type=INT32, convertedtype=TIME_MILLIS, isadjustedtoutc=true",
+	"long/time-micros":            "type=INT64, convertedtype=TIME_MICROS, isadjustedtoutc=true",
+	"long/timestamp-millis":       "type=INT64, convertedtype=TIMESTAMP_MILLIS, isadjustedtoutc=true",
+	"long/timestamp-micros":       "type=INT64, convertedtype=TIMESTAMP_MICROS, isadjustedtoutc=true",
+	"long/local-timestamp-millis": "type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=false, logicaltype.unit=MILLIS",
+	"long/local-timestamp-micros": "type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=false, logicaltype.unit=MICROS",
+	//uuid strings are written as strings by parquet-avro
+	"string/uuid": "type=BYTE_ARRAY, convertedtype=UTF8",
+}
+
+func avroInt(t map[string]interface{}, key string) int {
+	n, _ := t[key].(float64)
+	return int(n)
+}
+
+func (r *avroImporter) convertType(t interface{}, name string, repetitionType string, namespace string) (*JSONSchemaItemType, error) {
+	item := NewJSONSchemaItem()
+	if typeName, ok := t.(string); ok {
+		if tag, ok := avroPrimitiveTags[typeName]; ok {
+			item.Tag = fmt.Sprintf("name=%s, %s, repetitiontype=%s", name, tag, repetitionType)
+			return item, nil
+		}
+		//reference of a named type
+		named, ok := r.namedTypes[typeName]
+		if !ok {
+			named, ok = r.namedTypes[namespace+"."+typeName]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown Avro type %v", typeName)
+		}
+		t = named
+	}
+
+	obj, ok := t.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("nested unions aren't supported")
+	}
+	typeName, _ := obj["type"].(string)
+
+	if logicalType, ok := obj["logicalType"].(string); ok {
+		if tag, ok := avroLogicalTags[typeName+"/"+logicalType]; ok {
+			item.Tag = fmt.Sprintf("name=%s, %s, repetitiontype=%s", name, tag, repetitionType)
+			return item, nil
+		}
+		if logicalType == "decimal" && (typeName == "bytes" || typeName == "fixed") {
+			tag := fmt.Sprintf("type=BYTE_ARRAY, convertedtype=DECIMAL, scale=%d, precision=%d", avroInt(obj, "scale"), avroInt(obj, "precision"))
+			if typeName == "fixed" {
+				if _, err := r.define(obj, namespace); err != nil {
+					return nil, err
+				}
+				tag = fmt.Sprintf("type=FIXED_LEN_BYTE_ARRAY, length=%d, convertedtype=DECIMAL, scale=%d, precision=%d", avroInt(obj, "size"), avroInt(obj, "scale"), avroInt(obj, "precision"))
+			}
+			item.Tag = fmt.Sprintf("name=%s, %s, repetitiontype=%s", name, tag, repetitionType)
+			return item, nil
+		}
+		//unknown logical types are their base types
+	}
+
+	switch typeName {
+	case "record":
+		return r.convertRecord(obj, name, repetitionType, namespace)
+
+	case "enum":
+		if _, err := r.define(obj, namespace); err != nil {
+			return nil, err
+		}
+		item.Tag = fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=ENUM, repetitiontype=%s", name, repetitionType)
+
+	case "fixed":
+		if _, err := r.define(obj, namespace); err != nil {
+			return nil, err
+		}
+		item.Tag = fmt.Sprintf("name=%s, type=FIXED_LEN_BYTE_ARRAY, length=%d, repetitiontype=%s", name, avroInt(obj, "size"), repetitionType)
+
+	case "array":
+		element, err := r.convertField(obj["items"], "element", namespace)
+		if err != nil {
+			return nil, err
+		}
+		item.Tag = fmt.Sprintf("name=%s, type=LIST, repetitiontype=%s", name, repetitionType)
+		item.Fields = []*JSONSchemaItemType{element}
+
+	case "map":
+		value, err := r.convertField(obj["values"], "value", namespace)
+		if err != nil {
+			return nil, err
+		}
+		item.Tag = fmt.Sprintf("name=%s, type=MAP, repetitiontype=%s", name, repetitionType)
+		item.Fields = []*JSONSchemaItemType{
+			{Tag: "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+			value,
+		}
+
+	default:
+		//primitive type in an object, e.g. {"type": "string"}
+		if _, ok := avroPrimitiveTags[typeName]; !ok {
+			return nil, fmt.Errorf("unknown Avro type %v", obj["type"])
+		}
+		return r.convertType(typeName, name, repetitionType, namespace)
+	}
+	return item, nil
+}
+
+//Converter of a schema handler to an Avro schema
+type avroExporter struct {
+	sh          *SchemaHandler
+	recordNames map[string]bool
+}
+
+/*
+ConvertToAvroSchema converts the schema to an Avro schema (.avsc JSON), the inverse of NewSchemaHandlerFromAvro.
+OPTIONAL fields are unions of null and the type with null default, LISTs and REPEATED fields are arrays,
+MAPs with string keys are maps and the groups are records named by their fields.
+Logical types without Avro equivalent are written as their base types, e.g. INT64 TIMESTAMP(NANOS) is a long.
+*/
+func ConvertToAvroSchema(sh *SchemaHandler) (string, error) {
+	if len(sh.SchemaElements) == 0 {
+		return "", fmt.Errorf("empty schema")
+	}
+	e := &avroExporter{sh: sh, recordNames: make(map[string]bool)}
+	record, err := e.record(0)
+	if err != nil {
+		return "", err
+	}
+	bs, err := json.Marshal(record)
+	return string(bs), err
+}
+
+type avroField struct {
+	Name    string      `json:"name"`
+	Type    interface{} `json:"type"`
+	Default interface{} `json:"default,omitempty"`
+}
+
+//Record with the null default of the optional fields, it can't be omitted
+type avroNullableField struct {
+	Name    string      `json:"name"`
+	Type    interface{} `json:"type"`
+	Default interface{} `json:"default"`
+}
+
+type avroRecord struct {
+	Type      string        `json:"type"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Fields    []interface{} `json:"fields"`
+}
+
+//Avro names are [A-Za-z_][A-Za-z0-9_]*
+func avroName(name string) string {
+	res := []byte(name)
+	for i, c := range res {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || i > 0 && c >= '0' && c <= '9') {
+			res[i] = '_'
+		}
+	}
+	return string(res)
+}
+
+func (e *avroExporter) record(idx int32) (*avroRecord, error) {
+	exName, namespace := e.sh.GetExName(int(idx)), ""
+	//the root is named by the full name of the record, e.g. com.example.User
+	if i := strings.LastIndex(exName, "."); idx == 0 && i > 0 {
+		exName, namespace = exName[i+1:], exName[:i]
+	}
+	name := avroName(exName)
+	recordName := name
+	for i := 2; e.recordNames[recordName]; i++ {
+		recordName = fmt.Sprintf("%s%d", name, i)
+	}
+	e.recordNames[recordName] = true
+
+	res := &avroRecord{Type: "record", Name: recordName, Namespace: namespace, Fields: []interface{}{}}
+	for _, childIdx := range e.sh.ChildrenIndex(idx) {
+		t, err := e.field(childIdx)
+		if err != nil {
+			return nil, err
+		}
+		name := e.sh.GetExName(int(childIdx))
+		if e.sh.SchemaElements[childIdx].GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL {
+			res.Fields = append(res.Fields, &avroNullableField{Name: name, Type: []interface{}{"null", t}})
+		} else {
+			res.Fields = append(res.Fields, &avroField{Name: name, Type: t})
+		}
+	}
+	return res, nil
+}
+
+//Avro type of the field at idx without the null of OPTIONAL
+func (e *avroExporter) field(idx int32) (interface{}, error) {
+	sh := e.sh
+	se := sh.SchemaElements[idx]
+	if se.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+		t, err := e.value(idx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": t}, nil
+	}
+
+	if elementIdx, levels := sh.ListElementIndex(idx); levels > 0 {
+		t, err := e.nullable(elementIdx, levels == 3)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": t}, nil
+	}
+
+	if keyIdx, valueIdx, ok := sh.MapKeyValueIndex(idx); ok {
+		key := sh.SchemaElements[keyIdx]
+		if key.GetType() != parquet.Type_BYTE_ARRAY || key.GetNumChildren() > 0 {
+			return nil, fmt.Errorf("%v: Avro map keys must be strings", sh.GetExName(int(idx)))
+		}
+		t, err := e.nullable(valueIdx, true)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "map", "values": t}, nil
+	}
+	return e.value(idx)
+}
+
+//Type of a list element or map value, which is a union with null if it's OPTIONAL
+func (e *avroExporter) nullable(idx int32, canBeOptional bool) (interface{}, error) {
+	t, err := e.field(idx)
+	if err != nil {
+		return nil, err
+	}
+	if canBeOptional && e.sh.SchemaElements[idx].GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL {
+		return []interface{}{"null", t}, nil
+	}
+	return t, nil
+}
+
+//Type of a group or primitive value
+func (e *avroExporter) value(idx int32) (interface{}, error) {
+	se := e.sh.SchemaElements[idx]
+	if se.GetNumChildren() > 0 {
+		return e.record(idx)
+	}
+
+	lt := se.LogicalType
+	isConverted := func(ct parquet.ConvertedType) bool {
+		return se.IsSetConvertedType() && se.GetConvertedType() == ct
+	}
+	logical := func(base string, logicalType string) interface{} {
+		return map[string]interface{}{"type": base, "logicalType": logicalType}
+	}
+	timeUnit := func(unit *parquet.TimeUnit) string {
+		if unit.IsSetMILLIS() {
+			return "millis"
+		} else if unit.IsSetMICROS() {
+			return "micros"
+		}
+		return "nanos"
+	}
+
+	switch se.GetType() {
+	case parquet.Type_BOOLEAN:
+		return "boolean", nil
+
+	case parquet.Type_INT32:
+		switch {
+		case isConverted(parquet.ConvertedType_DATE) || lt != nil && lt.IsSetDATE():
+			return logical("int", "date"), nil
+		case isConverted(parquet.ConvertedType_TIME_MILLIS) || lt != nil && lt.IsSetTIME() && lt.TIME.Unit.IsSetMILLIS():
+			return logical("int", "time-millis"), nil
+		}
+		return "int", nil
+
+	case parquet.Type_INT64:
+		switch {
+		case isConverted(parquet.ConvertedType_TIME_MICROS) || lt != nil && lt.IsSetTIME() && lt.TIME.Unit.IsSetMICROS():
+			return logical("long", "time-micros"), nil
+		case isConverted(parquet.ConvertedType_TIMESTAMP_MILLIS):
+			return logical("long", "timestamp-millis"), nil
+		case isConverted(parquet.ConvertedType_TIMESTAMP_MICROS):
+			return logical("long", "timestamp-micros"), nil
+		case lt != nil && lt.IsSetTIMESTAMP() && !lt.TIMESTAMP.Unit.IsSetNANOS():
+			if lt.TIMESTAMP.IsAdjustedToUTC {
+				return logical("long", "timestamp-"+timeUnit(lt.TIMESTAMP.Unit)), nil
+			}
+			return logical("long", "local-timestamp-"+timeUnit(lt.TIMESTAMP.Unit)), nil
+		}
+		return "long", nil
+
+	case parquet.Type_INT96:
+		return map[string]interface{}{"type": "fixed", "name": e.fixedName(idx), "size": 12}, nil
+
+	case parquet.Type_FLOAT:
+		return "float", nil
+
+	case parquet.Type_DOUBLE:
+		return "double", nil
+
+	case parquet.Type_BYTE_ARRAY:
+		switch {
+		case isConverted(parquet.ConvertedType_DECIMAL):
+			return map[string]interface{}{"type": "bytes", "logicalType": "decimal", "precision": se.GetPrecision(), "scale": se.GetScale()}, nil
+		case isConverted(parquet.ConvertedType_UTF8) || isConverted(parquet.ConvertedType_ENUM) || isConverted(parquet.ConvertedType_JSON) ||
+			lt != nil && (lt.IsSetSTRING() || lt.IsSetENUM() || lt.IsSetJSON()):
+			return "string", nil
+		}
+		return "bytes", nil
+
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		switch {
+		case lt != nil && lt.IsSetUUID():
+			return logical("string", "uuid"), nil
+		case isConverted(parquet.ConvertedType_DECIMAL):
+			return map[string]interface{}{"type": "fixed", "name": e.fixedName(idx), "size": se.GetTypeLength(),
+				"logicalType": "decimal", "precision": se.GetPrecision(), "scale": se.GetScale()}, nil
+		}
+		return map[string]interface{}{"type": "fixed", "name": e.fixedName(idx), "size": se.GetTypeLength()}, nil
+	}
+	return nil, fmt.Errorf("%v: unknown type %v", e.sh.GetExName(int(idx)), se.GetType())
+}
+
+//Fixed types are named types like records
+func (e *avroExporter) fixedName(idx int32) string {
+	name := avroName(e.sh.GetExName(int(idx)))
+	res := name
+	for i := 2; e.recordNames[res]; i++ {
+		res = fmt.Sprintf("%s%d", name, i)
+	}
+	e.recordNames[res] = true
+	return res
+}
@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+const testAvroSchema = `{
+	"type": "record", "name": "User", "namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": ["null", "string"], "default": null},
+		{"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "local", "type": {"type": "long", "logicalType": "local-timestamp-micros"}},
+		{"name": "birthday", "type": ["null", {"type": "int", "logicalType": "date"}]},
+		{"name": "uid", "type": {"type": "string", "logicalType": "uuid"}},
+		{"name": "balance", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}},
+		{"name": "color", "type": {"type": "enum", "name": "Color", "symbols": ["RED", "GREEN"]}},
+		{"name": "tags", "type": {"type": "array", "items": ["null", "string"]}},
+		{"name": "scores", "type": {"type": "map", "values": "double"}},
+		{"name": "address", "type": ["null", {"type": "record", "name": "Address", "fields": [
+			{"name": "city", "type": "string"}
+		]}]},
+		{"name": "old_address", "type": ["null", "Address"]},
+		{"name": "value", "type": ["null", "int", "string"]}
+	]
+}`
+
+func TestNewSchemaHandlerFromAvro(t *testing.T) {
+	sh, err := NewSchemaHandlerFromAvro(testAvroSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := sh.GetExName(0); name != "com.example.User" {
+		t.Errorf("expect root com.example.User, get %v", name)
+	}
+	if sh.AvroSchema != testAvroSchema {
+		t.Errorf("expect the Avro schema in the handler, get %v", sh.AvroSchema)
+	}
+	element := func(path string) *parquet.SchemaElement {
+		idx, ok := sh.MapIndex[sh.GetRootInName()+"\x01"+path]
+		if !ok {
+			t.Fatalf("%v not found", path)
+		}
+		return sh.SchemaElements[idx]
+	}
+
+	if se := element("Name"); se.GetRepetitionType() != parquet.FieldRepetitionType_OPTIONAL || se.GetConvertedType() != parquet.ConvertedType_UTF8 {
+		t.Errorf("name: %v", se)
+	}
+	if se := element("Created"); se.GetConvertedType() != parquet.ConvertedType_TIMESTAMP_MILLIS || !se.LogicalType.TIMESTAMP.IsAdjustedToUTC {
+		t.Errorf("created: %v", se)
+	}
+	if se := element("Local"); se.IsSetConvertedType() || se.LogicalType.TIMESTAMP.IsAdjustedToUTC || !se.LogicalType.TIMESTAMP.Unit.IsSetMICROS() {
+		t.Errorf("local: %v", se)
+	}
+	if se := element("Uid"); se.GetType() != parquet.Type_BYTE_ARRAY || se.GetConvertedType() != parquet.ConvertedType_UTF8 || se.LogicalType.IsSetUUID() {
+		t.Errorf("uid: %v", se)
+	}
+	if se := element("Balance"); se.GetConvertedType() != parquet.ConvertedType_DECIMAL || se.GetPrecision() != 9 || se.GetScale() != 2 {
+		t.Errorf("balance: %v", se)
+	}
+	if se := element("Color"); se.GetConvertedType() != parquet.ConvertedType_ENUM {
+		t.Errorf("color: %v", se)
+	}
+	if se := element("Tags\x01List\x01Element"); se.GetRepetitionType() != parquet.FieldRepetitionType_OPTIONAL {
+		t.Errorf("tags: %v", se)
+	}
+	if se := element("Scores\x01Key_value\x01Value"); se.GetType() != parquet.Type_DOUBLE || se.GetRepetitionType() != parquet.FieldRepetitionType_REQUIRED {
+		t.Errorf("scores: %v", se)
+	}
+	element("Old_address\x01City")
+	element("Value\x01Member0")
+	element("Value\x01Member1")
+
+	for _, avsc := range []string{
+		`{"type": "enum", "name": "E", "symbols": ["A"]}`,
+		`{"type": "record", "name": "R", "fields": [{"name": "r", "type": ["null", "R"]}]}`,
+		`{"type": "record", "name": "R", "fields": [{"name": "x", "type": "Unknown"}]}`,
+	} {
+		if _, err := NewSchemaHandlerFromAvro(avsc); err == nil {
+			t.Errorf("%v: expect error", avsc)
+		}
+	}
+}
+
+func TestConvertToAvroSchema(t *testing.T) {
+	sh, err := NewSchemaHandlerFromAvro(testAvroSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	avsc, err := ConvertToAvroSchema(sh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var record struct {
+		Name      string
+		Namespace string
+		Fields    []struct {
+			Name    string
+			Type    interface{}
+			Default interface{}
+		}
+	}
+	if err := json.Unmarshal([]byte(avsc), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Name != "User" || record.Namespace != "com.example" {
+		t.Errorf("expect record com.example.User, get %v.%v", record.Namespace, record.Name)
+	}
+	types := make(map[string]string)
+	for _, f := range record.Fields {
+		bs, _ := json.Marshal(f.Type)
+		types[f.Name] = string(bs)
+	}
+	expected := map[string]string{
+		"id":       `"long"`,
+		"name":     `["null","string"]`,
+		"created":  `{"logicalType":"timestamp-millis","type":"long"}`,
+		"local":    `{"logicalType":"local-timestamp-micros","type":"long"}`,
+		"birthday": `["null",{"logicalType":"date","type":"int"}]`,
+		"uid":      `"string"`,
+		"balance":  `{"logicalType":"decimal","precision":9,"scale":2,"type":"bytes"}`,
+		"color":    `"string"`,
+		"tags":     `{"items":["null","string"],"type":"array"}`,
+		"scores":   `{"type":"map","values":"double"}`,
+	}
+	for name, typ := range expected {
+		if types[name] != typ {
+			t.Errorf("%v: expect %v, get %v", name, typ, types[name])
+		}
+	}
+
+	//the exported schema is imported to the same parquet schema except the enum
+	sh2, err := NewSchemaHandlerFromAvro(avsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sh.SchemaElements) != len(sh2.SchemaElements) {
+		t.Fatalf("expect %v elements, get %v", len(sh.SchemaElements), len(sh2.SchemaElements))
+	}
+	for i, se := range sh.SchemaElements {
+		if se.GetName() == "Color" {
+			continue
+		}
+		if !reflect.DeepEqual(se, sh2.SchemaElements[i]) {
+			t.Errorf("expect %v, get %v", se, sh2.SchemaElements[i])
+		}
+	}
+}
@@ -2,6 +2,9 @@ package schema
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/xitongsys/parquet-go/common"
 	"github.com/xitongsys/parquet-go/parquet"
@@ -44,3 +47,71 @@ func NewSchemaHandlerFromMetadata(mds []string) (*SchemaHandler, error) {
 	res.CreateInExMap()
 	return res, nil
 }
+
+//Types tried by InferMetadataFromCSV, from the most specific one
+var csvInferredTypes = []struct {
+	tag   string
+	match func(s string, timestampLayout string) bool
+}{
+	{"type=BOOLEAN", func(s string, timestampLayout string) bool {
+		return strings.EqualFold(s, "true") || strings.EqualFold(s, "false")
+	}},
+	{"type=INT64", func(s string, timestampLayout string) bool {
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	}},
+	{"type=DOUBLE", func(s string, timestampLayout string) bool {
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	}},
+	{"type=INT32, convertedtype=DATE", func(s string, timestampLayout string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	}},
+	{"type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MICROS", func(s string, timestampLayout string) bool {
+		if timestampLayout == "" {
+			timestampLayout = time.RFC3339Nano
+		}
+		_, err := time.Parse(timestampLayout, s)
+		return err == nil
+	}},
+}
+
+/*
+InferMetadataFromCSV infers the CSV metadata of NewSchemaHandlerFromMetadata from a sample of the records.
+A column is BOOLEAN, INT64, DOUBLE, DATE (2006-01-02) or TIMESTAMP (timestampLayout, RFC3339 if it's empty)
+if all its non null values in the sample are, otherwise it's UTF8. ',' and '=' in the names are replaced by '_'.
+All the columns are OPTIONAL, even if the sample has no null values: the records after the sample
+may have some, and they would be row errors in a REQUIRED column.
+*/
+func InferMetadataFromCSV(names []string, sample [][]string, isNull func(s string) bool, timestampLayout string) []string {
+	res := make([]string, len(names))
+	for i, name := range names {
+		candidates := make([]bool, len(csvInferredTypes))
+		for j := range candidates {
+			candidates[j] = true
+		}
+		hasValue := false
+		for _, record := range sample {
+			if i >= len(record) || isNull(record[i]) {
+				continue
+			}
+			hasValue = true
+			s := strings.TrimSpace(record[i])
+			for j, t := range csvInferredTypes {
+				candidates[j] = candidates[j] && t.match(s, timestampLayout)
+			}
+		}
+
+		tag := "type=BYTE_ARRAY, convertedtype=UTF8"
+		for j, t := range csvInferredTypes {
+			if hasValue && candidates[j] {
+				tag = t.tag
+				break
+			}
+		}
+		name = strings.NewReplacer(",", "_", "=", "_").Replace(name)
+		res[i] = fmt.Sprintf("name=%s, %s, repetitiontype=OPTIONAL", name, tag)
+	}
+	return res
+}
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferMetadataFromCSV(t *testing.T) {
+	names := []string{"b", "i", "f", "d", "ts", "s", "n", "a,b=c"}
+	sample := [][]string{
+		{"true", "1", "1", "2000-01-02", "2021-03-04T05:06:07Z", "x", "", "1"},
+		{"FALSE", "-2", "1.5", "", "2021-03-04T05:06:07.123+02:00", "1", "", "true"},
+		{"", " 3", "1e3", "2000-12-31", "", "", ""},
+	}
+	expected := []string{
+		"name=b, type=BOOLEAN, repetitiontype=OPTIONAL",
+		"name=i, type=INT64, repetitiontype=OPTIONAL",
+		"name=f, type=DOUBLE, repetitiontype=OPTIONAL",
+		"name=d, type=INT32, convertedtype=DATE, repetitiontype=OPTIONAL",
+		"name=ts, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MICROS, repetitiontype=OPTIONAL",
+		"name=s, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		"name=n, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		"name=a_b_c, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+	}
+	md := InferMetadataFromCSV(names, sample, func(s string) bool { return s == "" }, "")
+	if !reflect.DeepEqual(md, expected) {
+		t.Errorf("expected %v, got %v", expected, md)
+	}
+	if _, err := NewSchemaHandlerFromMetadata(md); err != nil {
+		t.Errorf("invalid inferred metadata: %v", err)
+	}
+
+	//the timestamps of the layout of the converter
+	md = InferMetadataFromCSV([]string{"ts"}, [][]string{{"2021-03-04 05:06:07"}, {"2021-12-31 23:59:59"}},
+		func(s string) bool { return s == "" }, "2006-01-02 15:04:05")
+	if md[0] != expected[4] {
+		t.Errorf("expected %v, got %v", expected[4], md[0])
+	}
+}
@@ -0,0 +1,351 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/types"
+)
+
+//CanPromote reports whether the values of the type can be read as the other type: INT32 as INT64 and FLOAT as DOUBLE
+func CanPromote(from parquet.Type, to parquet.Type) bool {
+	return (from == parquet.Type_INT32 && to == parquet.Type_INT64) ||
+		(from == parquet.Type_FLOAT && to == parquet.Type_DOUBLE)
+}
+
+//ReadConflicts returns the reasons why the column of the file element can't be read as the target element, it's empty if it can.
+//Diff reports the changes with the same rules.
+func ReadConflicts(file *parquet.SchemaElement, target *parquet.SchemaElement) []string {
+	fileIsLeaf, targetIsLeaf := file.GetNumChildren() == 0, target.GetNumChildren() == 0
+	if fileIsLeaf != targetIsLeaf {
+		return []string{"group and primitive column"}
+	}
+
+	var res []string
+	if conflict := repetitionConflict(file.GetRepetitionType(), target.GetRepetitionType()); conflict != "" {
+		res = append(res, conflict)
+	}
+	if !targetIsLeaf {
+		return res
+	}
+	if conflict := typeConflict(file, target); conflict != "" {
+		return append(res, conflict)
+	}
+	//the values must keep their meaning, e.g. the time units and the signs of the integers
+	if conflict := logicalTypeConflict(file, target); conflict != "" {
+		res = append(res, conflict)
+	}
+	return res
+}
+
+//ChangeKind is the kind of a SchemaChange
+type ChangeKind string
+
+const (
+	ChangeAdded       ChangeKind = "ADDED"
+	ChangeRemoved     ChangeKind = "REMOVED"
+	ChangeRenamed     ChangeKind = "RENAMED"
+	ChangeType        ChangeKind = "TYPE"
+	ChangeLogicalType ChangeKind = "LOGICAL_TYPE"
+	ChangeRepetition  ChangeKind = "REPETITION"
+)
+
+/*
+SchemaChange is a change of a field from the old schema to the new one. Path is the dotted path of the field
+in the new schema (the old one for REMOVED), OldPath is the path in the old schema if it's renamed.
+Old and New describe the changed type, logical type, repetition or name.
+BackwardCompatible is true if the files of the old schema can be read with the new one,
+ForwardCompatible is true if the files of the new schema can be read with the old one.
+*/
+type SchemaChange struct {
+	Kind               ChangeKind `json:"kind"`
+	Path               string     `json:"path"`
+	OldPath            string     `json:"oldPath,omitempty"`
+	Old                string     `json:"old,omitempty"`
+	New                string     `json:"new,omitempty"`
+	BackwardCompatible bool       `json:"backwardCompatible"`
+	ForwardCompatible  bool       `json:"forwardCompatible"`
+	Reason             string     `json:"reason,omitempty"`
+}
+
+//SchemaDiff is the result of Diff, it's compatible if all the changes are compatible
+type SchemaDiff struct {
+	Changes            []*SchemaChange `json:"changes"`
+	BackwardCompatible bool            `json:"backwardCompatible"`
+	ForwardCompatible  bool            `json:"forwardCompatible"`
+}
+
+type differ struct {
+	a, b           *SchemaHandler
+	matchByFieldID bool
+	res            *SchemaDiff
+}
+
+type DiffOption func(*differ)
+
+//WithDiffMatchByFieldID matches the fields by field_id like the reader created with WithMatchByFieldID, the renamed fields are compatible
+func WithDiffMatchByFieldID(match bool) DiffOption {
+	return func(d *differ) {
+		d.matchByFieldID = match
+	}
+}
+
+/*
+Diff compares the old schema a with the new schema b, e.g. the schema of the existing files with a changed struct.
+The fields are matched by name (or by field_id, see WithDiffMatchByFieldID) and the fields with the same field_id
+and different names are RENAMED. The compatibility follows the rules of the reader (ReadConflicts): added and removed
+columns are read as null (or zero values) and ignored, INT32 and FLOAT can be read as INT64 and DOUBLE, REQUIRED columns
+can be read as OPTIONAL. Logical type changes are compatible only if the values keep their meaning: string annotations
+of BYTE_ARRAY, wider integers and DECIMALs with more precision.
+*/
+func Diff(a, b *SchemaHandler, opts ...DiffOption) *SchemaDiff {
+	d := &differ{a: a, b: b, res: &SchemaDiff{Changes: []*SchemaChange{}}}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if len(a.SchemaElements) > 0 && len(b.SchemaElements) > 0 {
+		d.diffChildren(0, 0)
+	}
+	d.res.BackwardCompatible, d.res.ForwardCompatible = true, true
+	for _, c := range d.res.Changes {
+		d.res.BackwardCompatible = d.res.BackwardCompatible && c.BackwardCompatible
+		d.res.ForwardCompatible = d.res.ForwardCompatible && c.ForwardCompatible
+	}
+	return d.res
+}
+
+//Dotted path of the external names of the field without the root
+func fieldPath(sh *SchemaHandler, idx int32) string {
+	exPath := common.StrToPath(sh.InPathToExPath[sh.IndexMap[idx]])
+	return strings.Join(exPath[1:], ".")
+}
+
+func (d *differ) key(sh *SchemaHandler, idx int32) string {
+	if d.matchByFieldID && sh.HasFieldID(idx) {
+		return fmt.Sprintf("#%d", sh.SchemaElements[idx].GetFieldID())
+	}
+	return sh.GetExName(int(idx))
+}
+
+func (d *differ) add(c *SchemaChange) {
+	d.res.Changes = append(d.res.Changes, c)
+}
+
+func (d *differ) diffChildren(aIdx, bIdx int32) {
+	aChildren, bChildren := d.a.ChildrenIndex(aIdx), d.b.ChildrenIndex(bIdx)
+	bKeys := make(map[string]int32)
+	for _, idx := range bChildren {
+		bKeys[d.key(d.b, idx)] = idx
+	}
+
+	paired := make(map[int32]bool)
+	var removed []int32
+	for _, idx := range aChildren {
+		if bIdx, ok := bKeys[d.key(d.a, idx)]; ok {
+			paired[bIdx] = true
+			d.diffField(idx, bIdx, true)
+		} else {
+			removed = append(removed, idx)
+		}
+	}
+
+	//the removed and added fields with the same field_id are renamed, they aren't matched by name
+	bFieldIDs := make(map[int32]int32)
+	for _, idx := range bChildren {
+		if !paired[idx] && d.b.HasFieldID(idx) {
+			bFieldIDs[d.b.SchemaElements[idx].GetFieldID()] = idx
+		}
+	}
+	for _, idx := range removed {
+		if bIdx, ok := bFieldIDs[d.a.SchemaElements[idx].GetFieldID()]; ok && d.a.HasFieldID(idx) {
+			paired[bIdx] = true
+			d.diffField(idx, bIdx, false)
+			continue
+		}
+		d.add(&SchemaChange{Kind: ChangeRemoved, Path: fieldPath(d.a, idx), Old: d.describe(d.a, idx),
+			BackwardCompatible: true, ForwardCompatible: true})
+	}
+	for _, idx := range bChildren {
+		if !paired[idx] {
+			d.add(&SchemaChange{Kind: ChangeAdded, Path: fieldPath(d.b, idx), New: d.describe(d.b, idx),
+				BackwardCompatible: true, ForwardCompatible: true})
+		}
+	}
+}
+
+//Compare the matched fields, matched is false if they are only matched by the field_id
+func (d *differ) diffField(aIdx, bIdx int32, matched bool) {
+	aSE, bSE := d.a.SchemaElements[aIdx], d.b.SchemaElements[bIdx]
+	path := fieldPath(d.b, bIdx)
+
+	if aName, bName := d.a.GetExName(int(aIdx)), d.b.GetExName(int(bIdx)); aName != bName {
+		c := &SchemaChange{Kind: ChangeRenamed, Path: path, OldPath: fieldPath(d.a, aIdx), Old: aName, New: bName,
+			BackwardCompatible: d.matchByFieldID, ForwardCompatible: d.matchByFieldID}
+		if !matched {
+			c.Reason = "the fields are matched by name, the renamed field is read as null"
+		}
+		d.add(c)
+	}
+
+	if (aSE.GetNumChildren() == 0) != (bSE.GetNumChildren() == 0) {
+		d.add(&SchemaChange{Kind: ChangeType, Path: path, Old: d.describe(d.a, aIdx), New: d.describe(d.b, bIdx),
+			Reason: "group and primitive column"})
+		return
+	}
+
+	if aRT, bRT := aSE.GetRepetitionType(), bSE.GetRepetitionType(); aRT != bRT {
+		d.add(d.compatibility(&SchemaChange{Kind: ChangeRepetition, Path: path, Old: aRT.String(), New: bRT.String()},
+			repetitionConflict(aRT, bRT), repetitionConflict(bRT, aRT)))
+	}
+
+	if aSE.GetNumChildren() > 0 {
+		d.diffChildren(aIdx, bIdx)
+		return
+	}
+
+	if aType, bType := physicalTypeString(aSE), physicalTypeString(bSE); aType != bType {
+		d.add(d.compatibility(&SchemaChange{Kind: ChangeType, Path: path, Old: aType, New: bType},
+			typeConflict(aSE, bSE), typeConflict(bSE, aSE)))
+	}
+	if aLT, bLT := logicalTypeString(aSE), logicalTypeString(bSE); aLT != bLT {
+		d.add(d.compatibility(&SchemaChange{Kind: ChangeLogicalType, Path: path, Old: aLT, New: bLT},
+			logicalTypeConflict(aSE, bSE), logicalTypeConflict(bSE, aSE)))
+	}
+}
+
+//Set the compatibility of the change by the conflicts of reading the old as new and the new as old
+func (d *differ) compatibility(c *SchemaChange, backward string, forward string) *SchemaChange {
+	c.BackwardCompatible, c.ForwardCompatible = backward == "", forward == ""
+	if backward != "" {
+		c.Reason = backward
+	} else {
+		c.Reason = forward
+	}
+	return c
+}
+
+//Type of the field, e.g. INT32, FIXED_LEN_BYTE_ARRAY(16) (UUID) or OPTIONAL group
+func (d *differ) describe(sh *SchemaHandler, idx int32) string {
+	se := sh.SchemaElements[idx]
+	res := se.GetRepetitionType().String() + " "
+	if se.GetNumChildren() > 0 {
+		res += "group"
+	} else {
+		res += physicalTypeString(se)
+	}
+	if lt := logicalTypeString(se); lt != "" {
+		res += " (" + lt + ")"
+	}
+	return res
+}
+
+func physicalTypeString(se *parquet.SchemaElement) string {
+	if se.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY {
+		return fmt.Sprintf("FIXED_LEN_BYTE_ARRAY(%d)", se.GetTypeLength())
+	}
+	return se.GetType().String()
+}
+
+/*
+Logical type of the element in the message type format, the converted types are converted to logical types.
+The converted TIME and TIMESTAMP types are adjusted to UTC, they win over the logical types
+which this library wrote with isAdjustedToUTC=false.
+*/
+func logicalTypeString(se *parquet.SchemaElement) string {
+	isTime := false
+	switch se.GetConvertedType() {
+	case parquet.ConvertedType_TIME_MILLIS, parquet.ConvertedType_TIME_MICROS,
+		parquet.ConvertedType_TIMESTAMP_MILLIS, parquet.ConvertedType_TIMESTAMP_MICROS:
+		isTime = true
+	}
+	if (se.LogicalType == nil || isTime) && se.IsSetConvertedType() {
+		info := &common.Tag{Scale: se.GetScale(), Precision: se.GetPrecision(), IsAdjustedToUTC: true}
+		if lt := common.NewLogicalTypeFromConvertedType(se, info); lt != nil {
+			withLT := *se
+			withLT.LogicalType = lt
+			return messageTypeAnnotation(&withLT)
+		}
+	}
+	return messageTypeAnnotation(se)
+}
+
+func repetitionConflict(file, target parquet.FieldRepetitionType) string {
+	if (file == parquet.FieldRepetitionType_REPEATED) != (target == parquet.FieldRepetitionType_REPEATED) ||
+		(file == parquet.FieldRepetitionType_OPTIONAL && target == parquet.FieldRepetitionType_REQUIRED) {
+		return fmt.Sprintf("can't read %v as %v", file, target)
+	}
+	return ""
+}
+
+func typeConflict(file, target *parquet.SchemaElement) string {
+	if file.GetType() != target.GetType() {
+		if !CanPromote(file.GetType(), target.GetType()) {
+			return fmt.Sprintf("can't read %v as %v", file.GetType(), target.GetType())
+		}
+	} else if file.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY && file.GetTypeLength() != target.GetTypeLength() {
+		return fmt.Sprintf("can't read FIXED_LEN_BYTE_ARRAY(%v) as FIXED_LEN_BYTE_ARRAY(%v)", file.GetTypeLength(), target.GetTypeLength())
+	}
+	return ""
+}
+
+func logicalTypeConflict(file, target *parquet.SchemaElement) string {
+	fileLT, targetLT := logicalTypeString(file), logicalTypeString(target)
+	if fileLT == targetLT {
+		return ""
+	}
+	fileScale, filePrecision, fileIsDecimal := types.DecimalScale(file)
+	targetScale, targetPrecision, targetIsDecimal := types.DecimalScale(target)
+	if fileIsDecimal && targetIsDecimal {
+		if fileScale != targetScale {
+			return fmt.Sprintf("can't read DECIMAL with scale %v as scale %v", fileScale, targetScale)
+		}
+		if filePrecision > targetPrecision {
+			return fmt.Sprintf("can't read %v as %v", fileLT, targetLT)
+		}
+		return ""
+	}
+
+	isString := func(lt string) bool {
+		return lt == "" || lt == "STRING" || lt == "ENUM" || lt == "JSON"
+	}
+	if file.GetType() == parquet.Type_BYTE_ARRAY && target.GetType() == parquet.Type_BYTE_ARRAY && isString(fileLT) && isString(targetLT) {
+		return ""
+	}
+
+	fileInt, targetInt := integerType(file, fileLT), integerType(target, targetLT)
+	if from, to := fileInt, targetInt; from != nil && to != nil {
+		//the values fit in the target integers
+		if from.IsSigned == to.IsSigned && from.BitWidth <= to.BitWidth || !from.IsSigned && to.IsSigned && from.BitWidth < to.BitWidth {
+			return ""
+		}
+	}
+	if fileLT == "" {
+		fileLT = "no logical type"
+	}
+	if targetLT == "" {
+		targetLT = "no logical type"
+	}
+	return fmt.Sprintf("can't read %v as %v", fileLT, targetLT)
+}
+
+//Integer type of the INT32 and INT64 elements, the plain integers are signed
+func integerType(se *parquet.SchemaElement, lt string) *parquet.IntType {
+	if se.GetType() != parquet.Type_INT32 && se.GetType() != parquet.Type_INT64 {
+		return nil
+	}
+	if lt == "" {
+		if se.GetType() == parquet.Type_INT32 {
+			return &parquet.IntType{BitWidth: 32, IsSigned: true}
+		}
+		return &parquet.IntType{BitWidth: 64, IsSigned: true}
+	}
+	if se.LogicalType != nil && se.LogicalType.IsSetINTEGER() {
+		return se.LogicalType.INTEGER
+	}
+	if lt := common.NewLogicalTypeFromConvertedType(se, &common.Tag{}); lt != nil && lt.IsSetINTEGER() {
+		return lt.INTEGER
+	}
+	return nil
+}
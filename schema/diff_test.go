@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type Old struct {
+		ID      int32   `parquet:"name=id, type=INT32, fieldid=1"`
+		Name    string  `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=2"`
+		Score   float64 `parquet:"name=score, type=DOUBLE"`
+		Age     *int32  `parquet:"name=age, type=INT32, convertedtype=INT_16"`
+		Price   int64   `parquet:"name=price, type=INT64, convertedtype=DECIMAL, scale=2, precision=10"`
+		Removed string  `parquet:"name=removed, type=BYTE_ARRAY"`
+		Flag    bool    `parquet:"name=flag, type=BOOLEAN"`
+	}
+	type New struct {
+		ID       int64   `parquet:"name=id, type=INT64, fieldid=1"`
+		FullName string  `parquet:"name=full_name, type=BYTE_ARRAY, convertedtype=ENUM, fieldid=2"`
+		Score    float32 `parquet:"name=score, type=FLOAT"`
+		Age      int32   `parquet:"name=age, type=INT32, convertedtype=INT_32"`
+		Price    int64   `parquet:"name=price, type=INT64, convertedtype=DECIMAL, scale=2, precision=18"`
+		Flag     *bool   `parquet:"name=flag, type=BOOLEAN"`
+		Added    *string `parquet:"name=added, type=BYTE_ARRAY, convertedtype=UTF8"`
+	}
+	a, err := NewSchemaHandlerFromStruct(new(Old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSchemaHandlerFromStruct(new(New))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type change struct {
+		kind              ChangeKind
+		path, old, new    string
+		backward, forward bool
+	}
+	summary := func(diff *SchemaDiff) []change {
+		var res []change
+		for _, c := range diff.Changes {
+			res = append(res, change{c.Kind, c.Path, c.Old, c.New, c.BackwardCompatible, c.ForwardCompatible})
+		}
+		return res
+	}
+
+	diff := Diff(a, b)
+	expected := []change{
+		{ChangeType, "id", "INT32", "INT64", true, false},
+		{ChangeType, "score", "DOUBLE", "FLOAT", false, true},
+		{ChangeRepetition, "age", "OPTIONAL", "REQUIRED", false, true},
+		{ChangeLogicalType, "age", "INTEGER(16,true)", "INTEGER(32,true)", true, false},
+		{ChangeLogicalType, "price", "DECIMAL(10,2)", "DECIMAL(18,2)", true, false},
+		{ChangeRepetition, "flag", "REQUIRED", "OPTIONAL", true, false},
+		{ChangeRenamed, "full_name", "name", "full_name", false, false},
+		{ChangeLogicalType, "full_name", "STRING", "ENUM", true, true},
+		{ChangeRemoved, "removed", "REQUIRED BYTE_ARRAY", "", true, true},
+		{ChangeAdded, "added", "", "OPTIONAL BYTE_ARRAY (STRING)", true, true},
+	}
+	if res := summary(diff); !reflect.DeepEqual(res, expected) {
+		t.Errorf("expect\n%v\nget\n%v", expected, res)
+	}
+	if diff.BackwardCompatible || diff.ForwardCompatible {
+		t.Errorf("expect incompatible diff")
+	}
+	if c := diff.Changes[6]; c.OldPath != "name" || c.Reason == "" {
+		t.Errorf("renamed: %+v", c)
+	}
+
+	//the renamed field is compatible if the fields are matched by field_id
+	diff = Diff(a, b, WithDiffMatchByFieldID(true))
+	if c := diff.Changes[1]; c.Kind != ChangeRenamed || !c.BackwardCompatible || !c.ForwardCompatible {
+		t.Errorf("renamed: %+v", c)
+	}
+
+	if diff := Diff(a, a); len(diff.Changes) != 0 || !diff.BackwardCompatible || !diff.ForwardCompatible {
+		t.Errorf("expect no changes, get %+v", diff)
+	}
+}
+
+func TestDiffFieldIDZero(t *testing.T) {
+	type Old struct {
+		Name string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=0"`
+	}
+	type New struct {
+		FullName string `parquet:"name=full_name, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=0"`
+	}
+	a, err := NewSchemaHandlerFromStruct(new(Old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSchemaHandlerFromStruct(new(New))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := Diff(a, b, WithDiffMatchByFieldID(true))
+	if len(diff.Changes) != 1 || diff.Changes[0].Kind != ChangeRenamed || !diff.BackwardCompatible {
+		t.Errorf("expect the field_id 0 to match the renamed field, get %+v", diff.Changes)
+	}
+
+	//all the field_ids are 0 in the files of older versions, they aren't field_ids
+	for _, se := range append(a.SchemaElements, b.SchemaElements...) {
+		fieldID := int32(0)
+		se.FieldID = &fieldID
+	}
+	diff = Diff(a, b, WithDiffMatchByFieldID(true))
+	if len(diff.Changes) != 2 || diff.Changes[0].Kind != ChangeRemoved || diff.Changes[1].Kind != ChangeAdded {
+		t.Errorf("expect the fields to be matched by name, get %+v", diff.Changes)
+	}
+}
+
+func TestDiffConvertedTimestamp(t *testing.T) {
+	type Old struct {
+		Time int64 `parquet:"name=time, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	}
+	type New struct {
+		Time int64 `parquet:"name=time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MILLIS"`
+	}
+	type Micros struct {
+		Time int64 `parquet:"name=time, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	}
+	a, err := NewSchemaHandlerFromStruct(new(Old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewSchemaHandlerFromStruct(new(New))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewSchemaHandlerFromStruct(new(Micros))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the converted TIMESTAMP_MILLIS is adjusted to UTC
+	if diff := Diff(a, b); len(diff.Changes) != 0 {
+		t.Errorf("expect no changes, get %+v", diff.Changes[0])
+	}
+	diff := Diff(a, c)
+	if len(diff.Changes) != 1 || diff.Changes[0].Old != "TIMESTAMP(MILLIS,true)" || diff.Changes[0].New != "TIMESTAMP(MICROS,true)" ||
+		diff.BackwardCompatible || diff.ForwardCompatible {
+		t.Errorf("expect an incompatible change of the time unit, get %+v", diff.Changes)
+	}
+	//the reader has the same rules
+	if conflicts := ReadConflicts(a.SchemaElements[1], c.SchemaElements[1]); len(conflicts) != 1 || conflicts[0] != diff.Changes[0].Reason {
+		t.Errorf("expect the conflict %q, get %v", diff.Changes[0].Reason, conflicts)
+	}
+}
@@ -35,7 +35,7 @@ func (sh *SchemaHandler) GetTypes() []reflect.Type {
 			curlen := len(stack) - 1
 			idx := stack[curlen][0]
 			nc := sh.SchemaElements[idx].GetNumChildren()
-			pT, cT := sh.SchemaElements[idx].Type, sh.SchemaElements[idx].ConvertedType
+			pT := sh.SchemaElements[idx].Type
 			rT := sh.SchemaElements[idx].RepetitionType
 
 			if nc == 0 {
@@ -47,21 +47,20 @@ func (sh *SchemaHandler) GetTypes() []reflect.Type {
 				}
 
 			} else {
-				if cT != nil && *cT == parquet.ConvertedType_LIST &&
-					len(elements[idx]) == 1 &&
-					sh.GetInName(int(elements[idx][0])) == "List" &&
-					len(elements[elements[idx][0]]) == 1 &&
-					sh.GetInName(int(elements[elements[idx][0]][0])) == "Element" {
-					cidx := elements[elements[idx][0]][0]
-					elementTypes[idx] = reflect.SliceOf(elementTypes[cidx])
-
-				} else if cT != nil && *cT == parquet.ConvertedType_MAP &&
-					len(elements[idx]) == 1 &&
-					sh.GetInName(int(elements[idx][0])) == "Key_value" &&
-					len(elements[elements[idx][0]]) == 2 &&
-					sh.GetInName(int(elements[elements[idx][0]][0])) == "Key" &&
-					sh.GetInName(int(elements[elements[idx][0]][1])) == "Value" {
-					kIdx, vIdx := elements[elements[idx][0]][0], elements[elements[idx][0]][1]
+				elementIdx, listLevels := sh.ListElementIndex(idx)
+				kIdx, vIdx, isMap := sh.MapKeyValueIndex(idx)
+				if isMap && !elementTypes[kIdx].Comparable() {
+					isMap = false
+				}
+
+				if listLevels == 2 {
+					//the repeated field is the element and its type is already a slice
+					elementTypes[idx] = elementTypes[elementIdx]
+
+				} else if listLevels == 3 {
+					elementTypes[idx] = reflect.SliceOf(elementTypes[elementIdx])
+
+				} else if isMap {
 					kT, vT := elementTypes[kIdx], elementTypes[vIdx]
 					elementTypes[idx] = reflect.MapOf(kT, vT)
 
@@ -54,6 +54,7 @@ func NewSchemaHandlerFromJSON(str string) (sh *SchemaHandler, err error) {
 		if info.Type == "" { //struct
 			schema := parquet.NewSchemaElement()
 			schema.Name = info.InName
+			common.SetFieldID(schema, info)
 			rt := info.RepetitionType
 			schema.RepetitionType = &rt
 			numField := int32(len(item.Fields))
@@ -72,6 +73,7 @@ func NewSchemaHandlerFromJSON(str string) (sh *SchemaHandler, err error) {
 		} else if info.Type == "LIST" { //list
 			schema := parquet.NewSchemaElement()
 			schema.Name = info.InName
+			common.SetFieldID(schema, info)
 			rt1 := info.RepetitionType
 			schema.RepetitionType = &rt1
 			var numField1 int32 = 1
@@ -105,6 +107,7 @@ func NewSchemaHandlerFromJSON(str string) (sh *SchemaHandler, err error) {
 		} else if info.Type == "MAP" { //map
 			schema := parquet.NewSchemaElement()
 			schema.Name = info.InName
+			common.SetFieldID(schema, info)
 			rt1 := info.RepetitionType
 			schema.RepetitionType = &rt1
 			var numField1 int32 = 1
@@ -141,7 +144,7 @@ func NewSchemaHandlerFromJSON(str string) (sh *SchemaHandler, err error) {
 		} else { //normal variable
 			schema, err := common.NewSchemaElementFromTagMap(info)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create schema from tag map: %s", err.Error())
+				return nil, fmt.Errorf("failed to create schema from tag map of %s: %s", info.ExName, err.Error())
 			}
 			schemaElements = append(schemaElements, schema)
 
@@ -153,5 +156,8 @@ func NewSchemaHandlerFromJSON(str string) (sh *SchemaHandler, err error) {
 	res := NewSchemaHandlerFromSchemaList(schemaElements)
 	res.Infos = infos
 	res.CreateInExMap()
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
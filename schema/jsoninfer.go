@@ -0,0 +1,309 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type jsonKind int
+
+const (
+	jsonNull jsonKind = iota
+	jsonBool
+	jsonInt
+	jsonFloat
+	jsonTimestamp
+	jsonString
+	jsonObject
+	jsonArray
+	jsonMap
+)
+
+func (k jsonKind) String() string {
+	return [...]string{"null", "boolean", "integer", "number", "timestamp", "string", "object", "array", "map"}[k]
+}
+
+func (k jsonKind) isScalar() bool {
+	return k != jsonObject && k != jsonArray && k != jsonMap
+}
+
+//Inferred type of a JSON value
+type jsonNode struct {
+	kind jsonKind
+	//object fields in the order of their first occurrence
+	names  []string
+	fields map[string]*jsonNode
+	//array element or map value
+	elem *jsonNode
+}
+
+type jsonInferrer struct {
+	mapPaths     map[string]bool
+	mapThreshold int
+}
+
+type JSONInferOption func(*jsonInferrer)
+
+//WithJSONMapPaths infers the objects at the JSON paths (e.g. $.a.b, $.list[]) as maps
+func WithJSONMapPaths(paths ...string) JSONInferOption {
+	return func(r *jsonInferrer) {
+		for _, path := range paths {
+			r.mapPaths[path] = true
+		}
+	}
+}
+
+//WithJSONMapThreshold infers the objects with more than n different keys as maps, default is 100. 0 disables it.
+func WithJSONMapThreshold(n int) JSONInferOption {
+	return func(r *jsonInferrer) {
+		r.mapThreshold = n
+	}
+}
+
+//Infer the type of the next JSON value of d, the fields keep their order in the record
+func (r *jsonInferrer) infer(d *json.Decoder, path string) (*jsonNode, error) {
+	t, err := d.Token()
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+	switch x := t.(type) {
+	case bool:
+		return &jsonNode{kind: jsonBool}, nil
+	case json.Number:
+		if _, err := strconv.ParseInt(string(x), 10, 64); err == nil {
+			return &jsonNode{kind: jsonInt}, nil
+		}
+		return &jsonNode{kind: jsonFloat}, nil
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, x); err == nil {
+			return &jsonNode{kind: jsonTimestamp}, nil
+		}
+		return &jsonNode{kind: jsonString}, nil
+	case json.Delim:
+		if x == '[' {
+			res := &jsonNode{kind: jsonArray, elem: &jsonNode{kind: jsonNull}}
+			for d.More() {
+				elem, err := r.infer(d, path+"[]")
+				if err != nil {
+					return nil, err
+				}
+				if res.elem, err = r.merge(res.elem, elem, path+"[]"); err != nil {
+					return nil, err
+				}
+			}
+			_, err = d.Token()
+			return res, err
+		}
+
+		res := &jsonNode{kind: jsonObject, fields: make(map[string]*jsonNode)}
+		for d.More() {
+			t, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			name := t.(string)
+			field, err := r.infer(d, path+"."+name)
+			if err != nil {
+				return nil, err
+			}
+			if old, ok := res.fields[name]; ok {
+				if field, err = r.merge(old, field, path+"."+name); err != nil {
+					return nil, err
+				}
+			} else {
+				res.names = append(res.names, name)
+			}
+			res.fields[name] = field
+		}
+		_, err = d.Token()
+		return res, err
+	}
+	return &jsonNode{kind: jsonNull}, nil
+}
+
+//Widen a and b to a type which can hold both of them
+func (r *jsonInferrer) merge(a, b *jsonNode, path string) (*jsonNode, error) {
+	switch {
+	case a.kind == jsonNull:
+		return b, nil
+	case b.kind == jsonNull:
+		return a, nil
+	case a.kind == b.kind && a.kind.isScalar():
+		return a, nil
+	case a.kind.isScalar() && b.kind.isScalar():
+		if (a.kind == jsonInt && b.kind == jsonFloat) || (a.kind == jsonFloat && b.kind == jsonInt) {
+			return &jsonNode{kind: jsonFloat}, nil
+		}
+		return &jsonNode{kind: jsonString}, nil
+	case a.kind == jsonArray && b.kind == jsonArray:
+		elem, err := r.merge(a.elem, b.elem, path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		return &jsonNode{kind: jsonArray, elem: elem}, nil
+	case a.kind == jsonObject && b.kind == jsonObject:
+		res := &jsonNode{kind: jsonObject, names: append([]string{}, a.names...), fields: make(map[string]*jsonNode)}
+		for name, field := range a.fields {
+			res.fields[name] = field
+		}
+		for _, name := range b.names {
+			field, ok := res.fields[name]
+			if !ok {
+				res.names = append(res.names, name)
+				res.fields[name] = b.fields[name]
+				continue
+			}
+			var err error
+			if res.fields[name], err = r.merge(field, b.fields[name], path+"."+name); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case (a.kind == jsonMap || a.kind == jsonObject) && (b.kind == jsonMap || b.kind == jsonObject):
+		ma, err := r.toMap(a, path)
+		if err != nil {
+			return nil, err
+		}
+		mb, err := r.toMap(b, path)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := r.merge(ma.elem, mb.elem, path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		return &jsonNode{kind: jsonMap, elem: elem}, nil
+	}
+	return nil, fmt.Errorf("%v: can't merge %v and %v", path, a.kind, b.kind)
+}
+
+func (r *jsonInferrer) toMap(n *jsonNode, path string) (*jsonNode, error) {
+	if n.kind == jsonMap {
+		return n, nil
+	}
+	res := &jsonNode{kind: jsonMap, elem: &jsonNode{kind: jsonNull}}
+	for _, name := range n.names {
+		var err error
+		if res.elem, err = r.merge(res.elem, n.fields[name], path+"[]"); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+//Turn the objects into maps by the options, bottom up
+func (r *jsonInferrer) resolveMaps(n *jsonNode, path string) (*jsonNode, error) {
+	var err error
+	switch n.kind {
+	case jsonArray:
+		n.elem, err = r.resolveMaps(n.elem, path+"[]")
+	case jsonMap:
+		n.elem, err = r.resolveMaps(n.elem, path+"[]")
+	case jsonObject:
+		for _, name := range n.names {
+			if n.fields[name], err = r.resolveMaps(n.fields[name], path+"."+name); err != nil {
+				return nil, err
+			}
+		}
+		//an object without fields can't be a group
+		if r.mapPaths[path] || len(n.names) == 0 || (r.mapThreshold > 0 && len(n.names) > r.mapThreshold) {
+			return r.toMap(n, path)
+		}
+	}
+	return n, err
+}
+
+var jsonScalarTags = map[jsonKind]string{
+	jsonNull:      "type=BYTE_ARRAY, convertedtype=UTF8",
+	jsonBool:      "type=BOOLEAN",
+	jsonInt:       "type=INT64",
+	jsonFloat:     "type=DOUBLE",
+	jsonTimestamp: "type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MICROS",
+	jsonString:    "type=BYTE_ARRAY, convertedtype=UTF8",
+}
+
+func jsonSchemaItem(n *jsonNode, name string, repetitionType string) *JSONSchemaItemType {
+	//',' and '=' are separators of the tags
+	name = strings.NewReplacer(",", "_", "=", "_").Replace(name)
+	item := NewJSONSchemaItem()
+	switch n.kind {
+	case jsonObject:
+		item.Tag = fmt.Sprintf("name=%s, repetitiontype=%s", name, repetitionType)
+		for _, fieldName := range n.names {
+			item.Fields = append(item.Fields, jsonSchemaItem(n.fields[fieldName], fieldName, "OPTIONAL"))
+		}
+	case jsonArray:
+		item.Tag = fmt.Sprintf("name=%s, type=LIST, repetitiontype=%s", name, repetitionType)
+		item.Fields = []*JSONSchemaItemType{jsonSchemaItem(n.elem, "element", "OPTIONAL")}
+	case jsonMap:
+		item.Tag = fmt.Sprintf("name=%s, type=MAP, repetitiontype=%s", name, repetitionType)
+		item.Fields = []*JSONSchemaItemType{
+			{Tag: "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+			jsonSchemaItem(n.elem, "value", "OPTIONAL"),
+		}
+	default:
+		item.Tag = fmt.Sprintf("name=%s, %s, repetitiontype=%s", name, jsonScalarTags[n.kind], repetitionType)
+	}
+	return item
+}
+
+/*
+InferJSONSchema infers the JSON schema of NewSchemaHandlerFromJSON from a sample of JSON objects, e.g. the lines of NDJSON.
+Integers are INT64, other numbers DOUBLE, RFC3339 strings TIMESTAMP(MICROS), objects groups or maps and arrays LIST.
+The types are widened across the records: integer and number to number, other different scalars to string.
+All the fields are OPTIONAL and null values fit any type. An object can't be merged with an array or a scalar,
+the error names the JSON path of the field.
+*/
+func InferJSONSchema(records []string, opts ...JSONInferOption) (*JSONSchemaItemType, error) {
+	r := &jsonInferrer{mapPaths: make(map[string]bool), mapThreshold: 100}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	root := &jsonNode{kind: jsonObject, fields: make(map[string]*jsonNode)}
+	for i, record := range records {
+		d := json.NewDecoder(strings.NewReader(record))
+		d.UseNumber()
+		n, err := r.infer(d, "$")
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", i, err)
+		}
+		if n.kind != jsonObject {
+			return nil, fmt.Errorf("record %d: expect object, get %v", i, n.kind)
+		}
+		if root, err = r.merge(root, n, "$"); err != nil {
+			return nil, fmt.Errorf("record %d: %v", i, err)
+		}
+	}
+
+	//the root is always a group
+	for _, name := range root.names {
+		var err error
+		if root.fields[name], err = r.resolveMaps(root.fields[name], "$."+name); err != nil {
+			return nil, err
+		}
+	}
+	if len(root.names) == 0 {
+		return nil, fmt.Errorf("no fields in the JSON records")
+	}
+	return jsonSchemaItem(root, "parquet_go_root", "REQUIRED"), nil
+}
+
+//NewSchemaHandlerFromJSONSample creates the schema handler of the JSON schema inferred by InferJSONSchema
+func NewSchemaHandlerFromJSONSample(records []string, opts ...JSONInferOption) (*SchemaHandler, error) {
+	item, err := InferJSONSchema(records, opts...)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	return NewSchemaHandlerFromJSON(string(bs))
+}
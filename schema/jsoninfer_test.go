@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferJSONSchema(t *testing.T) {
+	records := []string{
+		`{"id": 1, "score": 2, "ok": true, "ts": "2021-03-04T05:06:07Z", "tags": ["a"], "child": {"name": "x"}, "attrs": {}}`,
+		`{"id": 2, "score": 2.5, "ok": null, "ts": "later", "tags": [], "child": {"age": 3}, "attrs": null, "extra": null}`,
+	}
+	item, err := InferJSONSchema(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &JSONSchemaItemType{
+		Tag: "name=parquet_go_root, repetitiontype=REQUIRED",
+		Fields: []*JSONSchemaItemType{
+			{Tag: "name=id, type=INT64, repetitiontype=OPTIONAL"},
+			{Tag: "name=score, type=DOUBLE, repetitiontype=OPTIONAL"},
+			{Tag: "name=ok, type=BOOLEAN, repetitiontype=OPTIONAL"},
+			{Tag: "name=ts, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+			{Tag: "name=tags, type=LIST, repetitiontype=OPTIONAL", Fields: []*JSONSchemaItemType{
+				{Tag: "name=element, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+			}},
+			{Tag: "name=child, repetitiontype=OPTIONAL", Fields: []*JSONSchemaItemType{
+				{Tag: "name=name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+				{Tag: "name=age, type=INT64, repetitiontype=OPTIONAL"},
+			}},
+			{Tag: "name=attrs, type=MAP, repetitiontype=OPTIONAL", Fields: []*JSONSchemaItemType{
+				{Tag: "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+				{Tag: "name=value, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+			}},
+			{Tag: "name=extra, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+		},
+	}
+	if !reflect.DeepEqual(item, expected) {
+		got, _ := json.Marshal(item)
+		t.Errorf("unexpected schema %s", got)
+	}
+
+	if _, err := NewSchemaHandlerFromJSONSample(records); err != nil {
+		t.Errorf("invalid inferred schema: %v", err)
+	}
+
+	//timestamps, maps by path and by threshold
+	records = []string{
+		`{"ts": "2021-03-04T05:06:07.5+02:00", "m": {"a": [1]}, "n": {"a": 1, "b": 2, "c": 3}}`,
+		`{"ts": null, "m": {"b": [2.5]}, "n": {"d": 4}}`,
+	}
+	item, err = InferJSONSchema(records, WithJSONMapPaths("$.m"), WithJSONMapThreshold(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, _ := json.Marshal(item)
+	for _, tag := range []string{
+		"name=ts, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MICROS, repetitiontype=OPTIONAL",
+		"name=m, type=MAP",
+		"name=element, type=DOUBLE",
+		"name=n, type=MAP",
+	} {
+		if !strings.Contains(string(bs), tag) {
+			t.Errorf("%s not in %s", tag, bs)
+		}
+	}
+
+	errRecords := [][]string{
+		{`{"a": {"b": [1]}}`, `{"a": {"b": 2}}`},
+		{`{"a": [{"b": 1}, [2]]}`},
+		{`[1]`},
+		{`{"a": `},
+	}
+	errs := []string{
+		"record 1: $.a.b: can't merge array and integer",
+		"record 0: $.a[]: can't merge object and array",
+		"record 0: expect object, get array",
+		"record 0: unexpected EOF",
+	}
+	for i, records := range errRecords {
+		if _, err := InferJSONSchema(records); err == nil || err.Error() != errs[i] {
+			t.Errorf("expected error %q, got %v", errs[i], err)
+		}
+	}
+}
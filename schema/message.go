@@ -0,0 +1,517 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//Delimiters of the message type tokens, the other characters are in the names
+const messageTypeDelimiters = " \t\r\n,;{}()="
+
+//Physical type names of the message type
+var messagePrimitiveTypes = map[string]parquet.Type{
+	"boolean":              parquet.Type_BOOLEAN,
+	"int32":                parquet.Type_INT32,
+	"int64":                parquet.Type_INT64,
+	"int96":                parquet.Type_INT96,
+	"float":                parquet.Type_FLOAT,
+	"double":               parquet.Type_DOUBLE,
+	"binary":               parquet.Type_BYTE_ARRAY,
+	"fixed_len_byte_array": parquet.Type_FIXED_LEN_BYTE_ARRAY,
+}
+
+type messageTypeParser struct {
+	tokens []string
+	pos    int
+	res    []*parquet.SchemaElement
+}
+
+/*
+ParseMessageType parses the schema in the message type format of parquet-mr and pyarrow, e.g.
+
+	message m {
+	  required int64 id = 1;
+	  optional binary name (STRING);
+	  optional group tags (LIST) {
+	    repeated group list {
+	      optional binary element (STRING);
+	    }
+	  }
+	}
+
+The annotations are logical types, e.g. TIMESTAMP(MILLIS,true), or converted types, e.g. UTF8.
+Both the logical and the converted types of the elements are set like parquet-mr.
+*/
+func ParseMessageType(text string) ([]*parquet.SchemaElement, error) {
+	p := &messageTypeParser{tokens: tokenizeMessageType(text)}
+	if err := p.expect("message"); err != nil {
+		return nil, err
+	}
+	root := parquet.NewSchemaElement()
+	root.Name = p.next()
+	rt := parquet.FieldRepetitionType_REQUIRED
+	root.RepetitionType = &rt
+	p.res = append(p.res, root)
+	if err := p.parseChildren(root); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q after the message", p.tokens[p.pos])
+	}
+	return p.res, nil
+}
+
+//NewSchemaHandlerFromMessageType creates a schema handler from the message type text, see ParseMessageType
+func NewSchemaHandlerFromMessageType(text string) (*SchemaHandler, error) {
+	elements, err := ParseMessageType(text)
+	if err != nil {
+		return nil, err
+	}
+	return NewSchemaHandlerFromSchemaList(elements), nil
+}
+
+func tokenizeMessageType(text string) []string {
+	var res []string
+	start := -1
+	for i, c := range text {
+		if strings.ContainsRune(messageTypeDelimiters, c) {
+			if start >= 0 {
+				res = append(res, text[start:i])
+				start = -1
+			}
+			if !strings.ContainsRune(" \t\r\n", c) {
+				res = append(res, string(c))
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		res = append(res, text[start:])
+	}
+	return res
+}
+
+//Next token, it's empty at the end
+func (p *messageTypeParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+func (p *messageTypeParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *messageTypeParser) expect(token string) error {
+	if t := p.next(); !strings.EqualFold(t, token) {
+		if t == "" {
+			return fmt.Errorf("expect %q, get the end of the message", token)
+		}
+		return fmt.Errorf("expect %q, get %q", token, t)
+	}
+	return nil
+}
+
+//Integer in parentheses, e.g. the length of fixed_len_byte_array(16)
+func (p *messageTypeParser) int32Token() (int32, error) {
+	t := p.next()
+	n, err := strconv.ParseInt(t, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("expect an integer, get %q", t)
+	}
+	return int32(n), nil
+}
+
+func (p *messageTypeParser) parseChildren(parent *parquet.SchemaElement) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	var numChildren int32
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			return fmt.Errorf("%v: expect \"}\", get the end of the message", parent.GetName())
+		}
+		if err := p.parseField(); err != nil {
+			return err
+		}
+		numChildren++
+	}
+	p.next()
+	parent.NumChildren = &numChildren
+	return nil
+}
+
+func (p *messageTypeParser) parseField() error {
+	se := parquet.NewSchemaElement()
+	p.res = append(p.res, se)
+
+	repetition := p.next()
+	rt, err := parquet.FieldRepetitionTypeFromString(strings.ToUpper(repetition))
+	if err != nil {
+		return fmt.Errorf("unknown repetition %q", repetition)
+	}
+	se.RepetitionType = &rt
+
+	typeName := strings.ToLower(p.next())
+	isGroup := typeName == "group"
+	if !isGroup {
+		t, ok := messagePrimitiveTypes[typeName]
+		if !ok {
+			return fmt.Errorf("unknown type %q", typeName)
+		}
+		se.Type = &t
+		if t == parquet.Type_FIXED_LEN_BYTE_ARRAY {
+			if err := p.expect("("); err != nil {
+				return err
+			}
+			length, err := p.int32Token()
+			if err != nil {
+				return err
+			}
+			se.TypeLength = &length
+			if err := p.expect(")"); err != nil {
+				return err
+			}
+		}
+	}
+
+	se.Name = p.next()
+	if se.Name == "" || strings.Contains(messageTypeDelimiters, se.Name) {
+		return fmt.Errorf("expect the name of the field, get %q", se.Name)
+	}
+	if p.peek() == "(" {
+		p.next()
+		if err := p.parseAnnotation(se); err != nil {
+			return fmt.Errorf("%v: %v", se.Name, err)
+		}
+	}
+	if p.peek() == "=" {
+		p.next()
+		fieldID, err := p.int32Token()
+		if err != nil {
+			return fmt.Errorf("%v: %v", se.Name, err)
+		}
+		se.FieldID = &fieldID
+	}
+
+	if isGroup {
+		return p.parseChildren(se)
+	}
+	if err := p.expect(";"); err != nil {
+		return fmt.Errorf("%v: %v", se.Name, err)
+	}
+	return nil
+}
+
+//Arguments of an annotation, e.g. MILLIS,true of TIMESTAMP(MILLIS,true)
+func (p *messageTypeParser) annotationArgs(n int) ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		args[i] = p.next()
+	}
+	return args, p.expect(")")
+}
+
+func newTimeUnit(unit string) (*parquet.TimeUnit, error) {
+	res := parquet.NewTimeUnit()
+	switch strings.ToUpper(unit) {
+	case "MILLIS":
+		res.MILLIS = parquet.NewMilliSeconds()
+	case "MICROS":
+		res.MICROS = parquet.NewMicroSeconds()
+	case "NANOS":
+		res.NANOS = parquet.NewNanoSeconds()
+	default:
+		return nil, fmt.Errorf("unknown time unit %q", unit)
+	}
+	return res, nil
+}
+
+//Parse the annotation after "(" and set the logical and converted types
+func (p *messageTypeParser) parseAnnotation(se *parquet.SchemaElement) error {
+	name := strings.ToUpper(p.next())
+	lt := parquet.NewLogicalType()
+	switch name {
+	case "STRING":
+		lt.STRING = parquet.NewStringType()
+	case "UUID":
+		lt.UUID = parquet.NewUUIDType()
+	case "UNKNOWN":
+		lt.UNKNOWN = parquet.NewNullType()
+	case "FLOAT16":
+		lt.FLOAT16 = parquet.NewFloat16Type()
+
+	case "DECIMAL":
+		args, err := p.annotationArgs(2)
+		if err != nil {
+			return err
+		}
+		precision, err := strconv.ParseInt(args[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid precision %q", args[0])
+		}
+		scale, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid scale %q", args[1])
+		}
+		p32, s32 := int32(precision), int32(scale)
+		lt.DECIMAL = &parquet.DecimalType{Precision: p32, Scale: s32}
+		se.Precision, se.Scale = &p32, &s32
+
+	case "TIME", "TIMESTAMP":
+		args, err := p.annotationArgs(2)
+		if err != nil {
+			return err
+		}
+		unit, err := newTimeUnit(args[0])
+		if err != nil {
+			return err
+		}
+		adjusted, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid isAdjustedToUTC %q", args[1])
+		}
+		if name == "TIME" {
+			lt.TIME = &parquet.TimeType{IsAdjustedToUTC: adjusted, Unit: unit}
+		} else {
+			lt.TIMESTAMP = &parquet.TimestampType{IsAdjustedToUTC: adjusted, Unit: unit}
+		}
+
+	case "INTEGER":
+		args, err := p.annotationArgs(2)
+		if err != nil {
+			return err
+		}
+		bitWidth, err := strconv.ParseInt(args[0], 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid bit width %q", args[0])
+		}
+		signed, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid isSigned %q", args[1])
+		}
+		lt.INTEGER = &parquet.IntType{BitWidth: int8(bitWidth), IsSigned: signed}
+
+	default:
+		//converted types, their logical types are set like the convertedtype tags,
+		//the legacy TIME_* and TIMESTAMP_* are adjusted to UTC
+		ct, err := parquet.ConvertedTypeFromString(name)
+		if err != nil {
+			return fmt.Errorf("unknown annotation %q", name)
+		}
+		se.ConvertedType = &ct
+		lt = common.NewLogicalTypeFromConvertedType(se, &common.Tag{IsAdjustedToUTC: true})
+	}
+	se.LogicalType = lt
+	if se.ConvertedType == nil {
+		se.ConvertedType = convertedTypeFromLogicalType(lt)
+	}
+	return p.expect(")")
+}
+
+//Converted type of the logical type like parquet-mr, it's nil if there isn't one
+func convertedTypeFromLogicalType(lt *parquet.LogicalType) *parquet.ConvertedType {
+	var ct parquet.ConvertedType
+	switch {
+	case lt == nil:
+		return nil
+	case lt.IsSetSTRING():
+		ct = parquet.ConvertedType_UTF8
+	case lt.IsSetMAP():
+		ct = parquet.ConvertedType_MAP
+	case lt.IsSetLIST():
+		ct = parquet.ConvertedType_LIST
+	case lt.IsSetENUM():
+		ct = parquet.ConvertedType_ENUM
+	case lt.IsSetDECIMAL():
+		ct = parquet.ConvertedType_DECIMAL
+	case lt.IsSetDATE():
+		ct = parquet.ConvertedType_DATE
+	case lt.IsSetJSON():
+		ct = parquet.ConvertedType_JSON
+	case lt.IsSetBSON():
+		ct = parquet.ConvertedType_BSON
+	case lt.IsSetTIME() && lt.TIME.Unit.IsSetMILLIS():
+		ct = parquet.ConvertedType_TIME_MILLIS
+	case lt.IsSetTIME() && lt.TIME.Unit.IsSetMICROS():
+		ct = parquet.ConvertedType_TIME_MICROS
+	case lt.IsSetTIMESTAMP() && lt.TIMESTAMP.Unit.IsSetMILLIS():
+		ct = parquet.ConvertedType_TIMESTAMP_MILLIS
+	case lt.IsSetTIMESTAMP() && lt.TIMESTAMP.Unit.IsSetMICROS():
+		ct = parquet.ConvertedType_TIMESTAMP_MICROS
+	case lt.IsSetINTEGER():
+		name := fmt.Sprintf("INT_%d", lt.INTEGER.BitWidth)
+		if !lt.INTEGER.IsSigned {
+			name = "U" + name
+		}
+		var err error
+		if ct, err = parquet.ConvertedTypeFromString(name); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return &ct
+}
+
+//FormatMessageType prints the schema elements in the message type format, see ParseMessageType
+func FormatMessageType(elements []*parquet.SchemaElement) (string, error) {
+	return formatMessageType(elements, func(i int) string { return elements[i].GetName() })
+}
+
+//MessageTypeString prints the schema with the ExNames in the message type format, see ParseMessageType
+func (sh *SchemaHandler) MessageTypeString() (string, error) {
+	return formatMessageType(sh.SchemaElements, sh.GetExName)
+}
+
+type messageTypePrinter struct {
+	elements       []*parquet.SchemaElement
+	name           func(int) string
+	buf            strings.Builder
+	pos            int
+	legacyFieldIDs bool
+}
+
+func formatMessageType(elements []*parquet.SchemaElement, name func(int) string) (string, error) {
+	if len(elements) == 0 {
+		return "", fmt.Errorf("empty schema")
+	}
+	p := &messageTypePrinter{elements: elements, name: name, pos: 1, legacyFieldIDs: LegacyFieldIDs(elements)}
+	rootName, err := p.checkName(0)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&p.buf, "message %s {\n", rootName)
+	if err := p.printChildren(elements[0], "  "); err != nil {
+		return "", err
+	}
+	p.buf.WriteString("}\n")
+	if p.pos != len(elements) {
+		return "", fmt.Errorf("%d schema elements aren't in the tree", len(elements)-p.pos)
+	}
+	return p.buf.String(), nil
+}
+
+func (p *messageTypePrinter) checkName(i int) (string, error) {
+	name := p.name(i)
+	if name == "" || strings.ContainsAny(name, messageTypeDelimiters) {
+		return "", fmt.Errorf("name %q can't be in the message type", name)
+	}
+	return name, nil
+}
+
+func (p *messageTypePrinter) printChildren(parent *parquet.SchemaElement, indent string) error {
+	for i := int32(0); i < parent.GetNumChildren(); i++ {
+		if p.pos >= len(p.elements) {
+			return fmt.Errorf("%v: expect %d children, get %d", parent.GetName(), parent.GetNumChildren(), i)
+		}
+		idx := p.pos
+		se := p.elements[idx]
+		p.pos++
+		name, err := p.checkName(idx)
+		if err != nil {
+			return err
+		}
+
+		p.buf.WriteString(indent + strings.ToLower(se.GetRepetitionType().String()) + " ")
+		if se.GetNumChildren() > 0 || se.Type == nil {
+			p.buf.WriteString("group ")
+		} else if se.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY {
+			fmt.Fprintf(&p.buf, "fixed_len_byte_array(%d) ", se.GetTypeLength())
+		} else if se.GetType() == parquet.Type_BYTE_ARRAY {
+			p.buf.WriteString("binary ")
+		} else {
+			p.buf.WriteString(strings.ToLower(se.GetType().String()) + " ")
+		}
+		p.buf.WriteString(name)
+		if annotation := messageTypeAnnotation(se); annotation != "" {
+			p.buf.WriteString(" (" + annotation + ")")
+		}
+		if se.IsSetFieldID() && !p.legacyFieldIDs {
+			fmt.Fprintf(&p.buf, " = %d", se.GetFieldID())
+		}
+
+		if se.GetNumChildren() > 0 || se.Type == nil {
+			p.buf.WriteString(" {\n")
+			if err := p.printChildren(se, indent+"  "); err != nil {
+				return err
+			}
+			p.buf.WriteString(indent + "}\n")
+		} else {
+			p.buf.WriteString(";\n")
+		}
+	}
+	return nil
+}
+
+//Annotation of the element, the logical type or the converted type of the old files
+func messageTypeAnnotation(se *parquet.SchemaElement) string {
+	lt := se.LogicalType
+	switch {
+	case lt == nil:
+	case lt.IsSetSTRING():
+		return "STRING"
+	case lt.IsSetMAP():
+		return "MAP"
+	case lt.IsSetLIST():
+		return "LIST"
+	case lt.IsSetENUM():
+		return "ENUM"
+	case lt.IsSetDECIMAL():
+		return fmt.Sprintf("DECIMAL(%d,%d)", lt.DECIMAL.Precision, lt.DECIMAL.Scale)
+	case lt.IsSetDATE():
+		return "DATE"
+	case lt.IsSetTIME():
+		return fmt.Sprintf("TIME(%s,%v)", messageTypeTimeUnit(lt.TIME.Unit), lt.TIME.IsAdjustedToUTC)
+	case lt.IsSetTIMESTAMP():
+		return fmt.Sprintf("TIMESTAMP(%s,%v)", messageTypeTimeUnit(lt.TIMESTAMP.Unit), lt.TIMESTAMP.IsAdjustedToUTC)
+	case lt.IsSetINTEGER():
+		return fmt.Sprintf("INTEGER(%d,%v)", lt.INTEGER.BitWidth, lt.INTEGER.IsSigned)
+	case lt.IsSetUNKNOWN():
+		return "UNKNOWN"
+	case lt.IsSetJSON():
+		return "JSON"
+	case lt.IsSetBSON():
+		return "BSON"
+	case lt.IsSetUUID():
+		return "UUID"
+	case lt.IsSetFLOAT16():
+		return "FLOAT16"
+	}
+
+	if !se.IsSetConvertedType() {
+		return ""
+	}
+	if se.GetConvertedType() == parquet.ConvertedType_DECIMAL {
+		return fmt.Sprintf("DECIMAL(%d,%d)", se.GetPrecision(), se.GetScale())
+	}
+	return se.GetConvertedType().String()
+}
+
+func messageTypeTimeUnit(unit *parquet.TimeUnit) string {
+	switch {
+	case unit.IsSetMILLIS():
+		return "MILLIS"
+	case unit.IsSetMICROS():
+		return "MICROS"
+	}
+	return "NANOS"
+}
@@ -0,0 +1,174 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+const testMessageType = `message parquet_go_root {
+  required int64 id = 1;
+  optional binary name (STRING) = 2;
+  required int32 age (INTEGER(8,true));
+  required int64 uage (INTEGER(64,false));
+  optional int64 created (TIMESTAMP(MILLIS,true));
+  optional int64 local (TIMESTAMP(NANOS,false));
+  optional int32 t (TIME(MILLIS,false));
+  required int32 birthday (DATE);
+  required fixed_len_byte_array(16) uid (UUID);
+  required binary balance (DECIMAL(9,2));
+  required fixed_len_byte_array(12) interval (INTERVAL);
+  required int96 legacy;
+  optional binary raw;
+  optional binary doc (JSON);
+  optional binary color (ENUM);
+  optional int32 nothing (UNKNOWN);
+  required boolean ok;
+  required float f;
+  required double d;
+  optional group tags (LIST) = 3 {
+    repeated group list {
+      optional binary element (STRING);
+    }
+  }
+  optional group scores (MAP) {
+    repeated group key_value {
+      required binary key (STRING);
+      optional double value;
+    }
+  }
+  repeated group friends {
+    required binary name (STRING);
+  }
+}
+`
+
+func TestMessageType(t *testing.T) {
+	elements, err := ParseMessageType(testMessageType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := FormatMessageType(elements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != testMessageType {
+		t.Errorf("expect\n%v\nget\n%v", testMessageType, res)
+	}
+
+	element := func(name string) *parquet.SchemaElement {
+		for _, se := range elements {
+			if se.GetName() == name {
+				return se
+			}
+		}
+		t.Fatalf("%v not found", name)
+		return nil
+	}
+	if se := element("id"); se.GetFieldID() != 1 || se.GetRepetitionType() != parquet.FieldRepetitionType_REQUIRED {
+		t.Errorf("id: %v", se)
+	}
+	if se := element("name"); se.GetConvertedType() != parquet.ConvertedType_UTF8 || !se.LogicalType.IsSetSTRING() {
+		t.Errorf("name: %v", se)
+	}
+	if se := element("age"); se.GetConvertedType() != parquet.ConvertedType_INT_8 {
+		t.Errorf("age: %v", se)
+	}
+	if se := element("local"); se.IsSetConvertedType() || !se.LogicalType.TIMESTAMP.Unit.IsSetNANOS() {
+		t.Errorf("local: %v", se)
+	}
+	if se := element("balance"); se.GetPrecision() != 9 || se.GetScale() != 2 || se.GetConvertedType() != parquet.ConvertedType_DECIMAL {
+		t.Errorf("balance: %v", se)
+	}
+	if se := element("interval"); se.GetConvertedType() != parquet.ConvertedType_INTERVAL || se.LogicalType != nil || se.GetTypeLength() != 12 {
+		t.Errorf("interval: %v", se)
+	}
+	if se := element("tags"); se.GetNumChildren() != 1 || se.GetFieldID() != 3 || se.GetConvertedType() != parquet.ConvertedType_LIST {
+		t.Errorf("tags: %v", se)
+	}
+
+	//the legacy converted types are adjusted to UTC
+	legacy, err := ParseMessageType(`message m { optional int64 a (TIMESTAMP_MILLIS); optional int32 b (TIME_MILLIS); }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lt := legacy[1].LogicalType; !lt.IsSetTIMESTAMP() || !lt.TIMESTAMP.IsAdjustedToUTC || !lt.TIMESTAMP.Unit.IsSetMILLIS() {
+		t.Errorf("a: %v", legacy[1])
+	}
+	if lt := legacy[2].LogicalType; !lt.IsSetTIME() || !lt.TIME.IsAdjustedToUTC {
+		t.Errorf("b: %v", legacy[2])
+	}
+
+	for _, text := range []string{
+		`message m { required int32 a }`,
+		`message m { required int33 a; }`,
+		`message m { requird int32 a; }`,
+		`message m { required int32 a (TIMESTAMP(SECONDS,true)); }`,
+		`message m { required int32 a (FOO); }`,
+		`message m { required group a { required int32 b; }`,
+		`message m { required int32 a = x; }`,
+		`message m { } }`,
+	} {
+		if _, err := ParseMessageType(text); err == nil {
+			t.Errorf("%v: expect error", text)
+		}
+	}
+}
+
+func TestMessageTypeString(t *testing.T) {
+	type Student struct {
+		Name    string            `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=1"`
+		Age     *int32            `parquet:"name=age, type=INT32, convertedtype=INT_16, fieldid=0"`
+		Day     int32             `parquet:"name=day, type=INT32, convertedtype=DATE"`
+		Ts      int64             `parquet:"name=ts, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+		Price   int64             `parquet:"name=price, type=INT64, convertedtype=DECIMAL, scale=2, precision=18"`
+		Tags    []string          `parquet:"name=tags, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+		Scores  map[string]int32  `parquet:"name=scores, type=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=INT32"`
+		Friends []string          `parquet:"name=friends, type=BYTE_ARRAY, repetitiontype=REPEATED"`
+		Extra   map[string]string `parquet:"name=extra, type=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	}
+	sh, err := NewSchemaHandlerFromStruct(new(Student))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := sh.MessageTypeString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sh2, err := NewSchemaHandlerFromMessageType(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sh.SchemaElements) != len(sh2.SchemaElements) {
+		t.Fatalf("expect %v elements, get %v", len(sh.SchemaElements), len(sh2.SchemaElements))
+	}
+	for i, se := range sh.SchemaElements {
+		se2 := sh2.SchemaElements[i]
+		if se2.GetName() != sh.GetExName(i) || se2.GetType() != se.GetType() || se2.GetRepetitionType() != se.GetRepetitionType() ||
+			se2.GetNumChildren() != se.GetNumChildren() || se2.GetConvertedType() != se.GetConvertedType() || se2.GetFieldID() != se.GetFieldID() || se2.IsSetFieldID() != se.IsSetFieldID() ||
+			se2.GetScale() != se.GetScale() || se2.GetPrecision() != se.GetPrecision() {
+			t.Errorf("expect %v, get %v", se, se2)
+		}
+		if se.LogicalType != nil && !reflect.DeepEqual(se.LogicalType, se2.LogicalType) {
+			t.Errorf("%v: expect %v, get %v", se.GetName(), se.LogicalType, se2.LogicalType)
+		}
+	}
+	text2, err := sh2.MessageTypeString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != text2 {
+		t.Errorf("expect\n%v\nget\n%v", text, text2)
+	}
+
+	//all the field_ids are 0 in the files of older versions, they aren't printed
+	for _, se := range sh.SchemaElements {
+		fieldID := int32(0)
+		se.FieldID = &fieldID
+	}
+	if text, err = sh.MessageTypeString(); err != nil || strings.Contains(text, " = ") {
+		t.Errorf("expect no field_ids, get %v %v", text, err)
+	}
+}
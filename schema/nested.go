@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//Get the index after the subtree of the schema element at idx
+func (sh *SchemaHandler) subtreeEnd(idx int32) int32 {
+	pos := idx + 1
+	for i := int32(0); i < sh.SchemaElements[idx].GetNumChildren() && pos < int32(len(sh.SchemaElements)); i++ {
+		pos = sh.subtreeEnd(pos)
+	}
+	return pos
+}
+
+//ChildrenIndex returns the indexes of the children of the schema element at idx
+func (sh *SchemaHandler) ChildrenIndex(idx int32) []int32 {
+	nc := sh.SchemaElements[idx].GetNumChildren()
+	res := make([]int32, 0, nc)
+	pos := idx + 1
+	for i := int32(0); i < nc && pos < int32(len(sh.SchemaElements)); i++ {
+		res = append(res, pos)
+		pos = sh.subtreeEnd(pos)
+	}
+	return res
+}
+
+func isListElement(se *parquet.SchemaElement) bool {
+	return se.GetConvertedType() == parquet.ConvertedType_LIST ||
+		(se.IsSetLogicalType() && se.LogicalType.IsSetLIST())
+}
+
+func isMapElement(se *parquet.SchemaElement) bool {
+	return se.GetConvertedType() == parquet.ConvertedType_MAP ||
+		se.GetConvertedType() == parquet.ConvertedType_MAP_KEY_VALUE ||
+		(se.IsSetLogicalType() && se.LogicalType.IsSetMAP())
+}
+
+/*
+ListElementIndex returns the index of the element of the LIST annotated group at idx
+and the number of levels of the list. It follows the backward compatibility rules of the parquet format:
+the repeated field is the element (2-level list) if it's primitive, or a group with more than one field,
+or a group named "array" or "<list name>_tuple" with one field. Otherwise its only field is the
+element (3-level list), whatever the names are, e.g. list/element, bag/array_element.
+levels is 0 if idx isn't a list.
+*/
+func (sh *SchemaHandler) ListElementIndex(idx int32) (elementIdx int32, levels int) {
+	se := sh.SchemaElements[idx]
+	if !isListElement(se) || se.GetNumChildren() != 1 || int(idx)+1 >= len(sh.SchemaElements) {
+		return -1, 0
+	}
+
+	repeatedIdx := idx + 1
+	repeated := sh.SchemaElements[repeatedIdx]
+	if repeated.GetRepetitionType() != parquet.FieldRepetitionType_REPEATED {
+		return -1, 0
+	}
+	nc := repeated.GetNumChildren()
+	if nc != 1 || repeated.GetName() == "array" || repeated.GetName() == se.GetName()+"_tuple" {
+		return repeatedIdx, 2
+	}
+	return repeatedIdx + 1, 3
+}
+
+/*
+MapKeyValueIndex returns the indexes of the key and the value of the MAP (or MAP_KEY_VALUE) annotated group at idx.
+The map has one repeated group with the key and the value fields. Their names are ignored,
+the first field is the key. ok is false if idx isn't a map.
+*/
+func (sh *SchemaHandler) MapKeyValueIndex(idx int32) (keyIdx int32, valueIdx int32, ok bool) {
+	se := sh.SchemaElements[idx]
+	if !isMapElement(se) || se.GetNumChildren() != 1 || int(idx)+1 >= len(sh.SchemaElements) {
+		return -1, -1, false
+	}
+
+	keyValueIdx := idx + 1
+	keyValue := sh.SchemaElements[keyValueIdx]
+	if keyValue.GetRepetitionType() != parquet.FieldRepetitionType_REPEATED || keyValue.GetNumChildren() != 2 {
+		return -1, -1, false
+	}
+	children := sh.ChildrenIndex(keyValueIdx)
+	if len(children) != 2 {
+		return -1, -1, false
+	}
+	return children[0], children[1], true
+}
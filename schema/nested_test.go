@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+func newGroup(name string, rT parquet.FieldRepetitionType, cT *parquet.ConvertedType, numChildren int32) *parquet.SchemaElement {
+	se := parquet.NewSchemaElement()
+	se.Name = name
+	se.RepetitionType = &rT
+	se.ConvertedType = cT
+	se.NumChildren = &numChildren
+	return se
+}
+
+func newLeaf(name string, rT parquet.FieldRepetitionType, pT parquet.Type) *parquet.SchemaElement {
+	se := parquet.NewSchemaElement()
+	se.Name = name
+	se.RepetitionType = &rT
+	se.Type = &pT
+	return se
+}
+
+func TestLegacyNestedTypes(t *testing.T) {
+	list, mp, mapKeyValue := parquet.ConvertedTypePtr(parquet.ConvertedType_LIST), parquet.ConvertedTypePtr(parquet.ConvertedType_MAP), parquet.ConvertedTypePtr(parquet.ConvertedType_MAP_KEY_VALUE)
+	required, optional, repeated := parquet.FieldRepetitionType_REQUIRED, parquet.FieldRepetitionType_OPTIONAL, parquet.FieldRepetitionType_REPEATED
+
+	testData := []struct {
+		name     string
+		elements []*parquet.SchemaElement
+		levels   int
+		expected reflect.Type
+	}{
+		{"standard list", []*parquet.SchemaElement{
+			newGroup("a", optional, list, 1),
+			newGroup("list", repeated, nil, 1),
+			newLeaf("element", optional, parquet.Type_INT32),
+		}, 3, reflect.TypeOf([]*int32{})},
+
+		{"2-level primitive", []*parquet.SchemaElement{
+			newGroup("a", optional, list, 1),
+			newLeaf("array", repeated, parquet.Type_INT32),
+		}, 2, reflect.TypeOf([]int32{})},
+
+		{"avro array", []*parquet.SchemaElement{
+			newGroup("a", optional, list, 1),
+			newGroup("array", repeated, nil, 1),
+			newLeaf("str", required, parquet.Type_BYTE_ARRAY),
+		}, 2, reflect.TypeOf([]struct{ Str string }{})},
+
+		{"thrift tuple", []*parquet.SchemaElement{
+			newGroup("a", optional, list, 1),
+			newGroup("a_tuple", repeated, nil, 1),
+			newLeaf("str", required, parquet.Type_BYTE_ARRAY),
+		}, 2, reflect.TypeOf([]struct{ Str string }{})},
+
+		{"2-level group", []*parquet.SchemaElement{
+			newGroup("a", optional, list, 1),
+			newGroup("item", repeated, nil, 2),
+			newLeaf("str", required, parquet.Type_BYTE_ARRAY),
+			newLeaf("num", optional, parquet.Type_INT64),
+		}, 2, reflect.TypeOf([]struct {
+			Str string
+			Num *int64
+		}{})},
+
+		{"spark bag", []*parquet.SchemaElement{
+			newGroup("a", optional, list, 1),
+			newGroup("bag", repeated, nil, 1),
+			newLeaf("array_element", optional, parquet.Type_INT64),
+		}, 3, reflect.TypeOf([]*int64{})},
+
+		{"map key value", []*parquet.SchemaElement{
+			newGroup("a", optional, mp, 1),
+			newGroup("map", repeated, mapKeyValue, 2),
+			newLeaf("k", required, parquet.Type_BYTE_ARRAY),
+			newLeaf("v", optional, parquet.Type_INT32),
+		}, 0, reflect.TypeOf(map[string]*int32{})},
+
+		{"legacy map annotation", []*parquet.SchemaElement{
+			newGroup("a", optional, mapKeyValue, 1),
+			newGroup("map", repeated, nil, 2),
+			newLeaf("key", required, parquet.Type_INT32),
+			newLeaf("value", required, parquet.Type_DOUBLE),
+		}, 0, reflect.TypeOf(map[int32]float64{})},
+
+		{"not a list", []*parquet.SchemaElement{
+			newGroup("a", optional, list, 1),
+			newLeaf("b", optional, parquet.Type_INT32),
+		}, 0, reflect.TypeOf(&struct{ B *int32 }{})},
+	}
+
+	for _, data := range testData {
+		sh := NewSchemaHandlerFromSchemaList(append([]*parquet.SchemaElement{newGroup("root", required, nil, 1)}, data.elements...))
+		if _, levels := sh.ListElementIndex(1); levels != data.levels {
+			t.Errorf("%v: ListElementIndex err, expect %v, get %v", data.name, data.levels, levels)
+		}
+		if res := sh.GetTypes()[1]; res != data.expected {
+			t.Errorf("%v: GetTypes err, expect %v, get %v", data.name, data.expected, res)
+		}
+	}
+}
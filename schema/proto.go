@@ -0,0 +1,161 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+//ProtoTimestampName is the full name of the well-known Timestamp message, it's an INT64 TIMESTAMP(NANOS)
+const ProtoTimestampName protoreflect.FullName = "google.protobuf.Timestamp"
+
+//Converter of a protobuf message descriptor to schema elements
+type protoSchemaBuilder struct {
+	res []*parquet.SchemaElement
+	//messages being converted, the recursive messages can't be converted
+	visiting map[protoreflect.FullName]bool
+}
+
+/*
+NewSchemaHandlerFromProto creates a schema handler from a protobuf message descriptor like parquet-protobuf:
+repeated fields are LISTs of REQUIRED elements, map fields are MAPs, the fields with presence (messages, oneofs
+and optional fields) are OPTIONAL, the others are REQUIRED. The field numbers are the field IDs.
+Enums are ENUM strings of the value names, unsigned integers are UINT_32/UINT_64 and the well-known
+google.protobuf.Timestamp is an INT64 TIMESTAMP(NANOS, true).
+*/
+func NewSchemaHandlerFromProto(md protoreflect.MessageDescriptor) (*SchemaHandler, error) {
+	b := &protoSchemaBuilder{visiting: make(map[protoreflect.FullName]bool)}
+	root := parquet.NewSchemaElement()
+	root.Name = "parquet_go_root"
+	rt := parquet.FieldRepetitionType_REQUIRED
+	root.RepetitionType = &rt
+	b.res = append(b.res, root)
+	if err := b.message(root, md); err != nil {
+		return nil, err
+	}
+	return NewSchemaHandlerFromSchemaList(b.res), nil
+}
+
+func newProtoSchemaElement(name string, rt parquet.FieldRepetitionType) *parquet.SchemaElement {
+	se := parquet.NewSchemaElement()
+	se.Name = name
+	se.RepetitionType = &rt
+	return se
+}
+
+//Children of the group se are the fields of the message
+func (b *protoSchemaBuilder) message(se *parquet.SchemaElement, md protoreflect.MessageDescriptor) error {
+	if b.visiting[md.FullName()] {
+		return fmt.Errorf("recursive message %v isn't supported", md.FullName())
+	}
+	b.visiting[md.FullName()] = true
+	defer delete(b.visiting, md.FullName())
+
+	fields := md.Fields()
+	if fields.Len() == 0 {
+		return fmt.Errorf("message %v without fields", md.FullName())
+	}
+	numChildren := int32(fields.Len())
+	se.NumChildren = &numChildren
+	for i := 0; i < fields.Len(); i++ {
+		if err := b.field(fields.Get(i)); err != nil {
+			return fmt.Errorf("%v: %v", md.FullName(), err)
+		}
+	}
+	return nil
+}
+
+func (b *protoSchemaBuilder) field(fd protoreflect.FieldDescriptor) error {
+	name := string(fd.Name())
+	fieldID := int32(fd.Number())
+
+	if fd.IsList() {
+		se := newProtoSchemaElement(name, parquet.FieldRepetitionType_REQUIRED)
+		se.FieldID = &fieldID
+		b.group(se, 1, parquet.ConvertedType_LIST)
+		b.group(newProtoSchemaElement("list", parquet.FieldRepetitionType_REPEATED), 1, -1)
+		return b.value(newProtoSchemaElement("element", parquet.FieldRepetitionType_REQUIRED), fd)
+	}
+
+	if fd.IsMap() {
+		se := newProtoSchemaElement(name, parquet.FieldRepetitionType_REQUIRED)
+		se.FieldID = &fieldID
+		b.group(se, 1, parquet.ConvertedType_MAP)
+		b.group(newProtoSchemaElement("key_value", parquet.FieldRepetitionType_REPEATED), 2, parquet.ConvertedType_MAP_KEY_VALUE)
+		if err := b.value(newProtoSchemaElement("key", parquet.FieldRepetitionType_REQUIRED), fd.MapKey()); err != nil {
+			return err
+		}
+		return b.value(newProtoSchemaElement("value", parquet.FieldRepetitionType_REQUIRED), fd.MapValue())
+	}
+
+	rt := parquet.FieldRepetitionType_REQUIRED
+	if fd.HasPresence() && fd.Cardinality() != protoreflect.Required {
+		rt = parquet.FieldRepetitionType_OPTIONAL
+	}
+	se := newProtoSchemaElement(name, rt)
+	se.FieldID = &fieldID
+	return b.value(se, fd)
+}
+
+//Append a group element, ct < 0 is no converted type
+func (b *protoSchemaBuilder) group(se *parquet.SchemaElement, numChildren int32, ct parquet.ConvertedType) {
+	se.NumChildren = &numChildren
+	if ct >= 0 {
+		se.ConvertedType = &ct
+		se.LogicalType = common.NewLogicalTypeFromConvertedType(se, &common.Tag{})
+	}
+	b.res = append(b.res, se)
+}
+
+//Append the element of the value of fd: a primitive or a group of the message
+func (b *protoSchemaBuilder) value(se *parquet.SchemaElement, fd protoreflect.FieldDescriptor) error {
+	b.res = append(b.res, se)
+	setType := func(t parquet.Type, ct parquet.ConvertedType) {
+		se.Type = &t
+		if ct >= 0 {
+			se.ConvertedType = &ct
+			se.LogicalType = common.NewLogicalTypeFromConvertedType(se, &common.Tag{})
+		}
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		setType(parquet.Type_BOOLEAN, -1)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		setType(parquet.Type_INT32, -1)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		setType(parquet.Type_INT32, parquet.ConvertedType_UINT_32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		setType(parquet.Type_INT64, -1)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		setType(parquet.Type_INT64, parquet.ConvertedType_UINT_64)
+	case protoreflect.FloatKind:
+		setType(parquet.Type_FLOAT, -1)
+	case protoreflect.DoubleKind:
+		setType(parquet.Type_DOUBLE, -1)
+	case protoreflect.StringKind:
+		setType(parquet.Type_BYTE_ARRAY, parquet.ConvertedType_UTF8)
+	case protoreflect.BytesKind:
+		setType(parquet.Type_BYTE_ARRAY, -1)
+	case protoreflect.EnumKind:
+		setType(parquet.Type_BYTE_ARRAY, parquet.ConvertedType_ENUM)
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		md := fd.Message()
+		if md.FullName() == ProtoTimestampName {
+			setType(parquet.Type_INT64, -1)
+			se.LogicalType = &parquet.LogicalType{TIMESTAMP: &parquet.TimestampType{
+				IsAdjustedToUTC: true,
+				Unit:            &parquet.TimeUnit{NANOS: parquet.NewNanoSeconds()},
+			}}
+			return nil
+		}
+		return b.message(se, md)
+
+	default:
+		return fmt.Errorf("field %v: unknown kind %v", fd.Name(), fd.Kind())
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	//register google/protobuf/timestamp.proto
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestNewSchemaHandlerFromProto(t *testing.T) {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(number), Type: typ.Enum(), Label: label.Enum(), JsonName: proto.String(name)}
+	}
+	optional, repeated := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	created := field("created", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional)
+	created.TypeName = proto.String(".google.protobuf.Timestamp")
+	tags := field("tags", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated)
+	scores := field("scores", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated)
+	scores.TypeName = proto.String(".test.User.ScoresEntry")
+	email := field("email", 7, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional)
+	email.OneofIndex = proto.Int32(0)
+	entryKey := field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional)
+	entryValue := field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, optional)
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional),
+				field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional),
+				field("count", 3, descriptorpb.FieldDescriptorProto_TYPE_UINT32, optional),
+				created, tags, scores, email,
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name:    proto.String("ScoresEntry"),
+				Field:   []*descriptorpb.FieldDescriptorProto{entryKey, entryValue},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("contact")}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sh, err := NewSchemaHandlerFromProto(fd.Messages().ByName("User"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := sh.MessageTypeString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `message parquet_go_root {
+  required int64 id = 1;
+  required binary name (STRING) = 2;
+  required int32 count (INTEGER(32,false)) = 3;
+  optional int64 created (TIMESTAMP(NANOS,true)) = 4;
+  required group tags (LIST) = 5 {
+    repeated group list {
+      required binary element (STRING);
+    }
+  }
+  required group scores (MAP) = 6 {
+    repeated group key_value (MAP_KEY_VALUE) {
+      required binary key (STRING);
+      required double value;
+    }
+  }
+  optional binary email (STRING) = 7;
+}
+`
+	if text != expected {
+		t.Errorf("expect\n%v\nget\n%v", expected, text)
+	}
+	if se := sh.SchemaElements[sh.MapIndex["Parquet_go_root\x01Tags"]]; se.GetFieldID() != 5 || se.GetConvertedType() != parquet.ConvertedType_LIST {
+		t.Errorf("tags: %v", se)
+	}
+
+	//recursive messages can't be converted
+	if _, err := NewSchemaHandlerFromProto((&descriptorpb.DescriptorProto{}).ProtoReflect().Descriptor()); err == nil {
+		t.Errorf("expect error of recursive message")
+	}
+	if _, err := NewSchemaHandlerFromProto((&durationpb.Duration{}).ProtoReflect().Descriptor()); err != nil {
+		t.Error(err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/xitongsys/parquet-go/common"
 	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/types"
 )
 
 /*
@@ -57,6 +58,9 @@ type SchemaHandler struct {
 	ExPathToInPath map[string]string
 
 	ValueColumns []string
+
+	//Avro schema (.avsc) the handler is created from by NewSchemaHandlerFromAvro
+	AvroSchema string
 }
 
 // setValueColumns collects leaf nodes' full path in SchemaHandler.ValueColumns
@@ -200,6 +204,43 @@ func (sh *SchemaHandler) ConvertToInPathStr(pathStr string) (string, error) {
 	return "", fmt.Errorf("can't find path %v", pathStr)
 }
 
+// LegacyFieldIDs reports whether the elements have the field_id 0 that older versions of this library
+// wrote on all the elements, including the root. They aren't field_ids.
+func LegacyFieldIDs(elements []*parquet.SchemaElement) bool {
+	for _, se := range elements {
+		if !se.IsSetFieldID() || se.GetFieldID() != 0 {
+			return false
+		}
+	}
+	return len(elements) > 0
+}
+
+// HasFieldID reports whether the element at idx has a field_id, 0 is a valid field_id, see LegacyFieldIDs
+func (sh *SchemaHandler) HasFieldID(idx int32) bool {
+	return sh.SchemaElements[idx].IsSetFieldID() && !LegacyFieldIDs(sh.SchemaElements)
+}
+
+// Get the internal path of the field with the field_id
+func (sh *SchemaHandler) GetPathByFieldID(fieldID int32) (string, error) {
+	for idx, se := range sh.SchemaElements {
+		if idx > 0 && sh.HasFieldID(int32(idx)) && se.GetFieldID() == fieldID {
+			return sh.IndexMap[int32(idx)], nil
+		}
+	}
+	return "", fmt.Errorf("can't find field_id %v", fieldID)
+}
+
+// Get the field_id of every field with a field_id, keyed by internal path
+func (sh *SchemaHandler) GetFieldIDs() map[string]int32 {
+	res := make(map[string]int32)
+	for idx, se := range sh.SchemaElements {
+		if idx > 0 && sh.HasFieldID(int32(idx)) {
+			res[sh.IndexMap[int32(idx)]] = se.GetFieldID()
+		}
+	}
+	return res
+}
+
 // Get root name from the schema handler
 func (sh *SchemaHandler) GetRootInName() string {
 	if len(sh.SchemaElements) <= 0 {
@@ -259,9 +300,16 @@ func NewSchemaHandlerFromStruct(obj interface{}) (sh *SchemaHandler, err error)
 		stack = stack[:ln-1]
 		var newInfo *common.Tag
 
-		if item.GoType.Kind() == reflect.Struct {
+		//native types are stored in one column whatever their kind is
+		kind := item.GoType.Kind()
+		if types.IsNativeType(item.GoType) {
+			kind = reflect.Invalid
+		}
+
+		if kind == reflect.Struct {
 			schema := parquet.NewSchemaElement()
 			schema.Name = item.Info.InName
+			common.SetFieldID(schema, item.Info)
 			schema.RepetitionType = &item.Info.RepetitionType
 			numField := int32(item.GoType.NumField())
 			schema.NumChildren = &numField
@@ -294,10 +342,11 @@ func NewSchemaHandlerFromStruct(obj interface{}) (sh *SchemaHandler, err error)
 				}
 				stack = append(stack, newItem)
 			}
-		} else if item.GoType.Kind() == reflect.Slice &&
+		} else if kind == reflect.Slice &&
 			item.Info.RepetitionType != parquet.FieldRepetitionType_REPEATED {
 			schema := parquet.NewSchemaElement()
 			schema.Name = item.Info.InName
+			common.SetFieldID(schema, item.Info)
 			rt1 := item.Info.RepetitionType
 			schema.RepetitionType = &rt1
 			var numField int32 = 1
@@ -333,16 +382,17 @@ func NewSchemaHandlerFromStruct(obj interface{}) (sh *SchemaHandler, err error)
 			}
 			stack = append(stack, newItem)
 
-		} else if item.GoType.Kind() == reflect.Slice &&
+		} else if kind == reflect.Slice &&
 			item.Info.RepetitionType == parquet.FieldRepetitionType_REPEATED {
 			newItem := NewItem()
 			newItem.Info = item.Info
 			newItem.GoType = item.GoType.Elem()
 			stack = append(stack, newItem)
 
-		} else if item.GoType.Kind() == reflect.Map {
+		} else if kind == reflect.Map {
 			schema := parquet.NewSchemaElement()
 			schema.Name = item.Info.InName
+			common.SetFieldID(schema, item.Info)
 			rt1 := item.Info.RepetitionType
 			schema.RepetitionType = &rt1
 			var numField1 int32 = 1
@@ -388,7 +438,7 @@ func NewSchemaHandlerFromStruct(obj interface{}) (sh *SchemaHandler, err error)
 		} else {
 			schema, err := common.NewSchemaElementFromTagMap(item.Info)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create schema from tag map: %s", err.Error())
+				return nil, fmt.Errorf("failed to create schema from tag map of %s: %s", item.Info.ExName, err.Error())
 			}
 			schemaElements = append(schemaElements, schema)
 			newInfo = common.NewTag()
@@ -400,6 +450,9 @@ func NewSchemaHandlerFromStruct(obj interface{}) (sh *SchemaHandler, err error)
 	res := NewSchemaHandlerFromSchemaList(schemaElements)
 	res.Infos = infos
 	res.CreateInExMap()
+	if err := res.Validate(); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
@@ -410,6 +463,7 @@ func NewSchemaHandlerFromSchemaHandler(sh *SchemaHandler) *SchemaHandler {
 	schemaHandler.InPathToExPath = make(map[string]string)
 	schemaHandler.ExPathToInPath = make(map[string]string)
 	schemaHandler.SchemaElements = sh.SchemaElements
+	schemaHandler.AvroSchema = sh.AvroSchema
 
 	schemaHandler.Infos = make([]*common.Tag, len(sh.SchemaElements))
 	for i := 0; i < len(sh.SchemaElements); i++ {
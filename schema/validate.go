@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+/*
+Validate checks the schema elements against the parquet format spec: the physical type, the converted type and
+the logical type of every field must be compatible (e.g. UTF8 and STRING on BYTE_ARRAY, TIMESTAMP on INT64,
+DECIMAL with a precision fitting the physical type), the converted and logical types must agree, and the LIST and
+MAP groups must have the standard structure. It returns an error with every problem and its field path.
+*/
+func (sh *SchemaHandler) Validate() error {
+	var problems []string
+	for i, se := range sh.SchemaElements {
+		path := fieldPath(sh, int32(i))
+		if i == 0 {
+			path = sh.GetExName(0)
+		}
+		var children []*parquet.SchemaElement
+		if se.GetNumChildren() > 0 {
+			children = sh.childElements(int32(i))
+		}
+		for _, p := range validateSchemaElement(se, children) {
+			problems = append(problems, path+": "+p)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid schema: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+//Direct children of the group at idx
+func (sh *SchemaHandler) childElements(idx int32) []*parquet.SchemaElement {
+	res := make([]*parquet.SchemaElement, 0, sh.SchemaElements[idx].GetNumChildren())
+	pos := idx + 1
+	for i := int32(0); i < sh.SchemaElements[idx].GetNumChildren() && int(pos) < len(sh.SchemaElements); i++ {
+		res = append(res, sh.SchemaElements[pos])
+		pos = sh.nextSibling(pos)
+	}
+	return res
+}
+
+//Index of the element after the subtree of the element at idx
+func (sh *SchemaHandler) nextSibling(idx int32) int32 {
+	for rest := int32(1); rest > 0 && int(idx) < len(sh.SchemaElements); idx++ {
+		rest += sh.SchemaElements[idx].GetNumChildren() - 1
+	}
+	return idx
+}
+
+func validateSchemaElement(se *parquet.SchemaElement, children []*parquet.SchemaElement) []string {
+	if se.Type == nil {
+		return validateGroup(se, children)
+	}
+
+	var problems []string
+	t := se.GetType()
+	if t == parquet.Type_FIXED_LEN_BYTE_ARRAY && se.GetTypeLength() <= 0 {
+		problems = append(problems, "FIXED_LEN_BYTE_ARRAY without length")
+	}
+	if se.ConvertedType != nil {
+		if p := convertedTypeConflict(se); p != "" {
+			problems = append(problems, p)
+		}
+	}
+	//the logical type derived from the converted type has the same problems
+	if se.LogicalType != nil && se.ConvertedType != nil {
+		if p := annotationConflict(se); p != "" {
+			problems = append(problems, p)
+		} else {
+			return problems
+		}
+	}
+	if se.LogicalType != nil {
+		if p := logicalTypeTypeConflict(se); p != "" {
+			problems = append(problems, p)
+		}
+	}
+	return problems
+}
+
+func validateGroup(se *parquet.SchemaElement, children []*parquet.SchemaElement) []string {
+	var kind string
+	if se.ConvertedType != nil {
+		kind = se.GetConvertedType().String()
+	}
+	if lt := se.GetLogicalType(); lt != nil && !lt.IsSetUNKNOWN() {
+		ltKind := messageTypeAnnotation(se)
+		switch {
+		case lt.IsSetLIST():
+			ltKind = "LIST"
+		case lt.IsSetMAP():
+			ltKind = "MAP"
+		}
+		if kind != "" && kind != ltKind {
+			return []string{fmt.Sprintf("converted type %v doesn't match logical type %v", kind, ltKind)}
+		}
+		kind = ltKind
+	}
+
+	switch kind {
+	case "":
+	case "LIST":
+		if se.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+			return []string{"LIST can't be REPEATED"}
+		}
+		if len(children) != 1 || children[0].GetRepetitionType() != parquet.FieldRepetitionType_REPEATED {
+			return []string{"LIST must have exactly one REPEATED field"}
+		}
+	case "MAP":
+		if se.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+			return []string{"MAP can't be REPEATED"}
+		}
+		if len(children) != 1 || children[0].GetRepetitionType() != parquet.FieldRepetitionType_REPEATED ||
+			children[0].GetNumChildren() == 0 {
+			return []string{"MAP must have exactly one REPEATED group of the key and value"}
+		}
+	case "MAP_KEY_VALUE":
+		if len(children) == 0 || len(children) > 2 ||
+			children[0].GetRepetitionType() != parquet.FieldRepetitionType_REQUIRED {
+			return []string{"MAP without a REQUIRED key or with more than a key and a value"}
+		}
+	default:
+		return []string{fmt.Sprintf("group can't be annotated with %v", kind)}
+	}
+	return nil
+}
+
+func convertedTypeConflict(se *parquet.SchemaElement) string {
+	t, ct := se.GetType(), se.GetConvertedType()
+	var ok bool
+	switch ct {
+	case parquet.ConvertedType_UTF8, parquet.ConvertedType_ENUM, parquet.ConvertedType_JSON, parquet.ConvertedType_BSON:
+		ok = t == parquet.Type_BYTE_ARRAY
+	case parquet.ConvertedType_INT_8, parquet.ConvertedType_INT_16, parquet.ConvertedType_INT_32,
+		parquet.ConvertedType_UINT_8, parquet.ConvertedType_UINT_16, parquet.ConvertedType_UINT_32,
+		parquet.ConvertedType_DATE, parquet.ConvertedType_TIME_MILLIS:
+		ok = t == parquet.Type_INT32
+	case parquet.ConvertedType_INT_64, parquet.ConvertedType_UINT_64, parquet.ConvertedType_TIME_MICROS,
+		parquet.ConvertedType_TIMESTAMP_MILLIS, parquet.ConvertedType_TIMESTAMP_MICROS:
+		ok = t == parquet.Type_INT64
+	case parquet.ConvertedType_INTERVAL:
+		if t == parquet.Type_FIXED_LEN_BYTE_ARRAY && se.GetTypeLength() != 12 {
+			return "INTERVAL must be a FIXED_LEN_BYTE_ARRAY of length 12"
+		}
+		ok = t == parquet.Type_FIXED_LEN_BYTE_ARRAY
+	case parquet.ConvertedType_DECIMAL:
+		return decimalConflict(se, se.GetPrecision(), se.GetScale())
+	case parquet.ConvertedType_LIST, parquet.ConvertedType_MAP, parquet.ConvertedType_MAP_KEY_VALUE:
+		return fmt.Sprintf("%v must be a group", ct)
+	}
+	if !ok {
+		return fmt.Sprintf("converted type %v can't be used on %v", ct, t)
+	}
+	return ""
+}
+
+func logicalTypeTypeConflict(se *parquet.SchemaElement) string {
+	t, lt := se.GetType(), se.GetLogicalType()
+	var ok bool
+	switch {
+	case lt.IsSetUNKNOWN():
+		ok = true
+	case lt.IsSetSTRING(), lt.IsSetENUM(), lt.IsSetJSON(), lt.IsSetBSON():
+		ok = t == parquet.Type_BYTE_ARRAY
+	case lt.IsSetUUID():
+		ok = t == parquet.Type_FIXED_LEN_BYTE_ARRAY && se.GetTypeLength() == 16
+		if !ok {
+			return "UUID must be a FIXED_LEN_BYTE_ARRAY of length 16"
+		}
+	case lt.IsSetFLOAT16():
+		ok = t == parquet.Type_FIXED_LEN_BYTE_ARRAY && se.GetTypeLength() == 2
+		if !ok {
+			return "FLOAT16 must be a FIXED_LEN_BYTE_ARRAY of length 2"
+		}
+	case lt.IsSetDATE():
+		ok = t == parquet.Type_INT32
+	case lt.IsSetTIME():
+		if lt.TIME.Unit == nil {
+			return "TIME without unit"
+		}
+		ok = lt.TIME.Unit.IsSetMILLIS() && t == parquet.Type_INT32 || !lt.TIME.Unit.IsSetMILLIS() && t == parquet.Type_INT64
+	case lt.IsSetTIMESTAMP():
+		if lt.TIMESTAMP.Unit == nil {
+			return "TIMESTAMP without unit"
+		}
+		ok = t == parquet.Type_INT64
+	case lt.IsSetINTEGER():
+		switch lt.INTEGER.BitWidth {
+		case 8, 16, 32:
+			ok = t == parquet.Type_INT32
+		case 64:
+			ok = t == parquet.Type_INT64
+		default:
+			return fmt.Sprintf("INTEGER bit width %d must be 8, 16, 32 or 64", lt.INTEGER.BitWidth)
+		}
+	case lt.IsSetDECIMAL():
+		return decimalConflict(se, lt.DECIMAL.Precision, lt.DECIMAL.Scale)
+	case lt.IsSetLIST(), lt.IsSetMAP():
+		return fmt.Sprintf("%v must be a group", messageTypeAnnotation(se))
+	}
+	if !ok {
+		return fmt.Sprintf("logical type %v can't be used on %v", messageTypeAnnotation(se), t)
+	}
+	return ""
+}
+
+//The converted type must be the one of the logical type, e.g. TIMESTAMP(NANOS) has no converted type
+func annotationConflict(se *parquet.SchemaElement) string {
+	lt := se.GetLogicalType()
+	if lt.IsSetUNKNOWN() {
+		return ""
+	}
+	ct := convertedTypeFromLogicalType(lt)
+	if ct == nil || *ct != se.GetConvertedType() {
+		return fmt.Sprintf("converted type %v doesn't match logical type %v", se.GetConvertedType(), messageTypeAnnotation(se))
+	}
+	if lt.IsSetDECIMAL() && (lt.DECIMAL.Precision != se.GetPrecision() || lt.DECIMAL.Scale != se.GetScale()) {
+		return fmt.Sprintf("precision %d and scale %d don't match logical type %v",
+			se.GetPrecision(), se.GetScale(), messageTypeAnnotation(se))
+	}
+	return ""
+}
+
+func decimalConflict(se *parquet.SchemaElement, precision int32, scale int32) string {
+	var maxPrecision int32
+	switch se.GetType() {
+	case parquet.Type_INT32:
+		maxPrecision = 9
+	case parquet.Type_INT64:
+		maxPrecision = 18
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		//digits of the max signed integer of the length: log10(2^(8*length-1)-1)
+		maxPrecision = int32(math.Floor(float64(8*se.GetTypeLength()-1) * math.Log10(2)))
+	case parquet.Type_BYTE_ARRAY:
+		maxPrecision = math.MaxInt32
+	default:
+		return fmt.Sprintf("DECIMAL can't be used on %v", se.GetType())
+	}
+	switch {
+	case precision <= 0:
+		return "DECIMAL without precision"
+	case precision > maxPrecision:
+		return fmt.Sprintf("DECIMAL precision %d is larger than %d of %v", precision, maxPrecision, se.GetType())
+	case scale < 0 || scale > precision:
+		return fmt.Sprintf("DECIMAL scale %d must be between 0 and the precision %d", scale, precision)
+	}
+	return ""
+}
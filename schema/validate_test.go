@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	type Invalid struct {
+		Price     int64   `parquet:"name=price, type=INT64, convertedtype=DECIMAL, scale=2"`
+		Name      int64   `parquet:"name=name, type=INT64, convertedtype=UTF8"`
+		Hash      string  `parquet:"name=hash, type=FIXED_LEN_BYTE_ARRAY"`
+		Time      int32   `parquet:"name=time, type=INT32, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MILLIS"`
+		Amount    int32   `parquet:"name=amount, type=INT32, convertedtype=DECIMAL, scale=2, precision=10"`
+		Date      int32   `parquet:"name=date, type=INT32, convertedtype=TIME_MILLIS, logicaltype=DATE"`
+		Valid     string  `parquet:"name=valid, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Interval  string  `parquet:"name=interval, type=FIXED_LEN_BYTE_ARRAY, convertedtype=INTERVAL, length=8"`
+		Scores    []int64 `parquet:"name=scores, type=LIST, valuetype=INT64, valueconvertedtype=INT_32"`
+		Timestamp int64   `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	}
+	_, err := NewSchemaHandlerFromStruct(new(Invalid))
+	if err == nil {
+		t.Fatal("expect invalid schema error")
+	}
+	expected := []string{
+		"price: DECIMAL without precision",
+		"name: converted type UTF8 can't be used on INT64",
+		"hash: FIXED_LEN_BYTE_ARRAY without length",
+		"time: logical type TIMESTAMP(MILLIS,true) can't be used on INT32",
+		"amount: DECIMAL precision 10 is larger than 9 of INT32",
+		"date: converted type TIME_MILLIS doesn't match logical type DATE",
+		"interval: INTERVAL must be a FIXED_LEN_BYTE_ARRAY of length 12",
+		"scores.list.element: converted type INT_32 can't be used on INT64",
+	}
+	for _, e := range expected {
+		if !strings.Contains(err.Error(), e) {
+			t.Errorf("expect %q in %q", e, err.Error())
+		}
+	}
+	if n := strings.Count(err.Error(), "; ") + 1; n != len(expected) {
+		t.Errorf("expect %d problems, get %d: %v", len(expected), n, err)
+	}
+
+	type Unknown struct {
+		Name string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=STRING"`
+	}
+	if _, err = NewSchemaHandlerFromStruct(new(Unknown)); err == nil || !strings.Contains(err.Error(), "name: convertedtype STRING") {
+		t.Errorf("expect unknown convertedtype error, get %v", err)
+	}
+
+	jsonSchema := `
+	{
+	  "Tag": "name=parquet_go_root, repetitiontype=REQUIRED",
+	  "Fields": [
+		{"Tag": "name=tags, type=LIST, repetitiontype=REPEATED",
+		 "Fields": [{"Tag": "name=element, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"}]},
+		{"Tag": "name=attrs, type=MAP, repetitiontype=OPTIONAL",
+		 "Fields": [
+		   {"Tag": "name=key, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"},
+		   {"Tag": "name=value, type=INT32, repetitiontype=REQUIRED"}
+		 ]},
+		{"Tag": "name=uuid, type=FIXED_LEN_BYTE_ARRAY, length=8, logicaltype=UUID, repetitiontype=REQUIRED"}
+	  ]
+	}
+	`
+	_, err = NewSchemaHandlerFromJSON(jsonSchema)
+	if err == nil {
+		t.Fatal("expect invalid schema error")
+	}
+	expected = []string{
+		"tags: LIST can't be REPEATED",
+		"attrs.key_value: MAP without a REQUIRED key",
+		"uuid: UUID must be a FIXED_LEN_BYTE_ARRAY of length 16",
+	}
+	for _, e := range expected {
+		if !strings.Contains(err.Error(), e) {
+			t.Errorf("expect %q in %q", e, err.Error())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+/*
+ReaderAtFile is a read only ParquetFile of the size bytes of an io.ReaderAt, e.g. *os.File, *bytes.Reader or an HTTP
+range client. It reads with positioned reads at its own offset and Open("") returns a new ReaderAtFile of the same
+io.ReaderAt instead of reopening it, so the column buffers of a reader share the io.ReaderAt concurrently.
+The io.ReaderAt must be safe for parallel ReadAt calls and isn't closed by Close.
+*/
+type ReaderAtFile struct {
+	r      io.ReaderAt
+	size   int64
+	offset int64
+}
+
+//NewReaderAtFile creates a ParquetFile reading the size bytes of r
+func NewReaderAtFile(r io.ReaderAt, size int64) *ReaderAtFile {
+	return &ReaderAtFile{r: r, size: size}
+}
+
+func (f *ReaderAtFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, fmt.Errorf("invalid whence %v", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset %v", offset)
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *ReaderAtFile) Read(b []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+	if rest := f.size - f.offset; int64(len(b)) > rest {
+		b = b[:rest]
+	}
+	n, err := f.r.ReadAt(b, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n == len(b) {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *ReaderAtFile) Write(b []byte) (int, error) {
+	return 0, errors.New("ReaderAtFile is read only")
+}
+
+func (f *ReaderAtFile) Close() error {
+	return nil
+}
+
+//Open returns a new ReaderAtFile of the same io.ReaderAt for the empty name, other files can't be opened
+func (f *ReaderAtFile) Open(name string) (ParquetFile, error) {
+	if name != "" {
+		return nil, fmt.Errorf("ReaderAtFile can't open %v", name)
+	}
+	return NewReaderAtFile(f.r, f.size), nil
+}
+
+func (f *ReaderAtFile) Create(name string) (ParquetFile, error) {
+	return nil, errors.New("ReaderAtFile is read only")
+}
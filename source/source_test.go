@@ -0,0 +1,67 @@
+package source_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+func TestReaderAtWriterAt(t *testing.T) {
+	type Entry struct {
+		Id   int64   `parquet:"name=id, type=INT64"`
+		Name string  `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Tags []int32 `parquet:"name=tags, type=LIST, valuetype=INT32"`
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "entries.parquet"))
+	assert.NoError(t, err)
+	defer f.Close()
+	pw, err := writer.NewParquetWriter(source.NewWriterAtFile(f), new(Entry), 2)
+	assert.NoError(t, err)
+	expected := make([]Entry, 50)
+	for i := range expected {
+		expected[i] = Entry{Id: int64(i), Name: fmt.Sprintf("name_%d", i), Tags: []int32{int32(i), int32(i + 1)}}
+		assert.NoError(t, pw.Write(expected[i]))
+		if i == 19 {
+			assert.NoError(t, pw.Flush(true))
+		}
+	}
+	assert.NoError(t, pw.WriteStop())
+	info, err := f.Stat()
+	assert.NoError(t, err)
+
+	//only ReadAt, the file can't be reopened or seeked
+	readerAt := struct{ io.ReaderAt }{f}
+	pr, err := reader.NewParquetReaderFromReaderAt(readerAt, info.Size(), new(Entry), 4)
+	assert.NoError(t, err)
+	entries := make([]Entry, len(expected))
+	assert.NoError(t, pr.Read(&entries))
+	assert.Equal(t, expected, entries)
+
+	pr, err = reader.NewParquetReaderFromReaderAt(readerAt, info.Size(), new(Entry), 4, reader.WithRowRange(15, 25))
+	assert.NoError(t, err)
+	entries = make([]Entry, 20)
+	assert.NoError(t, pr.Read(&entries))
+	assert.Equal(t, expected[15:25], entries)
+
+	buf, err := os.ReadFile(f.Name())
+	assert.NoError(t, err)
+	pr, err = reader.NewParquetColumnReaderFromReaderAt(bytes.NewReader(buf), int64(len(buf)), 1)
+	assert.NoError(t, err)
+	values, _, _, err := pr.ReadColumnByPath(common.ReformPathStr("parquet_go_root.name"), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"name_0", "name_1"}, values)
+
+	_, err = reader.NewParquetReaderFromReaderAt(bytes.NewReader(buf[:len(buf)-1]), int64(len(buf)-1), new(Entry), 1)
+	assert.Error(t, err)
+}
+
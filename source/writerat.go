@@ -0,0 +1,58 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+/*
+WriterAtFile is a write only ParquetFile of an io.WriterAt, e.g. *os.File or a multipart upload. It writes with
+positioned writes at its own offset. The io.WriterAt isn't closed by Close.
+*/
+type WriterAtFile struct {
+	w      io.WriterAt
+	offset int64
+}
+
+//NewWriterAtFile creates a ParquetFile writing to w from the offset 0
+func NewWriterAtFile(w io.WriterAt) *WriterAtFile {
+	return &WriterAtFile{w: w}
+}
+
+func (f *WriterAtFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	default:
+		return 0, fmt.Errorf("invalid whence %v", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset %v", offset)
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *WriterAtFile) Read(b []byte) (int, error) {
+	return 0, errors.New("WriterAtFile is write only")
+}
+
+func (f *WriterAtFile) Write(b []byte) (int, error) {
+	n, err := f.w.WriteAt(b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *WriterAtFile) Close() error {
+	return nil
+}
+
+func (f *WriterAtFile) Open(name string) (ParquetFile, error) {
+	return nil, errors.New("WriterAtFile is write only")
+}
+
+func (f *WriterAtFile) Create(name string) (ParquetFile, error) {
+	return nil, fmt.Errorf("WriterAtFile can't create %v", name)
+}
@@ -0,0 +1,324 @@
+package gentool
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+//Go types of the physical types
+var goTypes = map[parquet.Type]string{
+	parquet.Type_BOOLEAN:              "bool",
+	parquet.Type_INT32:                "int32",
+	parquet.Type_INT64:                "int64",
+	parquet.Type_INT96:                "string",
+	parquet.Type_FLOAT:                "float32",
+	parquet.Type_DOUBLE:               "float64",
+	parquet.Type_BYTE_ARRAY:           "string",
+	parquet.Type_FIXED_LEN_BYTE_ARRAY: "string",
+}
+
+type generator struct {
+	sh *schema.SchemaHandler
+	//declarations of the struct types in order
+	decls     []string
+	typeNames map[string]bool
+}
+
+/*
+Generate returns the Go source of the struct types of the schema, the root struct is named typeName.
+The fields have the parquet tags of NewSchemaHandlerFromStruct: physical, converted and logical types,
+LIST and MAP fields are slices and maps, REPEATED fields are slices and OPTIONAL fields are pointers.
+Nested groups are separate types named by the type and the field names, e.g. StudentAddress.
+*/
+func Generate(sh *schema.SchemaHandler, packageName string, typeName string) ([]byte, error) {
+	if len(sh.SchemaElements) == 0 || sh.SchemaElements[0].GetNumChildren() == 0 {
+		return nil, fmt.Errorf("empty schema")
+	}
+	g := &generator{sh: sh, typeNames: make(map[string]bool)}
+	if _, err := g.genStruct(0, typeName); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by parquet-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", packageName)
+	for _, decl := range g.decls {
+		buf.WriteString("\n")
+		buf.WriteString(decl)
+	}
+	return format.Source(buf.Bytes())
+}
+
+//A type name which isn't used yet
+func (g *generator) newTypeName(name string) string {
+	res := name
+	for i := 2; g.typeNames[res]; i++ {
+		res = fmt.Sprintf("%s%d", name, i)
+	}
+	g.typeNames[res] = true
+	return res
+}
+
+//Initialisms which are upper case in Go names
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "URI": true, "URL": true, "UTF8": true,
+	"UUID": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+//CamelCase exported Go name of the column name, e.g. created_at is CreatedAt, zip-code is ZipCode and uuid is UUID
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var res strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			res.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		res.WriteString(string(runes))
+	}
+	switch {
+	case res.Len() == 0:
+		return "Field"
+	case !unicode.IsUpper([]rune(res.String())[0]):
+		//starts with a digit or a letter without case
+		return "X" + res.String()
+	}
+	return res.String()
+}
+
+//Declare the struct type of the group at idx and return its name
+func (g *generator) genStruct(idx int32, typeName string) (string, error) {
+	typeName = g.newTypeName(typeName)
+	//keep the position of the declaration, the nested types follow it
+	pos := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	fieldNames := make(map[string]bool)
+	for _, childIdx := range g.sh.ChildrenIndex(idx) {
+		name := goName(g.sh.GetExName(int(childIdx)))
+		fieldName := name
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", name, i)
+		}
+		fieldNames[fieldName] = true
+
+		goType, tags, err := g.field(childIdx, typeName+fieldName)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&buf, "\t%s %s `parquet:\"%s\"`\n", fieldName, goType, strings.Join(tags, ", "))
+	}
+	buf.WriteString("}\n")
+	g.decls[pos] = buf.String()
+	return typeName, nil
+}
+
+//Go type and tags of the field at idx
+func (g *generator) field(idx int32, typeName string) (string, []string, error) {
+	sh := g.sh
+	se := sh.SchemaElements[idx]
+	name := sh.GetExName(int(idx))
+	if strings.ContainsAny(name, ",=") {
+		return "", nil, fmt.Errorf("field name %q can't be in a tag", name)
+	}
+	goType, tags, err := g.fieldType(idx, typeName)
+	if err != nil {
+		return "", nil, err
+	}
+	tags = append([]string{"name=" + name}, tags...)
+	if rt := se.GetRepetitionType(); rt != parquet.FieldRepetitionType_REQUIRED {
+		tags = append(tags, "repetitiontype="+rt.String())
+	}
+	if sh.HasFieldID(idx) {
+		tags = append(tags, fmt.Sprintf("fieldid=%d", se.GetFieldID()))
+	}
+	return goType, tags, nil
+}
+
+//Go type and type tags of the field at idx
+func (g *generator) fieldType(idx int32, typeName string) (string, []string, error) {
+	sh := g.sh
+	se := sh.SchemaElements[idx]
+	rt := se.GetRepetitionType()
+
+	if rt == parquet.FieldRepetitionType_REPEATED {
+		if se.GetNumChildren() > 0 {
+			structName, err := g.genStruct(idx, typeName)
+			return "[]" + structName, nil, err
+		}
+		tags, err := leafTags(se, "")
+		return "[]" + goTypes[se.GetType()], tags, err
+	}
+
+	if elementIdx, levels := sh.ListElementIndex(idx); levels > 0 {
+		goType, elementTags, err := g.nested(elementIdx, levels == 3, typeName)
+		if err != nil {
+			return "", nil, err
+		}
+		return "[]" + goType, append([]string{"type=LIST"}, elementTags...), nil
+	}
+
+	if keyIdx, valueIdx, ok := sh.MapKeyValueIndex(idx); ok {
+		key := sh.SchemaElements[keyIdx]
+		if key.GetNumChildren() > 0 {
+			return "", nil, fmt.Errorf("key of map %v isn't primitive", sh.GetExName(int(idx)))
+		}
+		keyTags, err := leafTags(key, "key")
+		if err != nil {
+			return "", nil, err
+		}
+		if sh.HasFieldID(keyIdx) {
+			keyTags = append(keyTags, fmt.Sprintf("keyfieldid=%d", key.GetFieldID()))
+		}
+		goType, valueTags, err := g.nested(valueIdx, true, typeName+"Value")
+		if err != nil {
+			return "", nil, err
+		}
+		tags := append(append([]string{"type=MAP"}, keyTags...), valueTags...)
+		return "map[" + goTypes[key.GetType()] + "]" + goType, tags, nil
+	}
+
+	ptr := ""
+	if rt == parquet.FieldRepetitionType_OPTIONAL {
+		ptr = "*"
+	}
+	if se.GetNumChildren() > 0 {
+		structName, err := g.genStruct(idx, typeName)
+		return ptr + structName, nil, err
+	}
+	tags, err := leafTags(se, "")
+	return ptr + goTypes[se.GetType()], tags, err
+}
+
+//Go type and tags of a list element or a map value, they are the value tags of the field
+func (g *generator) nested(idx int32, canBeOptional bool, typeName string) (string, []string, error) {
+	sh := g.sh
+	se := sh.SchemaElements[idx]
+	ptr := ""
+	if canBeOptional && se.GetRepetitionType() == parquet.FieldRepetitionType_OPTIONAL {
+		ptr = "*"
+	}
+
+	var tags []string
+	if sh.HasFieldID(idx) {
+		tags = append(tags, fmt.Sprintf("valuefieldid=%d", se.GetFieldID()))
+	}
+	if se.GetNumChildren() == 0 {
+		leafTags, err := leafTags(se, "value")
+		return ptr + goTypes[se.GetType()], append(tags, leafTags...), err
+	}
+
+	//the tags of nested lists and maps can't be written
+	_, levels := sh.ListElementIndex(idx)
+	_, _, isMap := sh.MapKeyValueIndex(idx)
+	if levels > 0 || isMap {
+		return "", nil, fmt.Errorf("%v: nested LIST or MAP in LIST or MAP isn't supported by the struct tags", sh.IndexMap[idx])
+	}
+	structName, err := g.genStruct(idx, typeName)
+	return ptr + structName, tags, err
+}
+
+//Tags of the type of the primitive field, e.g. type=INT32, convertedtype=DATE, logicaltype=DATE
+func leafTags(se *parquet.SchemaElement, prefix string) ([]string, error) {
+	tags := []string{prefix + "type=" + se.GetType().String()}
+	if se.IsSetConvertedType() {
+		tags = append(tags, prefix+"convertedtype="+se.GetConvertedType().String())
+	}
+	if se.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY {
+		tags = append(tags, fmt.Sprintf("%slength=%d", prefix, se.GetTypeLength()))
+	}
+	if se.IsSetConvertedType() && se.GetConvertedType() == parquet.ConvertedType_DECIMAL {
+		tags = append(tags, fmt.Sprintf("%sscale=%d", prefix, se.GetScale()), fmt.Sprintf("%sprecision=%d", prefix, se.GetPrecision()))
+	}
+
+	lt := se.LogicalType
+	if lt == nil {
+		return tags, nil
+	}
+	//the logical type of the converted type is set by NewSchemaHandlerFromStruct
+	if se.IsSetConvertedType() {
+		adjustedToUTC := (lt.IsSetTIME() && lt.TIME.IsAdjustedToUTC) || (lt.IsSetTIMESTAMP() && lt.TIMESTAMP.IsAdjustedToUTC)
+		info := &common.Tag{Scale: se.GetScale(), Precision: se.GetPrecision(), IsAdjustedToUTC: adjustedToUTC}
+		if reflect.DeepEqual(common.NewLogicalTypeFromConvertedType(se, info), lt) {
+			if adjustedToUTC {
+				tags = append(tags, prefix+"isadjustedtoutc=true")
+			}
+			return tags, nil
+		}
+	}
+	prefix += "logicaltype"
+	switch {
+	case lt.IsSetSTRING():
+		tags = append(tags, prefix+"=STRING")
+	case lt.IsSetENUM():
+		tags = append(tags, prefix+"=ENUM")
+	case lt.IsSetJSON():
+		tags = append(tags, prefix+"=JSON")
+	case lt.IsSetBSON():
+		tags = append(tags, prefix+"=BSON")
+	case lt.IsSetUUID():
+		tags = append(tags, prefix+"=UUID")
+	case lt.IsSetFLOAT16():
+		tags = append(tags, prefix+"=FLOAT16")
+	case lt.IsSetUNKNOWN():
+		tags = append(tags, prefix+"=UNKNOWN")
+	case lt.IsSetDATE():
+		tags = append(tags, prefix+"=DATE")
+	case lt.IsSetDECIMAL():
+		tags = append(tags, prefix+"=DECIMAL",
+			fmt.Sprintf("%s.precision=%d", prefix, lt.DECIMAL.Precision),
+			fmt.Sprintf("%s.scale=%d", prefix, lt.DECIMAL.Scale))
+	case lt.IsSetTIME():
+		unit, err := timeUnit(lt.TIME.Unit, se)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, prefix+"=TIME",
+			fmt.Sprintf("%s.isadjustedtoutc=%v", prefix, lt.TIME.IsAdjustedToUTC),
+			prefix+".unit="+unit)
+	case lt.IsSetTIMESTAMP():
+		unit, err := timeUnit(lt.TIMESTAMP.Unit, se)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, prefix+"=TIMESTAMP",
+			fmt.Sprintf("%s.isadjustedtoutc=%v", prefix, lt.TIMESTAMP.IsAdjustedToUTC),
+			prefix+".unit="+unit)
+	case lt.IsSetINTEGER():
+		tags = append(tags, prefix+"=INTEGER",
+			fmt.Sprintf("%s.bitwidth=%d", prefix, lt.INTEGER.BitWidth),
+			fmt.Sprintf("%s.issigned=%v", prefix, lt.INTEGER.IsSigned))
+	default:
+		return nil, fmt.Errorf("logical type %v of %v isn't supported by the struct tags", lt, se.GetName())
+	}
+	return tags, nil
+}
+
+func timeUnit(unit *parquet.TimeUnit, se *parquet.SchemaElement) (string, error) {
+	switch {
+	case unit == nil:
+		return "", fmt.Errorf("time unit of %v is missing", se.GetName())
+	case unit.IsSetMILLIS():
+		return "MILLIS", nil
+	case unit.IsSetMICROS():
+		return "MICROS", nil
+	}
+	return "NANOS", nil
+}
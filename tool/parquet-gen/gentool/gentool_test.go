@@ -0,0 +1,152 @@
+package gentool
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+//The golden files are compiled with the tests to check their schemas
+var goldenTests = []struct {
+	schemaFile string
+	typeName   string
+	goldenFile string
+	obj        interface{}
+}{
+	{"testdata/student.json", "Student", "golden_student_test.go", new(Student)},
+}
+
+//Compare the schemas without the Go names
+func assertSameSchema(t *testing.T, expected, actual *schema.SchemaHandler) {
+	if len(expected.SchemaElements) != len(actual.SchemaElements) {
+		t.Fatalf("expected %d schema elements, got %d", len(expected.SchemaElements), len(actual.SchemaElements))
+	}
+	for i := range expected.SchemaElements {
+		e, a := *expected.SchemaElements[i], *actual.SchemaElements[i]
+		e.Name, a.Name = "", ""
+		if !reflect.DeepEqual(e, a) || expected.GetExName(i) != actual.GetExName(i) {
+			t.Errorf("schema element %v: expected %v %v, got %v %v", expected.IndexMap[int32(i)], expected.GetExName(i), e, actual.GetExName(i), a)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, data := range goldenTests {
+		bs, err := os.ReadFile(data.schemaFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sh, err := schema.NewSchemaHandlerFromJSON(string(bs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		src, err := Generate(sh, "gentool", data.typeName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if *update {
+			if err = os.WriteFile(data.goldenFile, src, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		golden, err := os.ReadFile(data.goldenFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(src, golden) {
+			t.Errorf("%s isn't up to date, run go test -update\n%s", data.goldenFile, src)
+		}
+
+		//round trip of the generated struct
+		structSh, err := schema.NewSchemaHandlerFromStruct(data.obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSameSchema(t, sh, structSh)
+
+		//the schema of the file written with the struct generates the same code
+		var buf bytes.Buffer
+		pw, err := writer.NewParquetWriterFromWriter(&buf, data.obj, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = pw.WriteStop(); err != nil {
+			t.Fatal(err)
+		}
+		pf, err := buffer.NewBufferFile(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		pr, err := reader.NewParquetReader(pf, nil, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileSrc, err := Generate(pr.SchemaHandler, "gentool", data.typeName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(src, fileSrc) {
+			t.Errorf("%s: the file schema generates\n%s", filepath.Base(data.schemaFile), fileSrc)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	jsonSchema := `{
+	  "Tag": "name=parquet_go_root, repetitiontype=REQUIRED",
+	  "Fields": [
+	    {"Tag": "name=matrix, type=LIST",
+	     "Fields": [{"Tag": "name=element, type=LIST", "Fields": [{"Tag": "name=element, type=INT32"}]}]}
+	  ]
+	}`
+	sh, err := schema.NewSchemaHandlerFromJSON(jsonSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Generate(sh, "gentool", "Matrix"); err == nil {
+		t.Errorf("expected error of nested lists")
+	}
+
+	sh = schema.NewSchemaHandlerFromSchemaList([]*parquet.SchemaElement{{Name: "root"}})
+	if _, err = Generate(sh, "gentool", "Empty"); err == nil {
+		t.Errorf("expected error of empty schema")
+	}
+
+	numChildren, int64Type := int32(1), parquet.Type_INT64
+	sh = schema.NewSchemaHandlerFromSchemaList([]*parquet.SchemaElement{
+		{Name: "root", NumChildren: &numChildren},
+		{Name: "ts", Type: &int64Type, LogicalType: &parquet.LogicalType{TIMESTAMP: &parquet.TimestampType{}}},
+	})
+	if _, err = Generate(sh, "gentool", "Timestamp"); err == nil || err.Error() != "time unit of ts is missing" {
+		t.Errorf("expected error of missing time unit, get %v", err)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"created_at": "CreatedAt",
+		"zip-code":   "ZipCode",
+		"uuid":       "UUID",
+		"user_id":    "UserID",
+		"userId":     "UserId",
+		"2fa":        "X2fa",
+		"__":         "Field",
+		"größe":      "Größe",
+	} {
+		if res := goName(name); res != expected {
+			t.Errorf("goName(%q) = %q, expect %q", name, res, expected)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Code generated by parquet-gen. DO NOT EDIT.
+
+package gentool
+
+type Student struct {
+	Name      string                          `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Age       *int32                          `parquet:"name=age, type=INT32, convertedtype=INT_8, repetitiontype=OPTIONAL"`
+	ID        int64                           `parquet:"name=id, type=INT64, fieldid=7"`
+	Weight    *float32                        `parquet:"name=weight, type=FLOAT, repetitiontype=OPTIONAL"`
+	Sex       bool                            `parquet:"name=sex, type=BOOLEAN"`
+	Day       int32                           `parquet:"name=day, type=INT32, convertedtype=DATE"`
+	CreatedAt int64                           `parquet:"name=created_at, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	UpdatedAt int64                           `parquet:"name=updated_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, isadjustedtoutc=true"`
+	Price     string                          `parquet:"name=price, type=FIXED_LEN_BYTE_ARRAY, convertedtype=DECIMAL, length=8, scale=2, precision=18"`
+	UUID      *string                         `parquet:"name=uuid, type=FIXED_LEN_BYTE_ARRAY, length=16, logicaltype=UUID, repetitiontype=OPTIONAL"`
+	Legacy    string                          `parquet:"name=legacy, type=INT96"`
+	Classes   []*string                       `parquet:"name=classes, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Scores    map[string]*float64             `parquet:"name=scores, type=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=DOUBLE"`
+	Friends   []StudentFriends                `parquet:"name=friends, type=LIST"`
+	Teachers  map[int32]*StudentTeachersValue `parquet:"name=teachers, type=MAP, keytype=INT32, repetitiontype=OPTIONAL"`
+	Address   *StudentAddress                 `parquet:"name=address, repetitiontype=OPTIONAL"`
+	Tags      []int32                         `parquet:"name=tags, type=INT32, repetitiontype=REPEATED"`
+	Events    []StudentEvents                 `parquet:"name=events, repetitiontype=REPEATED"`
+}
+
+type StudentFriends struct {
+	Name string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ID   *int64 `parquet:"name=id, type=INT64, repetitiontype=OPTIONAL"`
+}
+
+type StudentTeachersValue struct {
+	Name string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+type StudentAddress struct {
+	City    string `parquet:"name=city, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ZipCode *int32 `parquet:"name=zip-code, type=INT32, convertedtype=UINT_16, repetitiontype=OPTIONAL"`
+}
+
+type StudentEvents struct {
+	At int64 `parquet:"name=at, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+}
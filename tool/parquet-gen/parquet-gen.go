@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/tool/parquet-gen/gentool"
+)
+
+func main() {
+	fileName := flag.String("file", "", "parquet file to generate the structs from")
+	schemaName := flag.String("schema", "", "JSON schema file to generate the structs from")
+	typeName := flag.String("type", "Root", "name of the root struct")
+	packageName := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file, default is $GOPACKAGE of go generate or main")
+	outName := flag.String("out", "", "output file, default is stdout")
+
+	flag.Parse()
+
+	if (*fileName == "") == (*schemaName == "") {
+		fmt.Fprintf(os.Stderr, "one of -file and -schema is required\n")
+		os.Exit(1)
+	}
+	if *packageName == "" {
+		*packageName = "main"
+	}
+
+	var sh *schema.SchemaHandler
+	if *fileName != "" {
+		fr, err := local.NewLocalFileReader(*fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open local file [%s]: %s\n", *fileName, err.Error())
+			os.Exit(1)
+		}
+		pr, err := reader.NewParquetReader(fr, nil, 1)
+		if err != nil {
+			fr.Close()
+			fmt.Fprintf(os.Stderr, "Can't create parquet reader: %s\n", err)
+			os.Exit(1)
+		}
+		sh = pr.SchemaHandler
+		pr.ReadStop()
+		fr.Close()
+	} else {
+		bs, err := os.ReadFile(*schemaName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read JSON schema [%s]: %s\n", *schemaName, err.Error())
+			os.Exit(1)
+		}
+		if sh, err = schema.NewSchemaHandlerFromJSON(string(bs)); err != nil {
+			fmt.Fprintf(os.Stderr, "Can't parse JSON schema: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	src, err := gentool.Generate(sh, *packageName, *typeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't generate structs: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *outName == "" {
+		os.Stdout.Write(src)
+	} else if err = os.WriteFile(*outName, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write [%s]: %s\n", *outName, err.Error())
+		os.Exit(1)
+	}
+}
@@ -17,26 +17,35 @@ import (
 	"github.com/xitongsys/parquet-go-source/local"
 	"github.com/xitongsys/parquet-go-source/s3"
 	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/schema"
 	"github.com/xitongsys/parquet-go/source"
 	"github.com/xitongsys/parquet-go/tool/parquet-tools/schematool"
 	"github.com/xitongsys/parquet-go/tool/parquet-tools/sizetool"
 )
 
 func main() {
-	cmd := flag.String("cmd", "schema", "command to run. Allowed values: schema, rowcount, size, cat")
+	cmd := flag.String("cmd", "schema", "command to run. Allowed values: schema, rowcount, size, cat, csv, schema-diff")
 	fileName := flag.String("file", "", "file name")
+	fileName2 := flag.String("file2", "", "file name of the new schema for schema-diff")
+	matchFieldID := flag.Bool("fieldid", false, "match the fields by field_id for schema-diff")
 	withTags := flag.Bool("tag", false, "show struct tags")
 	withPrettySize := flag.Bool("pretty", false, "show pretty size")
 	uncompressedSize := flag.Bool("uncompressed", false, "show uncompressed size")
 	catCount := flag.Int("count", 1000, "max count to cat. If it is nil, only show first 1000 records.")
 	skipCount := flag.Int64("skip", 0, "skip count with cat. If it is nil,skip 0 records.")
-	schemaFormat := flag.String("schema-format", "json", "schema format go/json (default to JSON schema)")
+	schemaFormat := flag.String("schema-format", "json", "schema format go/json/message (default to JSON schema)")
+	ndjson := flag.Bool("ndjson", false, "cat one JSON record per line")
+	csvDelimiter := flag.String("delimiter", ",", "field delimiter of csv")
+	csvHeader := flag.Bool("header", true, "write the column names as the first csv record")
+	csvNull := flag.String("null", "", "text of the null values in csv")
+	csvTimestampFormat := flag.String("timestamp-format", "", "go time layout of the timestamps in csv, default is RFC3339")
+	csvFlatten := flag.Bool("flatten", false, "write the columns of nested groups to csv with dotted names")
 
 	flag.Parse()
 
 	// validate schema output format
-	if *schemaFormat != "json" && *schemaFormat != "go" {
-		fmt.Fprintf(os.Stderr, "schema format can only be json or go\n")
+	if *schemaFormat != "json" && *schemaFormat != "go" && *schemaFormat != "message" {
+		fmt.Fprintf(os.Stderr, "schema format can only be json, go or message\n")
 		os.Exit(1)
 	}
 
@@ -46,56 +55,29 @@ func main() {
 		os.Exit(1)
 	}
 
-	// validate file scheme (s3 or file)
-	uri, err := url.Parse(*fileName)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "unable to parse file location [%s]\n", *fileName)
-		os.Exit(1)
-	}
-	if uri.Scheme == "" {
-		uri.Scheme = "file"
-	}
-
-	var fr source.ParquetFile
-	switch uri.Scheme {
-	case "s3":
-		// determine S3 bucket's region
-		ctx := context.Background()
-		sess := session.Must(session.NewSession())
-		region, err := s3manager.GetBucketRegion(ctx, sess, uri.Host, "us-east-1")
-		if err != nil {
-			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
-				fmt.Fprintf(os.Stderr, "unable to find bucket %s's region not found", uri.Host)
-			} else {
-				fmt.Fprintf(os.Stderr, "AWS error: %s", err.Error())
-			}
-			os.Exit(1)
-		}
-
-		fr, err = s3.NewS3FileReader(ctx, uri.Host, strings.TrimLeft(uri.Path, "/"), &aws.Config{Region: aws.String(region)})
+	fr := openFile(*fileName)
+	//the csv command reads the file with its own CSVReader
+	var pr *reader.ParquetReader
+	var err error
+	if *cmd != "csv" {
+		pr, err = reader.NewParquetReader(fr, nil, 1)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to open S3 object [%s]: %s\n", *fileName, err.Error())
+			fmt.Fprintf(os.Stderr, "Can't create parquet reader: %s\n", err)
 			os.Exit(1)
 		}
-	case "file":
-		fr, err = local.NewLocalFileReader(uri.Path)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to open local file [%s]: %s\n", uri.Path, err.Error())
-			os.Exit(1)
-		}
-	default:
-		fmt.Fprintf(os.Stderr, "unknown location scheme [%s]\n", uri.Scheme)
-		os.Exit(1)
-	}
-
-	pr, err := reader.NewParquetReader(fr, nil, 1)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Can't create parquet reader: %s\n", err)
-		os.Exit(1)
 	}
 
 	switch *cmd {
 	case "schema":
+		if *schemaFormat == "message" {
+			text, err := pr.SchemaHandler.MessageTypeString()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Can't print schema: %s\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(text)
+			break
+		}
 		tree := schematool.CreateSchemaTree(pr.SchemaHandler.SchemaElements)
 		if *schemaFormat == "go" {
 			fmt.Printf("%s\n", tree.OutputStruct(*withTags))
@@ -120,22 +102,81 @@ func main() {
 				os.Exit(1)
 			}
 
-			res, err := pr.ReadByNumber(cnt)
+			if *ndjson {
+				if _, err = pr.ReadNDJSON(os.Stdout, cnt); err != nil {
+					fmt.Fprintf(os.Stderr, "Can't cat: %s\n", err)
+					os.Exit(1)
+				}
+				totCnt += cnt
+				continue
+			}
+
+			res, err := pr.ReadJSON(cnt)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Can't cat: %s\n", err)
 				os.Exit(1)
 			}
 
-			jsonBs, err := json.Marshal(res)
+			fmt.Println("[" + strings.Join(res, ",") + "]")
+
+			totCnt += cnt
+		}
+
+	case "csv":
+		delimiter := []rune(*csvDelimiter)
+		if len(delimiter) != 1 {
+			fmt.Fprintf(os.Stderr, "delimiter must be one character\n")
+			os.Exit(1)
+		}
+		cr, err := reader.NewCSVReader(fr, 1,
+			reader.WithCSVDelimiter(delimiter[0]),
+			reader.WithCSVHeader(*csvHeader),
+			reader.WithCSVNullToken(*csvNull),
+			reader.WithCSVTimestampFormat(*csvTimestampFormat),
+			reader.WithCSVFlatten(*csvFlatten),
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't create csv reader: %s\n", err)
+			os.Exit(1)
+		}
+		if err = cr.SkipRows(*skipCount); err != nil {
+			fmt.Fprintf(os.Stderr, "Can't skip: %s\n", err)
+			os.Exit(1)
+		}
+		for totCnt := 0; totCnt < *catCount; {
+			cnt := *catCount - totCnt
+			if cnt > 1000 {
+				cnt = 1000
+			}
+			n, err := cr.ReadCSV(os.Stdout, cnt)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Can't to json: %s\n", err)
+				fmt.Fprintf(os.Stderr, "Can't write csv: %s\n", err)
 				os.Exit(1)
 			}
+			if n == 0 {
+				break
+			}
+			totCnt += n
+		}
 
-			fmt.Println(string(jsonBs))
-
-			totCnt += cnt
+	case "schema-diff":
+		if *fileName2 == "" {
+			fmt.Fprintf(os.Stderr, "missing location of parquet file2\n")
+			os.Exit(1)
+		}
+		fr2 := openFile(*fileName2)
+		pr2, err := reader.NewParquetReader(fr2, nil, 1)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't create parquet reader: %s\n", err)
+			os.Exit(1)
+		}
+		diff := schema.Diff(pr.SchemaHandler, pr2.SchemaHandler, schema.WithDiffMatchByFieldID(*matchFieldID))
+		res, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't marshal schema diff: %s\n", err)
+			os.Exit(1)
 		}
+		fmt.Println(string(res))
 
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command %s\n", *cmd)
@@ -143,3 +184,49 @@ func main() {
 	}
 
 }
+
+//Open the local or S3 (s3://bucket/key) parquet file, it exits on errors
+func openFile(fileName string) source.ParquetFile {
+	// validate file scheme (s3 or file)
+	uri, err := url.Parse(fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to parse file location [%s]\n", fileName)
+		os.Exit(1)
+	}
+	if uri.Scheme == "" {
+		uri.Scheme = "file"
+	}
+
+	var fr source.ParquetFile
+	switch uri.Scheme {
+	case "s3":
+		// determine S3 bucket's region
+		ctx := context.Background()
+		sess := session.Must(session.NewSession())
+		region, err := s3manager.GetBucketRegion(ctx, sess, uri.Host, "us-east-1")
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+				fmt.Fprintf(os.Stderr, "unable to find bucket %s's region not found", uri.Host)
+			} else {
+				fmt.Fprintf(os.Stderr, "AWS error: %s", err.Error())
+			}
+			os.Exit(1)
+		}
+
+		fr, err = s3.NewS3FileReader(ctx, uri.Host, strings.TrimLeft(uri.Path, "/"), &aws.Config{Region: aws.String(region)})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open S3 object [%s]: %s\n", fileName, err.Error())
+			os.Exit(1)
+		}
+	case "file":
+		fr, err = local.NewLocalFileReader(uri.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open local file [%s]: %s\n", uri.Path, err.Error())
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown location scheme [%s]\n", uri.Scheme)
+		os.Exit(1)
+	}
+	return fr
+}
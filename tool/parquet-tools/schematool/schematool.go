@@ -99,6 +99,44 @@ func ParquetTypeToGoTypeStr(pT *parquet.Type, cT *parquet.ConvertedType) string
 	return res
 }
 
+//Tags of the logical types without converted type, e.g. ", logicaltype=FLOAT16"
+func LogicalTypeTagStr(se *parquet.SchemaElement) string {
+	lT := se.LogicalType
+	if lT == nil || se.ConvertedType != nil {
+		return ""
+	}
+	timeTag := func(name string, adjustedToUTC bool, unit *parquet.TimeUnit) string {
+		unitStr := "MICROS"
+		if unit.IsSetMILLIS() {
+			unitStr = "MILLIS"
+		} else if unit.IsSetNANOS() {
+			unitStr = "NANOS"
+		}
+		return fmt.Sprintf(", logicaltype=%s, logicaltype.isadjustedtoutc=%t, logicaltype.unit=%s", name, adjustedToUTC, unitStr)
+	}
+	switch {
+	case lT.IsSetFLOAT16():
+		return ", logicaltype=FLOAT16"
+	case lT.IsSetUNKNOWN():
+		return ", logicaltype=UNKNOWN"
+	case lT.IsSetUUID():
+		return ", logicaltype=UUID"
+	case lT.IsSetTIME():
+		return timeTag("TIME", lT.TIME.IsAdjustedToUTC, lT.TIME.Unit)
+	case lT.IsSetTIMESTAMP():
+		return timeTag("TIMESTAMP", lT.TIMESTAMP.IsAdjustedToUTC, lT.TIMESTAMP.Unit)
+	}
+	return ""
+}
+
+//Tag of the field_id, e.g. ", fieldid=3" for the key "fieldid"
+func FieldIDTagStr(se *parquet.SchemaElement, key string) string {
+	if !se.IsSetFieldID() {
+		return ""
+	}
+	return fmt.Sprintf(", %s=%d", key, se.GetFieldID())
+}
+
 type Node struct {
 	Indent   string
 	SE       *parquet.SchemaElement
@@ -123,6 +161,7 @@ func (n *Node) OutputJsonSchema() string {
 	} else if n.SE.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
 		rTStr = "REPEATED"
 	}
+	rTStr += FieldIDTagStr(n.SE, "fieldid")
 
 	pTStr, cTStr := ParquetTypeToParquetTypeStr(pT, cT)
 	tagStr := "\"name=%s, type=%s, repetitiontype=%s\""
@@ -132,8 +171,8 @@ func (n *Node) OutputJsonSchema() string {
 	if len(n.Children) == 0 {
 		if *pT == parquet.Type_FIXED_LEN_BYTE_ARRAY && cT == nil {
 			length := n.SE.GetTypeLength()
-			tagStr = "\"name=%s, type=%s, length=%d, repetitiontype=%s\""
-			res += fmt.Sprintf(tagStr, name, pTStr, length, rTStr) + "}"
+			tagStr = "\"name=%s, type=%s, length=%d%s, repetitiontype=%s\""
+			res += fmt.Sprintf(tagStr, name, pTStr, length, LogicalTypeTagStr(n.SE), rTStr) + "}"
 
 		} else if cT != nil && *cT == parquet.ConvertedType_DECIMAL {
 			scale, precision := n.SE.GetScale(), n.SE.GetPrecision()
@@ -152,7 +191,8 @@ func (n *Node) OutputJsonSchema() string {
 				res += fmt.Sprintf(tagStr, name, pTStr, cTStr, rTStr) + "}"
 
 			} else {
-				res += fmt.Sprintf(tagStr, name, pTStr, rTStr) + "}"
+				tagStr := "\"name=%s, type=%s%s, repetitiontype=%s\""
+				res += fmt.Sprintf(tagStr, name, pTStr, LogicalTypeTagStr(n.SE), rTStr) + "}"
 			}
 
 		}
@@ -207,6 +247,7 @@ func (n *Node) getStructTags() string {
 	} else if n.SE.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
 		rTStr = "REPEATED"
 	}
+	rTStr += FieldIDTagStr(n.SE, "fieldid")
 
 	pT, cT := n.SE.Type, n.SE.ConvertedType
 	pTStr, cTStr := ParquetTypeToParquetTypeStr(pT, cT)
@@ -218,22 +259,26 @@ func (n *Node) getStructTags() string {
 
 	if pT == nil && cT == nil {
 		tags = fmt.Sprintf("`parquet:\"name=%s, repetitiontype=%s\"`", n.SE.Name, rTStr)
+	} else if cT == nil && *pT != parquet.Type_FIXED_LEN_BYTE_ARRAY {
+		tags = fmt.Sprintf("`parquet:\"name=%s, type=%s%s, repetitiontype=%s\"`", n.SE.Name, typeStr, LogicalTypeTagStr(n.SE), rTStr)
 	} else if cT != nil && *cT == parquet.ConvertedType_MAP && n.Children != nil {
 		keyNode := n.Children[0].Children[0]
 		keyTypeStr := GetTypeStr(keyNode.SE.Type, keyNode.SE.ConvertedType)
 		valNode := n.Children[0].Children[1]
 		valTypeStr := GetTypeStr(valNode.SE.Type, valNode.SE.ConvertedType)
+		rTStr += FieldIDTagStr(keyNode.SE, "keyfieldid") + FieldIDTagStr(valNode.SE, "valuefieldid")
 		tags = fmt.Sprintf("`parquet:\"name=%s, type=MAP, repetitiontype=%s, keytype=%s, valuetype=%s\"`", n.SE.Name, rTStr, keyTypeStr, valTypeStr)
 
 	} else if cT != nil && *cT == parquet.ConvertedType_LIST && n.Children != nil {
 		cNode := n.Children[0].Children[0]
 		valTypeStr := GetTypeStr(cNode.SE.Type, cNode.SE.ConvertedType)
+		rTStr += FieldIDTagStr(cNode.SE, "valuefieldid")
 		tags = fmt.Sprintf("`parquet:\"name=%s, type=LIST, repetitiontype=%s, valuetype=%s\"`", n.SE.Name, rTStr, valTypeStr)
 
 	} else if *pT == parquet.Type_FIXED_LEN_BYTE_ARRAY && cT == nil {
 		length := n.SE.GetTypeLength()
-		tagStr := "`parquet:\"name=%s, type=%s, length=%d, repetitiontype=%s\"`"
-		tags = fmt.Sprintf(tagStr, n.SE.Name, pTStr, length, rTStr)
+		tagStr := "`parquet:\"name=%s, type=%s, length=%d%s, repetitiontype=%s\"`"
+		tags = fmt.Sprintf(tagStr, n.SE.Name, pTStr, length, LogicalTypeTagStr(n.SE), rTStr)
 	} else if cT != nil && *cT == parquet.ConvertedType_DECIMAL {
 		scale, precision := n.SE.GetScale(), n.SE.GetPrecision()
 		if *pT == parquet.Type_FIXED_LEN_BYTE_ARRAY {
@@ -326,6 +371,16 @@ type SchemaTree struct {
 }
 
 func CreateSchemaTree(schemas []*parquet.SchemaElement) *SchemaTree {
+	//the field_id 0 of all the elements written by older versions isn't a field_id
+	if schema.LegacyFieldIDs(schemas) {
+		withoutFieldIDs := make([]*parquet.SchemaElement, len(schemas))
+		for i, se := range schemas {
+			copied := *se
+			copied.FieldID = nil
+			withoutFieldIDs[i] = &copied
+		}
+		schemas = withoutFieldIDs
+	}
 	pos := 0
 	stack := make([]*Node, 0)
 	root := NewNode(schemas[0])
@@ -0,0 +1,57 @@
+package schematool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+func TestFieldIDTags(t *testing.T) {
+	type Address struct {
+		City string `parquet:"name=city, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=11"`
+		Zip  int32  `parquet:"name=zip, type=INT32"`
+	}
+	type Entry struct {
+		Id      int64            `parquet:"name=id, type=INT64, fieldid=0"`
+		Address *Address         `parquet:"name=address, fieldid=2"`
+		Tags    []string         `parquet:"name=tags, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8, fieldid=3, valuefieldid=31"`
+		Scores  map[string]int32 `parquet:"name=scores, type=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=INT32, fieldid=4, keyfieldid=41, valuefieldid=42"`
+		Note    string           `parquet:"name=note, type=BYTE_ARRAY, convertedtype=UTF8"`
+	}
+	sh, err := schema.NewSchemaHandlerFromStruct(new(Entry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the field ids are kept by the JSON schema
+	sh2, err := schema.NewSchemaHandlerFromJSON(CreateSchemaTree(sh.SchemaElements).OutputJsonSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sh.SchemaElements) != len(sh2.SchemaElements) {
+		t.Fatalf("expect %v elements, get %v", len(sh.SchemaElements), len(sh2.SchemaElements))
+	}
+	for i, se := range sh.SchemaElements {
+		se2 := sh2.SchemaElements[i]
+		if se.IsSetFieldID() != se2.IsSetFieldID() || se.GetFieldID() != se2.GetFieldID() {
+			t.Errorf("%v: expect field_id %v, get %v", se.GetName(), se.FieldID, se2.FieldID)
+		}
+	}
+
+	structStr := CreateSchemaTree(sh.SchemaElements).OutputStruct(true)
+	for _, tag := range []string{"repetitiontype=REQUIRED, fieldid=0", "repetitiontype=REQUIRED, fieldid=4, keyfieldid=41, valuefieldid=42"} {
+		if !strings.Contains(structStr, tag) {
+			t.Errorf("expect %v in\n%v", tag, structStr)
+		}
+	}
+
+	//all the field_ids are 0 in the files of older versions, they aren't field_ids
+	for _, se := range sh.SchemaElements {
+		fieldID := int32(0)
+		se.FieldID = &fieldID
+	}
+	if structStr = CreateSchemaTree(sh.SchemaElements).OutputStruct(true); strings.Contains(structStr, "fieldid") {
+		t.Errorf("expect no field_ids in\n%v", structStr)
+	}
+}
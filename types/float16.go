@@ -0,0 +1,33 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//Float16 is the go type of the FLOAT16 logical type, a FIXED_LEN_BYTE_ARRAY(2) of the IEEE 754
+//half precision float in little endian. The value is rounded to the nearest half precision float when it's written.
+type Float16 float32
+
+func (f Float16) MarshalParquetValue() (interface{}, error) {
+	return common.Float32ToFLOAT16(float32(f)), nil
+}
+
+func (f *Float16) UnmarshalParquetValue(val interface{}) error {
+	if val == nil {
+		*f = 0
+		return nil
+	}
+	s, ok := val.(string)
+	if !ok || len(s) != 2 {
+		return fmt.Errorf("invalid FLOAT16 value %v", val)
+	}
+	*f = Float16(common.FLOAT16ToFloat32(s))
+	return nil
+}
+
+func isFloat16Type(schema *parquet.SchemaElement) bool {
+	return schema.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY && schema.LogicalType != nil && schema.LogicalType.IsSetFLOAT16()
+}
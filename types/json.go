@@ -0,0 +1,235 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+const (
+	jsonDateLayout      = "2006-01-02"
+	jsonTimeLayout      = "15:04:05.999999999"
+	jsonTimestampLayout = "2006-01-02T15:04:05.999999999"
+)
+
+//Columns of strings, which are written to JSON as text
+func isStringType(schema *parquet.SchemaElement) bool {
+	//the default of ConvertedType is UTF8
+	if schema.IsSetConvertedType() {
+		switch schema.GetConvertedType() {
+		case parquet.ConvertedType_UTF8, parquet.ConvertedType_ENUM, parquet.ConvertedType_JSON:
+			return true
+		}
+	}
+	lT := schema.LogicalType
+	return lT != nil && (lT.IsSetSTRING() || lT.IsSetENUM() || lT.IsSetJSON())
+}
+
+func isUUIDType(schema *parquet.SchemaElement) bool {
+	return schema.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY && schema.LogicalType != nil && schema.LogicalType.IsSetUUID()
+}
+
+//BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY columns which are not strings, decimals, UUIDs or FLOAT16s
+func IsBinaryType(schema *parquet.SchemaElement) bool {
+	pT := schema.GetType()
+	if pT != parquet.Type_BYTE_ARRAY && pT != parquet.Type_FIXED_LEN_BYTE_ARRAY {
+		return false
+	}
+	_, _, isDecimal := DecimalScale(schema)
+	return !isDecimal && !isStringType(schema) && !isUUIDType(schema) && !isFloat16Type(schema)
+}
+
+func isUnsignedType(schema *parquet.SchemaElement) bool {
+	switch schema.GetConvertedType() {
+	case parquet.ConvertedType_UINT_8, parquet.ConvertedType_UINT_16, parquet.ConvertedType_UINT_32, parquet.ConvertedType_UINT_64:
+		return true
+	}
+	lT := schema.LogicalType
+	return lT != nil && lT.IsSetINTEGER() && !lT.INTEGER.IsSigned
+}
+
+//Decimal string of the unscaled value
+func decimalToString(unscaled *big.Int, scale int32) string {
+	if scale <= 0 {
+		return new(big.Int).Mul(unscaled, pow10(-scale)).String()
+	}
+	return new(big.Rat).SetFrac(unscaled, pow10(scale)).FloatString(int(scale))
+}
+
+func floatToJSON(v float64, bitSize int) interface{} {
+	if math.IsNaN(v) {
+		return "NaN"
+	} else if math.IsInf(v, 1) {
+		return "+Inf"
+	} else if math.IsInf(v, -1) {
+		return "-Inf"
+	}
+	return json.Number(strconv.FormatFloat(v, 'g', -1, bitSize))
+}
+
+func uuidToString(s string) string {
+	h := hex.EncodeToString([]byte(s))
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+/*
+ParquetTypeToJSONType converts the parquet value of the column to a value for encoding/json:
+nil, bool, json.Number or string. Logical types are formatted as text:
+DATE as 2006-01-02, TIME as 15:04:05.999999999, TIMESTAMP and INT96 as RFC3339 (without the zone if not adjusted to UTC),
+DECIMAL as decimal string, UUID as 36 chars text, and binary as base64. FLOAT16 is a number.
+NaN and infinities are strings.
+*/
+func ParquetTypeToJSONType(val interface{}, schema *parquet.SchemaElement) (interface{}, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	if kind, _, adjustedToUTC := timeInfo(schema); kind != noTime {
+		t, err := parquetTypeToTime(val, schema)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case kind == dateTime:
+			return t.Format(jsonDateLayout), nil
+		case kind == timeOfDay:
+			return t.Format(jsonTimeLayout), nil
+		case adjustedToUTC:
+			return t.UTC().Format(time.RFC3339Nano), nil
+		}
+		return t.Format(jsonTimestampLayout), nil
+	}
+
+	if scale, _, ok := DecimalScale(schema); ok {
+		unscaled, err := parquetTypeToDecimal(val)
+		if err != nil {
+			return nil, err
+		}
+		return decimalToString(unscaled, scale), nil
+	}
+
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	case int32:
+		if isUnsignedType(schema) {
+			return json.Number(strconv.FormatUint(uint64(uint32(v)), 10)), nil
+		}
+		return json.Number(strconv.FormatInt(int64(v), 10)), nil
+	case int64:
+		if isUnsignedType(schema) {
+			return json.Number(strconv.FormatUint(uint64(v), 10)), nil
+		}
+		return json.Number(strconv.FormatInt(v, 10)), nil
+	case float32:
+		return floatToJSON(float64(v), 32), nil
+	case float64:
+		return floatToJSON(v, 64), nil
+	case string:
+		if isUUIDType(schema) && len(v) == 16 {
+			return uuidToString(v), nil
+		} else if isFloat16Type(schema) && len(v) == 2 {
+			return floatToJSON(float64(common.FLOAT16ToFloat32(v)), 32), nil
+		} else if IsBinaryType(schema) {
+			return base64.StdEncoding.EncodeToString([]byte(v)), nil
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("can't convert %T of column %v to JSON", val, schema.GetName())
+}
+
+func parseJSONTime(s string, schema *parquet.SchemaElement) (interface{}, error) {
+	kind, _, _ := timeInfo(schema)
+	var (
+		t   time.Time
+		err error
+	)
+	switch kind {
+	case dateTime:
+		t, err = time.Parse(jsonDateLayout, s)
+	case timeOfDay:
+		t, err = time.Parse(jsonTimeLayout, s)
+	default:
+		if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			t, err = time.Parse(jsonTimestampLayout, s)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return timeToParquetType(t, schema)
+}
+
+/*
+JSONValueToParquetType converts a decoded JSON value to the parquet value of the column.
+Besides the formats of JSONTypeToParquetType, it reads the text formats of ParquetTypeToJSONType:
+time strings, decimal strings without losing precision, UUID text and FLOAT16 numbers.
+Binary columns are decoded from base64 if base64Binary is set.
+*/
+func JSONValueToParquetType(val reflect.Value, schema *parquet.SchemaElement, base64Binary bool) (interface{}, error) {
+	if val.Type().Kind() == reflect.Interface && val.IsNil() {
+		return nil, nil
+	}
+	s, isString := val.Interface().(string)
+
+	if kind, _, _ := timeInfo(schema); kind != noTime && isString {
+		return parseJSONTime(s, schema)
+	}
+
+	if _, _, ok := DecimalScale(schema); ok {
+		num, ok := new(big.Rat).SetString(fmt.Sprintf("%v", val))
+		if !ok {
+			return nil, fmt.Errorf("invalid decimal %v of column %v", val, schema.GetName())
+		}
+		return GoTypeToParquetType(num, schema)
+	}
+
+	if isString && isUUIDType(schema) && len(s) == 36 {
+		bs, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+		if err != nil || len(bs) != 16 {
+			return nil, fmt.Errorf("invalid UUID %v of column %v", s, schema.GetName())
+		}
+		return string(bs), nil
+	}
+
+	if isFloat16Type(schema) {
+		//numbers, or the strings of NaN and infinities
+		f, err := strconv.ParseFloat(fmt.Sprintf("%v", val), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FLOAT16 %v of column %v", val, schema.GetName())
+		}
+		return common.Float32ToFLOAT16(float32(f)), nil
+	}
+
+	if pT := schema.GetType(); !isString && isUnsignedType(schema) && (pT == parquet.Type_INT32 || pT == parquet.Type_INT64) {
+		n, err := strconv.ParseUint(fmt.Sprintf("%v", val), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if pT == parquet.Type_INT32 {
+			return int32(uint32(n)), nil
+		}
+		return int64(n), nil
+	}
+
+	if isString && base64Binary && IsBinaryType(schema) {
+		bs, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 value of column %v: %v", schema.GetName(), err)
+		}
+		return string(bs), nil
+	}
+
+	return JSONTypeToParquetType(val, schema.Type, schema.ConvertedType, int(schema.GetTypeLength()), int(schema.GetScale()))
+}
+
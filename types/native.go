@@ -0,0 +1,393 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	bigRatType   = reflect.TypeOf(big.Rat{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+
+	valueMarshalerType   = reflect.TypeOf((*ParquetValueMarshaler)(nil)).Elem()
+	valueUnmarshalerType = reflect.TypeOf((*ParquetValueUnmarshaler)(nil)).Elem()
+)
+
+//ParquetValueMarshaler is implemented by the types which store themselves as a parquet primitive value.
+//The returned value must be one of the go types of the column's primitive type, or nil for null.
+type ParquetValueMarshaler interface {
+	MarshalParquetValue() (interface{}, error)
+}
+
+//ParquetValueUnmarshaler is implemented by the types which read themselves from a parquet primitive value.
+//It's called on a pointer to a zero value, val is nil for null.
+type ParquetValueUnmarshaler interface {
+	UnmarshalParquetValue(val interface{}) error
+}
+
+//Types implementing ParquetValueMarshaler or ParquetValueUnmarshaler, with value or pointer receivers
+func isValueMarshalerType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+	pt := reflect.PtrTo(t)
+	return t.Implements(valueMarshalerType) || pt.Implements(valueMarshalerType) ||
+		pt.Implements(valueUnmarshalerType)
+}
+
+//Go types which are converted automatically by marshal/unmarshal:
+//time.Time for DATE/TIME/TIMESTAMP/INT96, time.Duration for TIME,
+//big.Rat and big.Int for DECIMAL and [N]byte for FIXED_LEN_BYTE_ARRAY (e.g. UUID).
+//time.Time, big.Rat and big.Int are structs, but they are stored in one column.
+//The types implementing ParquetValueMarshaler or ParquetValueUnmarshaler are stored in one column too.
+func IsNativeType(t reflect.Type) bool {
+	return isValueMarshalerType(t) || t == timeType || t == durationType || t == bigRatType || t == bigIntType ||
+		(t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8)
+}
+
+type timeKind int
+
+const (
+	noTime timeKind = iota
+	dateTime
+	timeOfDay
+	timestampTime
+	int96Time
+)
+
+func timeUnit(unit *parquet.TimeUnit) time.Duration {
+	if unit != nil && unit.IsSetMILLIS() {
+		return time.Millisecond
+	} else if unit != nil && unit.IsSetNANOS() {
+		return time.Nanosecond
+	}
+	return time.Microsecond
+}
+
+//Get how the column stores time values
+func timeInfo(schema *parquet.SchemaElement) (kind timeKind, unit time.Duration, adjustedToUTC bool) {
+	if schema.GetType() == parquet.Type_INT96 {
+		return int96Time, time.Nanosecond, true
+	}
+	if lT := schema.LogicalType; lT != nil {
+		if lT.IsSetDATE() {
+			return dateTime, 0, true
+		} else if lT.IsSetTIMESTAMP() {
+			return timestampTime, timeUnit(lT.TIMESTAMP.Unit), lT.TIMESTAMP.IsAdjustedToUTC
+		} else if lT.IsSetTIME() {
+			return timeOfDay, timeUnit(lT.TIME.Unit), lT.TIME.IsAdjustedToUTC
+		}
+	}
+	if schema.IsSetConvertedType() {
+		switch schema.GetConvertedType() {
+		case parquet.ConvertedType_DATE:
+			return dateTime, 0, true
+		case parquet.ConvertedType_TIMESTAMP_MILLIS:
+			return timestampTime, time.Millisecond, true
+		case parquet.ConvertedType_TIMESTAMP_MICROS:
+			return timestampTime, time.Microsecond, true
+		case parquet.ConvertedType_TIME_MILLIS:
+			return timeOfDay, time.Millisecond, true
+		case parquet.ConvertedType_TIME_MICROS:
+			return timeOfDay, time.Microsecond, true
+		}
+	}
+	return noTime, 0, false
+}
+
+//DecimalScale returns the scale and the precision of a DECIMAL column, from the logical type or the converted type.
+//ok is false if the column isn't DECIMAL.
+func DecimalScale(schema *parquet.SchemaElement) (scale int32, precision int32, ok bool) {
+	if lT := schema.LogicalType; lT != nil && lT.IsSetDECIMAL() {
+		return lT.DECIMAL.Scale, lT.DECIMAL.Precision, true
+	}
+	if schema.GetConvertedType() == parquet.ConvertedType_DECIMAL {
+		return schema.GetScale(), schema.GetPrecision(), true
+	}
+	return 0, 0, false
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+//Wall clock of t as UTC, which is used for the values not adjusted to UTC
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+//Divide and round toward negative infinity
+func floorDiv(a int64, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func durationToParquetType(d time.Duration, unit time.Duration, pT parquet.Type) interface{} {
+	if pT == parquet.Type_INT32 {
+		return int32(d / unit)
+	}
+	return int64(d / unit)
+}
+
+func timeToParquetType(t time.Time, schema *parquet.SchemaElement) (interface{}, error) {
+	kind, unit, adjustedToUTC := timeInfo(schema)
+	if kind == dateTime {
+		//the calendar date in the location of t
+		return int32(floorDiv(wallClock(t).Unix(), 24*3600)), nil
+	}
+	if adjustedToUTC {
+		t = t.UTC()
+	} else {
+		t = wallClock(t)
+	}
+
+	switch kind {
+	case int96Time:
+		return TimeToINT96(t), nil
+
+	case timeOfDay:
+		h, m, s := t.Clock()
+		d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(t.Nanosecond())
+		return durationToParquetType(d, unit, schema.GetType()), nil
+
+	case timestampTime:
+		sec, nsec := t.Unix(), int64(t.Nanosecond())
+		perSec := int64(time.Second / unit)
+		return sec*perSec + nsec/int64(unit), nil
+	}
+	return nil, fmt.Errorf("can't store time.Time in column %v without DATE, TIME, TIMESTAMP or INT96 type", schema.GetName())
+}
+
+//Unscaled decimal value to parquet type
+func decimalToParquetType(unscaled *big.Int, schema *parquet.SchemaElement, precision int32) (interface{}, error) {
+	if precision > 0 && new(big.Int).Abs(unscaled).Cmp(pow10(precision)) >= 0 {
+		return nil, fmt.Errorf("decimal %v overflows precision %v of column %v", unscaled, precision, schema.GetName())
+	}
+
+	switch schema.GetType() {
+	case parquet.Type_INT32:
+		if !unscaled.IsInt64() || unscaled.Int64() != int64(int32(unscaled.Int64())) {
+			return nil, fmt.Errorf("decimal %v overflows INT32 column %v", unscaled, schema.GetName())
+		}
+		return int32(unscaled.Int64()), nil
+
+	case parquet.Type_INT64:
+		if !unscaled.IsInt64() {
+			return nil, fmt.Errorf("decimal %v overflows INT64 column %v", unscaled, schema.GetName())
+		}
+		return unscaled.Int64(), nil
+
+	case parquet.Type_BYTE_ARRAY, parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		length := 0
+		if schema.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY {
+			length = int(schema.GetTypeLength())
+		}
+		bs := StrIntToBinary(unscaled.String(), "BigEndian", length, true)
+		if length > 0 && bigIntFromBytes([]byte(bs)).Cmp(unscaled) != 0 {
+			return nil, fmt.Errorf("decimal %v overflows %v bytes of column %v", unscaled, length, schema.GetName())
+		}
+		return bs, nil
+	}
+	return nil, fmt.Errorf("can't store decimal in column %v of type %v", schema.GetName(), schema.GetType())
+}
+
+//Two's complement big endian bytes to big.Int
+func bigIntFromBytes(bs []byte) *big.Int {
+	res := new(big.Int).SetBytes(bs)
+	if len(bs) > 0 && bs[0]&0x80 != 0 {
+		res.Sub(res, new(big.Int).Lsh(big.NewInt(1), uint(len(bs)*8)))
+	}
+	return res
+}
+
+//Convert the go value to the parquet value of the column if it's one of the types of IsNativeType.
+//Other values are returned unchanged.
+func GoTypeToParquetType(src interface{}, schema *parquet.SchemaElement) (interface{}, error) {
+	if src == nil || schema == nil {
+		return src, nil
+	}
+
+	if rv := reflect.ValueOf(src); isValueMarshalerType(rv.Type()) {
+		//use a copy for the methods with pointer receivers
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		if m, ok := ptr.Interface().(ParquetValueMarshaler); ok {
+			return m.MarshalParquetValue()
+		}
+	}
+
+	switch v := src.(type) {
+	case time.Time:
+		return timeToParquetType(v, schema)
+
+	case time.Duration:
+		if kind, unit, _ := timeInfo(schema); kind == timeOfDay {
+			return durationToParquetType(v, unit, schema.GetType()), nil
+		}
+		return int64(v), nil
+
+	case big.Rat:
+		return GoTypeToParquetType(&v, schema)
+
+	case *big.Rat:
+		if v == nil {
+			return nil, nil
+		}
+		scale, precision, ok := DecimalScale(schema)
+		if !ok {
+			return nil, fmt.Errorf("can't store *big.Rat in column %v without DECIMAL type", schema.GetName())
+		}
+		//round half away from zero
+		num := new(big.Int).Mul(v.Num(), pow10(scale))
+		unscaled, rem := new(big.Int).QuoRem(num, v.Denom(), new(big.Int))
+		if rem.Sign() != 0 && new(big.Int).Abs(new(big.Int).Lsh(rem, 1)).Cmp(v.Denom()) >= 0 {
+			unscaled.Add(unscaled, big.NewInt(int64(v.Sign())))
+		}
+		return decimalToParquetType(unscaled, schema, precision)
+
+	case big.Int:
+		return GoTypeToParquetType(&v, schema)
+
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		_, precision, ok := DecimalScale(schema)
+		if !ok {
+			return nil, fmt.Errorf("can't store *big.Int in column %v without DECIMAL type", schema.GetName())
+		}
+		return decimalToParquetType(v, schema, precision)
+	}
+
+	if rv := reflect.ValueOf(src); rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		if schema.GetType() == parquet.Type_FIXED_LEN_BYTE_ARRAY && int(schema.GetTypeLength()) != rv.Len() {
+			return nil, fmt.Errorf("can't store [%v]byte in column %v of length %v", rv.Len(), schema.GetName(), schema.GetTypeLength())
+		}
+		bs := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(bs), rv)
+		return string(bs), nil
+	}
+	return src, nil
+}
+
+func toInt64(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("can't convert %T to integer", val)
+}
+
+func parquetTypeToTime(val interface{}, schema *parquet.SchemaElement) (time.Time, error) {
+	kind, unit, adjustedToUTC := timeInfo(schema)
+	if kind == int96Time {
+		s, ok := val.(string)
+		if !ok || len(s) != 12 {
+			return time.Time{}, fmt.Errorf("invalid INT96 value %v", val)
+		}
+		return INT96ToTime(s), nil
+	}
+
+	n, err := toInt64(val)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var t time.Time
+	switch kind {
+	case dateTime:
+		return time.Unix(n*24*3600, 0).UTC(), nil
+	case timeOfDay:
+		t = time.Unix(0, 0).UTC().Add(time.Duration(n) * unit)
+	case timestampTime:
+		perSec := int64(time.Second / unit)
+		t = time.Unix(floorDiv(n, perSec), (n-floorDiv(n, perSec)*perSec)*int64(unit)).UTC()
+	default:
+		return time.Time{}, fmt.Errorf("can't read time.Time from column %v without DATE, TIME, TIMESTAMP or INT96 type", schema.GetName())
+	}
+	if !adjustedToUTC {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+	}
+	return t, nil
+}
+
+//Unscaled decimal value from parquet type
+func parquetTypeToDecimal(val interface{}) (*big.Int, error) {
+	switch v := val.(type) {
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case string:
+		return bigIntFromBytes([]byte(v)), nil
+	}
+	return nil, fmt.Errorf("can't convert %T to decimal", val)
+}
+
+//Convert the parquet value of the column to the go type t, which is one of the types of IsNativeType.
+func ParquetTypeToGoType(val interface{}, t reflect.Type, schema *parquet.SchemaElement) (reflect.Value, error) {
+	if isValueMarshalerType(t) {
+		ptr := reflect.New(t)
+		if u, ok := ptr.Interface().(ParquetValueUnmarshaler); ok {
+			return ptr.Elem(), u.UnmarshalParquetValue(val)
+		}
+	}
+
+	res := reflect.New(t).Elem()
+	if val == nil {
+		return res, nil
+	}
+
+	switch t {
+	case timeType:
+		v, err := parquetTypeToTime(val, schema)
+		res.Set(reflect.ValueOf(v))
+		return res, err
+
+	case durationType:
+		n, err := toInt64(val)
+		if kind, unit, _ := timeInfo(schema); kind == timeOfDay {
+			n *= int64(unit)
+		}
+		res.SetInt(n)
+		return res, err
+
+	case bigRatType, bigIntType:
+		unscaled, err := parquetTypeToDecimal(val)
+		if err != nil {
+			return res, err
+		}
+		if t == bigIntType {
+			res.Set(reflect.ValueOf(unscaled).Elem())
+			return res, nil
+		}
+		scale, _, _ := DecimalScale(schema)
+		res.Set(reflect.ValueOf(new(big.Rat).SetFrac(unscaled, pow10(scale))).Elem())
+		return res, nil
+	}
+
+	if t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8 {
+		s, ok := val.(string)
+		if !ok || len(s) != t.Len() {
+			return res, fmt.Errorf("can't convert %v to [%v]byte", val, t.Len())
+		}
+		reflect.Copy(res, reflect.ValueOf([]byte(s)))
+		return res, nil
+	}
+	//types only implementing ParquetValueMarshaler, e.g. type Money int64
+	if rv := reflect.ValueOf(val); rv.Type().ConvertibleTo(t) && (rv.Kind() == reflect.String) == (t.Kind() == reflect.String) {
+		res.Set(rv.Convert(t))
+		return res, nil
+	}
+	return res, fmt.Errorf("unsupported type %v", t)
+}
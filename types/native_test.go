@@ -0,0 +1,216 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+func newSchemaElement(pT parquet.Type, cT *parquet.ConvertedType, lT *parquet.LogicalType) *parquet.SchemaElement {
+	se := parquet.NewSchemaElement()
+	se.Name = "col"
+	se.Type = &pT
+	se.ConvertedType = cT
+	se.LogicalType = lT
+	return se
+}
+
+func TestNativeTime(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 891234567, time.UTC)
+	timestampNanos := parquet.NewLogicalType()
+	timestampNanos.TIMESTAMP = &parquet.TimestampType{IsAdjustedToUTC: true, Unit: &parquet.TimeUnit{NANOS: parquet.NewNanoSeconds()}}
+	timeMicros := parquet.NewLogicalType()
+	timeMicros.TIME = &parquet.TimeType{IsAdjustedToUTC: true, Unit: &parquet.TimeUnit{MICROS: parquet.NewMicroSeconds()}}
+
+	testData := []struct {
+		se       *parquet.SchemaElement
+		expected interface{}
+		back     time.Time
+	}{
+		{newSchemaElement(parquet.Type_INT64, parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MILLIS), nil), ts.UnixMilli(), ts.Truncate(time.Millisecond)},
+		{newSchemaElement(parquet.Type_INT64, parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MICROS), nil), ts.UnixMicro(), ts.Truncate(time.Microsecond)},
+		{newSchemaElement(parquet.Type_INT64, nil, timestampNanos), ts.UnixNano(), ts},
+		{newSchemaElement(parquet.Type_INT32, parquet.ConvertedTypePtr(parquet.ConvertedType_DATE), nil), int32(18690), time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{newSchemaElement(parquet.Type_INT32, parquet.ConvertedTypePtr(parquet.ConvertedType_TIME_MILLIS), nil), int32(18367891), time.Date(1970, 1, 1, 5, 6, 7, 891000000, time.UTC)},
+		{newSchemaElement(parquet.Type_INT64, nil, timeMicros), int64(18367891234), time.Date(1970, 1, 1, 5, 6, 7, 891234000, time.UTC)},
+		{newSchemaElement(parquet.Type_INT96, nil, nil), TimeToINT96(ts), ts.Truncate(time.Microsecond)},
+	}
+
+	for _, data := range testData {
+		res, err := GoTypeToParquetType(ts, data.se)
+		if err != nil || res != data.expected {
+			t.Errorf("GoTypeToParquetType err, expect %v, get %v, %v", data.expected, res, err)
+		}
+		back, err := ParquetTypeToGoType(res, reflect.TypeOf(time.Time{}), data.se)
+		if err != nil || !back.Interface().(time.Time).Equal(data.back) {
+			t.Errorf("ParquetTypeToGoType err, expect %v, get %v, %v", data.back, back, err)
+		}
+	}
+
+	//dates before the epoch
+	res, _ := GoTypeToParquetType(time.Date(1969, 12, 31, 23, 0, 0, 0, time.UTC), testData[3].se)
+	if res != int32(-1) {
+		t.Errorf("GoTypeToParquetType err, expect -1, get %v", res)
+	}
+
+	if _, err := GoTypeToParquetType(ts, newSchemaElement(parquet.Type_INT64, nil, nil)); err == nil {
+		t.Errorf("GoTypeToParquetType err, expect error for column without time type")
+	}
+}
+
+func TestNativeDuration(t *testing.T) {
+	se := newSchemaElement(parquet.Type_INT32, parquet.ConvertedTypePtr(parquet.ConvertedType_TIME_MILLIS), nil)
+	d := 5*time.Hour + 1500*time.Millisecond
+	res, err := GoTypeToParquetType(d, se)
+	if err != nil || res != int32(18001500) {
+		t.Errorf("GoTypeToParquetType err, expect 18001500, get %v, %v", res, err)
+	}
+	back, err := ParquetTypeToGoType(res, reflect.TypeOf(d), se)
+	if err != nil || back.Interface() != d {
+		t.Errorf("ParquetTypeToGoType err, expect %v, get %v, %v", d, back, err)
+	}
+}
+
+func TestNativeDecimal(t *testing.T) {
+	decimal := func(pT parquet.Type, length int32) *parquet.SchemaElement {
+		se := newSchemaElement(pT, parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL), nil)
+		scale, precision := int32(2), int32(9)
+		se.Scale, se.Precision, se.TypeLength = &scale, &precision, &length
+		return se
+	}
+
+	testData := []struct {
+		src      interface{}
+		se       *parquet.SchemaElement
+		expected interface{}
+		back     string
+	}{
+		{big.NewRat(-12345, 100), decimal(parquet.Type_INT32, 0), int32(-12345), "-123.45"},
+		{big.NewRat(1, 3), decimal(parquet.Type_INT64, 0), int64(33), "0.33"},
+		{big.NewRat(-5, 1000), decimal(parquet.Type_INT64, 0), int64(-1), "-0.01"},
+		{big.NewRat(-12345, 100), decimal(parquet.Type_BYTE_ARRAY, 0), string([]byte{0xCF, 0xC7}), "-123.45"},
+		{big.NewRat(12345, 100), decimal(parquet.Type_FIXED_LEN_BYTE_ARRAY, 4), string([]byte{0, 0, 0x30, 0x39}), "123.45"},
+		{big.NewInt(-12345), decimal(parquet.Type_FIXED_LEN_BYTE_ARRAY, 4), string([]byte{0xFF, 0xFF, 0xCF, 0xC7}), "-123.45"},
+	}
+
+	for _, data := range testData {
+		res, err := GoTypeToParquetType(data.src, data.se)
+		if err != nil || res != data.expected {
+			t.Errorf("GoTypeToParquetType err, expect %v, get %v, %v", data.expected, res, err)
+		}
+		back, err := ParquetTypeToGoType(res, reflect.TypeOf(big.Rat{}), data.se)
+		if rat := back.Interface().(big.Rat); err != nil || rat.FloatString(2) != data.back {
+			t.Errorf("ParquetTypeToGoType err, expect %v, get %v, %v", data.back, rat.FloatString(2), err)
+		}
+	}
+
+	back, err := ParquetTypeToGoType(string([]byte{0xFF, 0xFF, 0xCF, 0xC7}), reflect.TypeOf(big.Int{}), testData[5].se)
+	if bi := back.Interface().(big.Int); err != nil || bi.Int64() != -12345 {
+		t.Errorf("ParquetTypeToGoType err, expect -12345, get %v, %v", bi.String(), err)
+	}
+
+	if _, err := GoTypeToParquetType(big.NewRat(10000000, 1), decimal(parquet.Type_INT64, 0)); err == nil {
+		t.Errorf("GoTypeToParquetType err, expect error for value overflowing precision")
+	}
+}
+
+func TestNativeByteArray(t *testing.T) {
+	se := newSchemaElement(parquet.Type_FIXED_LEN_BYTE_ARRAY, nil, nil)
+	length := int32(16)
+	se.TypeLength = &length
+	uuid := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+	res, err := GoTypeToParquetType(uuid, se)
+	if err != nil || res != string(uuid[:]) {
+		t.Errorf("GoTypeToParquetType err, expect %v, get %v, %v", uuid, res, err)
+	}
+	back, err := ParquetTypeToGoType(res, reflect.TypeOf(uuid), se)
+	if err != nil || back.Interface() != uuid {
+		t.Errorf("ParquetTypeToGoType err, expect %v, get %v, %v", uuid, back, err)
+	}
+
+	if _, err := GoTypeToParquetType([4]byte{}, se); err == nil {
+		t.Errorf("GoTypeToParquetType err, expect error for wrong length")
+	}
+}
+
+type testMoney struct {
+	Cents int64
+}
+
+func (m testMoney) MarshalParquetValue() (interface{}, error) {
+	return m.Cents, nil
+}
+
+func (m *testMoney) UnmarshalParquetValue(val interface{}) error {
+	m.Cents, _ = val.(int64)
+	return nil
+}
+
+type testColor int
+
+func (c *testColor) MarshalParquetValue() (interface{}, error) {
+	if *c < 0 {
+		return nil, fmt.Errorf("invalid color %d", *c)
+	}
+	return []string{"red", "green"}[*c], nil
+}
+
+type testCelsius float64
+
+func (c testCelsius) MarshalParquetValue() (interface{}, error) {
+	return float64(c), nil
+}
+
+func TestValueMarshaler(t *testing.T) {
+	se := newSchemaElement(parquet.Type_INT64, nil, nil)
+	for _, typ := range []reflect.Type{reflect.TypeOf(testMoney{}), reflect.TypeOf(testColor(0)), reflect.TypeOf(testCelsius(0))} {
+		if !IsNativeType(typ) || IsNativeType(reflect.PtrTo(typ)) {
+			t.Errorf("IsNativeType err, expect true for %v only", typ)
+		}
+	}
+
+	res, err := GoTypeToParquetType(testMoney{Cents: -123}, se)
+	if err != nil || res != int64(-123) {
+		t.Errorf("GoTypeToParquetType err, expect -123, get %v, %v", res, err)
+	}
+	back, err := ParquetTypeToGoType(res, reflect.TypeOf(testMoney{}), se)
+	if err != nil || back.Interface() != (testMoney{Cents: -123}) {
+		t.Errorf("ParquetTypeToGoType err, expect {-123}, get %v, %v", back, err)
+	}
+
+	//pointer receiver
+	res, err = GoTypeToParquetType(testColor(1), se)
+	if err != nil || res != "green" {
+		t.Errorf("GoTypeToParquetType err, expect green, get %v, %v", res, err)
+	}
+	if _, err = GoTypeToParquetType(testColor(-1), se); err == nil {
+		t.Errorf("GoTypeToParquetType err, expect error from MarshalParquetValue")
+	}
+
+	//no ParquetValueUnmarshaler, the value is converted
+	back, err = ParquetTypeToGoType(float64(21.5), reflect.TypeOf(testCelsius(0)), se)
+	if err != nil || back.Interface() != testCelsius(21.5) {
+		t.Errorf("ParquetTypeToGoType err, expect 21.5, get %v, %v", back, err)
+	}
+	if _, err = ParquetTypeToGoType("green", reflect.TypeOf(testColor(0)), se); err == nil {
+		t.Errorf("ParquetTypeToGoType err, expect error for string to int")
+	}
+}
+
+func TestFloat16(t *testing.T) {
+	lT := parquet.NewLogicalType()
+	lT.FLOAT16 = parquet.NewFloat16Type()
+	se := newSchemaElement(parquet.Type_FIXED_LEN_BYTE_ARRAY, nil, lT)
+	if res, err := ParquetTypeToText(common.Float32ToFLOAT16(-1.5), se, ""); err != nil || res != "-1.5" {
+		t.Errorf("expect -1.5, get %v %v", res, err)
+	}
+	if res, err := TextToParquetType("0.5", se, ""); err != nil || res != common.Float32ToFLOAT16(0.5) {
+		t.Errorf("expect 0.5, get %v %v", res, err)
+	}
+}
@@ -0,0 +1,139 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+//ParquetTypeToText formats the parquet value of the column as ParquetTypeToJSONType does, as text.
+//TIMESTAMP and INT96 values are formatted with timestampLayout if it's not empty.
+func ParquetTypeToText(val interface{}, schema *parquet.SchemaElement, timestampLayout string) (string, error) {
+	if kind, _, adjustedToUTC := timeInfo(schema); val != nil && timestampLayout != "" && (kind == timestampTime || kind == int96Time) {
+		t, err := parquetTypeToTime(val, schema)
+		if err != nil {
+			return "", err
+		}
+		if adjustedToUTC {
+			t = t.UTC()
+		}
+		return t.Format(timestampLayout), nil
+	}
+
+	res, err := ParquetTypeToJSONType(val, schema)
+	if err != nil || res == nil {
+		return "", err
+	}
+	return fmt.Sprint(res), nil
+}
+
+func intBitSize(schema *parquet.SchemaElement) int {
+	if schema.IsSetConvertedType() {
+		switch schema.GetConvertedType() {
+		case parquet.ConvertedType_INT_8, parquet.ConvertedType_UINT_8:
+			return 8
+		case parquet.ConvertedType_INT_16, parquet.ConvertedType_UINT_16:
+			return 16
+		}
+	}
+	if lT := schema.LogicalType; lT != nil && lT.IsSetINTEGER() && lT.INTEGER.BitWidth > 0 {
+		return int(lT.INTEGER.BitWidth)
+	}
+	if schema.GetType() == parquet.Type_INT32 {
+		return 32
+	}
+	return 64
+}
+
+/*
+TextToParquetType parses the text of a value to the parquet value of the column, it's the inverse of ParquetTypeToText
+except for binary values, which are the raw text. The text must be a whole value,
+e.g. "12abc" isn't an integer. Times are parsed with the layouts of ParquetTypeToJSONType,
+or timestampLayout for TIMESTAMP and INT96 if it's not empty.
+*/
+func TextToParquetType(s string, schema *parquet.SchemaElement, timestampLayout string) (interface{}, error) {
+	pT := schema.GetType()
+	if pT != parquet.Type_BYTE_ARRAY && pT != parquet.Type_FIXED_LEN_BYTE_ARRAY {
+		s = strings.TrimSpace(s)
+	}
+
+	if kind, _, _ := timeInfo(schema); kind != noTime {
+		if kind == int96Time {
+			if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return StrIntToBinary(s, "LittleEndian", 12, true), nil
+			}
+		}
+		if timestampLayout != "" && (kind == timestampTime || kind == int96Time) {
+			t, err := time.Parse(timestampLayout, s)
+			if err != nil {
+				return nil, err
+			}
+			return timeToParquetType(t, schema)
+		}
+		//the numbers of the time units
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil && kind != int96Time {
+			if pT == parquet.Type_INT32 {
+				return int32(n), nil
+			}
+			return n, nil
+		}
+		return parseJSONTime(s, schema)
+	}
+
+	if _, _, ok := DecimalScale(schema); ok {
+		num, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("invalid decimal %q", s)
+		}
+		return GoTypeToParquetType(num, schema)
+	}
+
+	switch pT {
+	case parquet.Type_BOOLEAN:
+		return strconv.ParseBool(s)
+
+	case parquet.Type_INT32, parquet.Type_INT64:
+		var (
+			n   int64
+			err error
+		)
+		if isUnsignedType(schema) {
+			var u uint64
+			u, err = strconv.ParseUint(s, 10, intBitSize(schema))
+			n = int64(u)
+		} else {
+			n, err = strconv.ParseInt(s, 10, intBitSize(schema))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if pT == parquet.Type_INT32 {
+			return int32(n), nil
+		}
+		return n, nil
+
+	case parquet.Type_FLOAT:
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+
+	case parquet.Type_DOUBLE:
+		return strconv.ParseFloat(s, 64)
+
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		if isUUIDType(schema) || isFloat16Type(schema) {
+			return JSONValueToParquetType(reflect.ValueOf(s), schema, false)
+		}
+		if schema.GetConvertedType() == parquet.ConvertedType_INTERVAL && schema.IsSetConvertedType() {
+			return StrIntToBinary(s, "LittleEndian", 12, false), nil
+		}
+		if len(s) != int(schema.GetTypeLength()) {
+			return nil, fmt.Errorf("length of %q isn't %v", s, schema.GetTypeLength())
+		}
+	}
+	return s, nil
+}
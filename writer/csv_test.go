@@ -1,9 +1,14 @@
 package writer
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/reader"
 )
 
 func BenchmarkWriteCSV(b *testing.B) {
@@ -67,3 +72,109 @@ func BenchmarkWriteCSVPlainDictionary(b *testing.B) {
 		fw.Close()
 	}
 }
+
+func TestConvertCSV(t *testing.T) {
+	input := "name,age,score,active,birthday,seen\n" +
+		"a,38,1.5,true,2000-01-02,2021-03-04T05:06:07Z\n" +
+		"b,,2,FALSE,,\n" +
+		"c,7,NaN,true,2001-02-03,2021-03-04T05:06:07.5Z\n"
+
+	var buf bytes.Buffer
+	n, err := ConvertCSV(strings.NewReader(input), writerfile.NewWriterFile(&buf), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	cr, err := reader.NewCSVReader(pf, 1)
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	_, err = cr.ReadCSV(&out, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "name,age,score,active,birthday,seen\n"+
+		"a,38,1.5,true,2000-01-02,2021-03-04T05:06:07Z\n"+
+		"b,,2,false,,\n"+
+		"c,7,NaN,true,2001-02-03,2021-03-04T05:06:07.5Z\n", out.String())
+}
+
+func TestConvertCSVTimestampFormat(t *testing.T) {
+	input := "seen\n2021-03-04 05:06:07\n\n2021-03-04 05:06:08\n"
+	var buf bytes.Buffer
+	n, err := ConvertCSV(strings.NewReader(input), writerfile.NewWriterFile(&buf), 1,
+		WithCSVInputTimestampFormat("2006-01-02 15:04:05"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	//the column is inferred as TIMESTAMP with the layout
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	cr, err := reader.NewCSVReader(pf, 1)
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	_, err = cr.ReadCSV(&out, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "seen\n2021-03-04T05:06:07Z\n2021-03-04T05:06:08Z\n", out.String())
+}
+
+func TestConvertCSVRowErrors(t *testing.T) {
+	md := []string{
+		"name=Name, type=BYTE_ARRAY, convertedtype=UTF8",
+		"name=Age, type=INT32, repetitiontype=OPTIONAL",
+	}
+	input := "Name,Age\n" +
+		"a,1\n" +
+		"b,12abc\n" +
+		"NA,2\n" +
+		"c\n" +
+		"d,\"3\n" +
+		"e,NA\n"
+
+	var (
+		buf    bytes.Buffer
+		errs   []string
+		lines  []int
+		column []int
+	)
+	sink := func(err *CSVRowError) {
+		errs = append(errs, err.Error())
+		lines = append(lines, err.Line)
+		column = append(column, err.Column)
+	}
+	_, err := ConvertCSV(strings.NewReader(input), writerfile.NewWriterFile(&buf), 1,
+		WithCSVMetadata(md), WithCSVNullValues("NA"), WithCSVErrorSink(sink))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4, 5, 6}, lines)
+	assert.Equal(t, []int{2, 1, 0, 0}, column)
+	assert.Contains(t, errs[0], "line 3, column 2 (Age): ")
+	assert.Equal(t, "line 4, column 1 (Name): null value in REQUIRED column", errs[1])
+	assert.Equal(t, "line 5: expect 2 fields, get 1", errs[2])
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	cr, err := reader.NewCSVReader(pf, 1)
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	_, err = cr.ReadCSV(&out, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "Name,Age\na,1\n", out.String())
+
+	//without a sink the first bad row stops the conversion
+	buf.Reset()
+	n, err := ConvertCSV(strings.NewReader(input), writerfile.NewWriterFile(&buf), 1,
+		WithCSVMetadata(md), WithCSVNullValues("NA"))
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), n)
+	rowErr, ok := err.(*CSVRowError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, rowErr.Line)
+
+	//the rows before the error are still written
+	pf, err = buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	cr, err = reader.NewCSVReader(pf, 1)
+	assert.NoError(t, err)
+	out.Reset()
+	_, err = cr.ReadCSV(&out, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "Name,Age\na,1\n", out.String())
+}
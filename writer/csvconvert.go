@@ -0,0 +1,259 @@
+package writer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/types"
+)
+
+//CSVRowError is a bad CSV row, Line and Column are 1-based. Column is 0 if the error isn't in a field.
+type CSVRowError struct {
+	Line   int
+	Column int
+	Name   string
+	Err    error
+}
+
+func (e *CSVRowError) Error() string {
+	if e.Column <= 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d (%s): %v", e.Line, e.Column, e.Name, e.Err)
+}
+
+func (e *CSVRowError) Unwrap() error {
+	return e.Err
+}
+
+type csvConverter struct {
+	metadata        []string
+	sampleSize      int
+	delimiter       rune
+	header          bool
+	nullValues      map[string]bool
+	timestampLayout string
+	errorSink       func(*CSVRowError)
+	writerOpts      []ParquetWriterOption
+}
+
+type CSVConvertOption func(*csvConverter)
+
+//WithCSVMetadata sets the metadata of the columns (see NewCSVWriter) instead of inferring them
+func WithCSVMetadata(md []string) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.metadata = md
+	}
+}
+
+//WithCSVSampleSize sets the number of records to infer the schema from, default is 100
+func WithCSVSampleSize(n int) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.sampleSize = n
+	}
+}
+
+//WithCSVInputDelimiter sets the field delimiter, default is ','
+func WithCSVInputDelimiter(delimiter rune) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.delimiter = delimiter
+	}
+}
+
+//WithCSVInputHeader tells if the first record is the header, default is true.
+//Without the header the columns are named column_1, column_2...
+func WithCSVInputHeader(header bool) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.header = header
+	}
+}
+
+//WithCSVNullValues sets the texts of the null values, default is the empty string
+func WithCSVNullValues(values ...string) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.nullValues = make(map[string]bool)
+		for _, v := range values {
+			c.nullValues[v] = true
+		}
+	}
+}
+
+//WithCSVInputTimestampFormat sets the time layout of the TIMESTAMP and INT96 values, default is RFC3339
+func WithCSVInputTimestampFormat(layout string) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.timestampLayout = layout
+	}
+}
+
+//WithCSVErrorSink skips the bad rows and passes their errors to sink. Without it the first bad row stops the conversion.
+func WithCSVErrorSink(sink func(*CSVRowError)) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.errorSink = sink
+	}
+}
+
+//WithCSVWriterOptions sets the options of the parquet writer
+func WithCSVWriterOptions(opts ...ParquetWriterOption) CSVConvertOption {
+	return func(c *csvConverter) {
+		c.writerOpts = append(c.writerOpts, opts...)
+	}
+}
+
+//A record read from the CSV and the lines of its fields
+type csvRecord struct {
+	fields []string
+	lines  []int
+	err    error
+}
+
+func readCSVRecord(cr *csv.Reader) (*csvRecord, error) {
+	fields, err := cr.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	//a malformed record is a row error, the reader goes on with the next line
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &csvRecord{lines: []int{parseErr.StartLine}, err: parseErr.Err}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	res := &csvRecord{fields: fields, lines: make([]int, len(fields))}
+	for i := range fields {
+		res.lines[i], _ = cr.FieldPos(i)
+	}
+	return res, nil
+}
+
+/*
+ConvertCSV streams the CSV records of r to a parquet file.
+The schema is inferred from the first records by schema.InferMetadataFromCSV, or set by WithCSVMetadata.
+The fields are parsed by types.TextToParquetType, null values in REQUIRED columns are errors.
+It returns the number of written rows. If it stops on an error, the rows written before it are
+flushed and the parquet file is still complete.
+*/
+func ConvertCSV(r io.Reader, pfile source.ParquetFile, np int64, opts ...CSVConvertOption) (int64, error) {
+	c := &csvConverter{
+		sampleSize: 100,
+		delimiter:  ',',
+		header:     true,
+		nullValues: map[string]bool{"": true},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = c.delimiter
+	//the field counts are checked by the converter
+	cr.FieldsPerRecord = -1
+
+	var names []string
+	if c.header {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return 0, errors.New("missing CSV header")
+		} else if err != nil {
+			return 0, err
+		}
+		names = record
+	}
+
+	//records to infer the schema from
+	sample := make([]*csvRecord, 0, c.sampleSize)
+	for len(c.metadata) == 0 && len(sample) < c.sampleSize {
+		record, err := readCSVRecord(cr)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		sample = append(sample, record)
+	}
+
+	md := c.metadata
+	if len(md) == 0 {
+		fields := make([][]string, 0, len(sample))
+		for _, record := range sample {
+			fields = append(fields, record.fields)
+			for len(names) < len(record.fields) {
+				names = append(names, fmt.Sprintf("column_%d", len(names)+1))
+			}
+		}
+		md = schema.InferMetadataFromCSV(names, fields, func(s string) bool { return c.nullValues[s] }, c.timestampLayout)
+	}
+
+	cw, err := NewCSVWriter(md, pfile, np, c.writerOpts...)
+	if err != nil {
+		return 0, err
+	}
+	sh := cw.SchemaHandler
+
+	var numRows int64
+	write := func(record *csvRecord) error {
+		rowErr := &CSVRowError{Err: record.err}
+		if len(record.lines) > 0 {
+			rowErr.Line = record.lines[0]
+		}
+		if rowErr.Err == nil && len(record.fields) != len(md) {
+			rowErr.Err = fmt.Errorf("expect %d fields, get %d", len(md), len(record.fields))
+		}
+
+		rec := make([]interface{}, len(record.fields))
+		for i := 0; rowErr.Err == nil && i < len(record.fields); i++ {
+			se := sh.SchemaElements[i+1]
+			if c.nullValues[record.fields[i]] {
+				if se.GetRepetitionType() == parquet.FieldRepetitionType_REQUIRED {
+					rowErr.Err = errors.New("null value in REQUIRED column")
+				}
+			} else {
+				rec[i], rowErr.Err = types.TextToParquetType(record.fields[i], se, c.timestampLayout)
+			}
+			if rowErr.Err != nil {
+				rowErr.Line, rowErr.Column, rowErr.Name = record.lines[i], i+1, sh.GetExName(i+1)
+			}
+		}
+
+		if rowErr.Err != nil {
+			if c.errorSink == nil {
+				return rowErr
+			}
+			c.errorSink(rowErr)
+			return nil
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+		numRows++
+		return nil
+	}
+	//the written rows are still flushed, so the file is valid
+	stop := func(err error) (int64, error) {
+		cw.WriteStop()
+		return numRows, err
+	}
+
+	for _, record := range sample {
+		if err = write(record); err != nil {
+			return stop(err)
+		}
+	}
+	for {
+		record, err := readCSVRecord(cr)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return stop(err)
+		}
+		if err = write(record); err != nil {
+			return stop(err)
+		}
+	}
+	return numRows, cw.WriteStop()
+}
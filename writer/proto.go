@@ -0,0 +1,63 @@
+package writer
+
+import (
+	"errors"
+	"io"
+
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/marshal"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/source"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+//ProtoWriter writes protobuf messages, the schema is created by schema.NewSchemaHandlerFromProto
+type ProtoWriter struct {
+	ParquetWriter
+}
+
+func NewProtoWriterFromWriter(md protoreflect.MessageDescriptor, w io.Writer, np int64, opts ...ParquetWriterOption) (*ProtoWriter, error) {
+	wf := writerfile.NewWriterFile(w)
+	return NewProtoWriter(md, wf, np, opts...)
+}
+
+//Create Proto writer of the messages of the descriptor
+func NewProtoWriter(md protoreflect.MessageDescriptor, pfile source.ParquetFile, np int64, opts ...ParquetWriterOption) (*ProtoWriter, error) {
+	sh, err := schema.NewSchemaHandlerFromProto(md)
+	if err != nil {
+		return nil, err
+	}
+	pw, err := NewParquetWriter(pfile, sh, np, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pw.MarshalFunc = marshal.MarshalRows
+	return &ProtoWriter{ParquetWriter: *pw}, nil
+}
+
+//Write one message, it's converted to a row (see marshal.ProtoToRow) and can be modified after the call
+func (w *ProtoWriter) WriteProto(msg proto.Message) error {
+	if w.stopped {
+		return errors.New("writer is stopped")
+	}
+	row, err := marshal.ProtoToRow(msg, w.SchemaHandler)
+	if err != nil {
+		return err
+	}
+
+	ln := int64(len(w.Objs))
+	if w.CheckSizeCritical <= ln {
+		w.ObjSize = (w.ObjSize+int64(proto.Size(msg)))/2 + 1
+	}
+	w.ObjsSize += w.ObjSize
+	w.Objs = append(w.Objs, row)
+
+	criSize := w.NP * w.PageSize * w.SchemaHandler.GetColumnNum()
+	if w.ObjsSize >= criSize {
+		return w.Flush(false)
+	}
+	dln := (criSize - w.ObjsSize + w.ObjSize - 1) / w.ObjSize / 2
+	w.CheckSizeCritical = dln + ln
+	return nil
+}
@@ -0,0 +1,118 @@
+package writer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/marshal"
+	"github.com/xitongsys/parquet-go/reader"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	//register google/protobuf/timestamp.proto and type.proto
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/typepb"
+)
+
+//Descriptor of the message Event { int64 id; string name; uint32 count; Timestamp created; repeated string tags;
+//map<string, double> scores; oneof contact { string email; }; google.protobuf.Field field; repeated google.protobuf.Field fields; }
+func testProtoEventType(t *testing.T) protoreflect.MessageType {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		res := &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(number), Type: typ.Enum(), Label: label.Enum(), JsonName: proto.String(name)}
+		if typeName != "" {
+			res.TypeName = proto.String(typeName)
+		}
+		return res
+	}
+	optional, repeated := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	email := field("email", 7, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, "")
+	email.OneofIndex = proto.Int32(0)
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("event.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto", "google/protobuf/type.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Event"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				field("count", 3, descriptorpb.FieldDescriptorProto_TYPE_UINT32, optional, ""),
+				field("created", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Timestamp"),
+				field("tags", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, ""),
+				field("scores", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".test.Event.ScoresEntry"),
+				email,
+				field("field", 8, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional, ".google.protobuf.Field"),
+				field("fields", 9, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".google.protobuf.Field"),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("ScoresEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, optional, ""),
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("contact")}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dynamicpb.NewMessageType(fd.Messages().ByName("Event"))
+}
+
+func TestProto(t *testing.T) {
+	mt := testProtoEventType(t)
+	records := []string{
+		`{"id": "1", "name": "a", "count": 4000000000, "created": "2021-03-04T05:06:07.123456789Z", "tags": ["x", "y"],
+			"scores": {"b": 2.5, "a": 1}, "email": "a@b.c",
+			"field": {"kind": "TYPE_STRING", "number": 3, "name": "f", "packed": true, "options": [{"name": "o"}]},
+			"fields": [{"name": "g"}, {"cardinality": "CARDINALITY_REPEATED"}]}`,
+		`{"id": "2", "created": "1969-12-31T23:59:59.5Z"}`,
+	}
+	messages := make([]proto.Message, len(records))
+	for i, record := range records {
+		messages[i] = mt.New().Interface()
+		assert.NoError(t, protojson.Unmarshal([]byte(record), messages[i]))
+	}
+
+	var buf bytes.Buffer
+	pw, err := NewProtoWriterFromWriter(mt.Descriptor(), &buf, 1)
+	assert.NoError(t, err)
+	for _, msg := range messages {
+		assert.NoError(t, pw.WriteProto(msg))
+	}
+	//the messages are converted by WriteProto and can be modified
+	written := proto.Clone(messages[0])
+	messages[0].ProtoReflect().Set(mt.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString("b"))
+	messages[0] = written
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	rows, err := pr.ReadRows(len(messages))
+	assert.NoError(t, err)
+	assert.Equal(t, len(messages), len(rows))
+	for i := range messages {
+		res := mt.New().Interface()
+		assert.NoError(t, marshal.UnmarshalProto(rows[i], res))
+		assert.True(t, proto.Equal(messages[i], res), "expect %v, get %v", messages[i], res)
+	}
+
+	jsonRes, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	records, err = jsonRes.ReadJSON(1)
+	assert.NoError(t, err)
+	assert.Contains(t, records[0], `"scores":{"a":1,"b":2.5}`)
+	assert.Contains(t, records[0], `"count":4000000000`)
+}
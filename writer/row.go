@@ -0,0 +1,34 @@
+package writer
+
+import (
+	"io"
+
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/marshal"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+//RowWriter writes dynamic rows (marshal.Row) without reflection
+type RowWriter struct {
+	ParquetWriter
+}
+
+func NewRowWriterFromWriter(obj interface{}, w io.Writer, np int64, opts ...ParquetWriterOption) (*RowWriter, error) {
+	wf := writerfile.NewWriterFile(w)
+	return NewRowWriter(obj, wf, np, opts...)
+}
+
+//Create Row writer. Obj is a JSON schema string, a schema list, a schema handler or a object with tags
+func NewRowWriter(obj interface{}, pfile source.ParquetFile, np int64, opts ...ParquetWriterOption) (*RowWriter, error) {
+	pw, err := NewParquetWriter(pfile, obj, np, opts...)
+	if err != nil {
+		return nil, err
+	}
+	pw.MarshalFunc = marshal.MarshalRows
+	return &RowWriter{ParquetWriter: *pw}, nil
+}
+
+//Write one row, the fields are matched by the original names and missing fields are null
+func (w *RowWriter) WriteRow(row marshal.Row) error {
+	return w.Write(row)
+}
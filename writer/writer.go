@@ -46,8 +46,10 @@ type ParquetWriter struct {
 
 	MarshalFunc func(src []interface{}, sh *schema.SchemaHandler) (*map[string]*layout.Table, error)
 
-	stopped            bool
-	disableColumnIndex bool
+	stopped                  bool
+	disableColumnIndex       bool
+	statisticsTruncateLength int32
+	pageChecksum             bool
 }
 
 type ParquetWriterOption func(*ParquetWriter)
@@ -58,6 +60,36 @@ func WithDisableColumnIndex(disable bool) ParquetWriterOption {
 	}
 }
 
+// WithStatisticsTruncateLength limits the length of BYTE_ARRAY/FIXED_LEN_BYTE_ARRAY
+// min/max statistics in page headers, column chunks and column indexes. 0 disables truncation.
+func WithStatisticsTruncateLength(length int32) ParquetWriterOption {
+	return func(pw *ParquetWriter) {
+		pw.statisticsTruncateLength = length
+	}
+}
+
+// WithPageChecksum enables the CRC32 checksum of the compressed page data in the page headers
+func WithPageChecksum(enable bool) ParquetWriterOption {
+	return func(pw *ParquetWriter) {
+		pw.pageChecksum = enable
+	}
+}
+
+// WithKeyValueMetadata adds the key-value metadata to the file footer
+func WithKeyValueMetadata(key string, value string) ParquetWriterOption {
+	return func(pw *ParquetWriter) {
+		v := value
+		pw.Footer.KeyValueMetadata = append(pw.Footer.KeyValueMetadata, &parquet.KeyValue{Key: key, Value: &v})
+	}
+}
+
+// WithAvroSchema stores the Avro schema in the parquet.avro.schema key-value metadata like parquet-avro.
+// It isn't needed if the schema handler of the writer is created by schema.NewSchemaHandlerFromAvro,
+// its Avro schema is stored automatically.
+func WithAvroSchema(avsc string) ParquetWriterOption {
+	return WithKeyValueMetadata(schema.AvroSchemaKey, avsc)
+}
+
 func NewParquetWriterFromWriter(w io.Writer, obj interface{}, np int64, opts ...ParquetWriterOption) (*ParquetWriter, error) {
 	wf := writerfile.NewWriterFile(w)
 	return NewParquetWriter(wf, obj, np, opts...)
@@ -166,6 +198,8 @@ func (pw *ParquetWriter) WriteStop() error {
 	ts := thrift.NewTSerializer()
 	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
 	pw.RenameSchema()
+	pw.setColumnOrders()
+	pw.setAvroSchema()
 
 	// write ColumnIndex if not disabled
 	if !pw.disableColumnIndex {
@@ -173,7 +207,13 @@ func (pw *ParquetWriter) WriteStop() error {
 			idx := 0
 			for _, rowGroup := range pw.Footer.RowGroups {
 				for _, columnChunk := range rowGroup.Columns {
-					columnIndexBuf, err := ts.Write(context.TODO(), pw.ColumnIndexes[idx])
+					columnIndex := pw.ColumnIndexes[idx]
+					idx++
+					//no column index for the columns without min/max statistics
+					if columnIndex == nil {
+						continue
+					}
+					columnIndexBuf, err := ts.Write(context.TODO(), columnIndex)
 					if err != nil {
 						return err
 					}
@@ -181,8 +221,6 @@ func (pw *ParquetWriter) WriteStop() error {
 						return err
 					}
 
-					idx++
-
 					pos := pw.Offset
 					columnChunk.ColumnIndexOffset = &pos
 					columnIndexBufSize := int32(len(columnIndexBuf))
@@ -239,6 +277,35 @@ func (pw *ParquetWriter) WriteStop() error {
 	return nil
 }
 
+// setAvroSchema stores the Avro schema of the schema handler, unless it's set by WithAvroSchema
+func (pw *ParquetWriter) setAvroSchema() {
+	if pw.SchemaHandler == nil || pw.SchemaHandler.AvroSchema == "" {
+		return
+	}
+	for _, kv := range pw.Footer.KeyValueMetadata {
+		if kv.Key == schema.AvroSchemaKey {
+			return
+		}
+	}
+	WithAvroSchema(pw.SchemaHandler.AvroSchema)(pw)
+}
+
+// setColumnOrders sets the TypeDefinedOrder for all leaf columns,
+// which tells readers that min/max statistics follow the logical type ordering.
+// The list has one order per leaf and TYPE_ORDER is the only order of the format,
+// so the columns without a defined order (INTERVAL) keep their entry but have no min/max statistics.
+func (pw *ParquetWriter) setColumnOrders() {
+	pw.Footer.ColumnOrders = make([]*parquet.ColumnOrder, 0, len(pw.Footer.Schema))
+	for i, schema := range pw.Footer.Schema {
+		if i == 0 || schema.GetNumChildren() > 0 {
+			continue
+		}
+		columnOrder := parquet.NewColumnOrder()
+		columnOrder.TYPE_ORDER = parquet.NewTypeDefinedOrder()
+		pw.Footer.ColumnOrders = append(pw.Footer.ColumnOrders, columnOrder)
+	}
+}
+
 // Write one object to parquet file
 func (pw *ParquetWriter) Write(src interface{}) error {
 	if pw.stopped {
@@ -324,6 +391,8 @@ func (pw *ParquetWriter) flushObjs() error {
 
 			if err2 == nil {
 				for name, table := range *tableMap {
+					table.StatisticsTruncateLength = pw.statisticsTruncateLength
+					table.PageChecksum = pw.pageChecksum
 					if table.Info.Encoding == parquet.Encoding_PLAIN_DICTIONARY ||
 						table.Info.Encoding == parquet.Encoding_RLE_DICTIONARY {
 
@@ -334,6 +403,7 @@ func (pw *ParquetWriter) flushObjs() error {
 							}
 							if _, ok := pw.DictRecs[name]; !ok {
 								pw.DictRecs[name] = layout.NewDictRec(*table.Schema.Type)
+								pw.DictRecs[name].PageChecksum = pw.pageChecksum
 							}
 							pagesMapList[index][name], _ = layout.TableToDictDataPages(pw.DictRecs[name],
 								table, int32(pw.PageSize), 32, pw.CompressionType)
@@ -369,6 +439,14 @@ func (pw *ParquetWriter) flushObjs() error {
 			}
 			for _, page := range pages {
 				pw.Size += int64(len(page.RawData))
+				//the values of repeated columns aren't rows, keep the rows for the offset index
+				if page.DataTable != nil {
+					for _, rl := range page.DataTable.RepetitionLevels {
+						if rl == 0 {
+							page.NumRows++
+						}
+					}
+				}
 				page.DataTable = nil //release memory
 			}
 		}
@@ -439,12 +517,15 @@ func (pw *ParquetWriter) Flush(flag bool) error {
 			}
 
 			if !pw.disableColumnIndex {
-				//add ColumnIndex
-				columnIndex := parquet.NewColumnIndex()
-				columnIndex.NullPages = make([]bool, dataPageCount)
-				columnIndex.MinValues = make([][]byte, dataPageCount)
-				columnIndex.MaxValues = make([][]byte, dataPageCount)
-				columnIndex.BoundaryOrder = parquet.BoundaryOrder_UNORDERED
+				//add ColumnIndex, the columns without a defined order have none
+				var columnIndex *parquet.ColumnIndex
+				if schema := rowGroup.Chunks[k].Pages[0].Schema; common.HasDefinedOrder(schema.ConvertedType, schema.LogicalType) {
+					columnIndex = parquet.NewColumnIndex()
+					columnIndex.NullPages = make([]bool, dataPageCount)
+					columnIndex.MinValues = make([][]byte, dataPageCount)
+					columnIndex.MaxValues = make([][]byte, dataPageCount)
+					columnIndex.BoundaryOrder = parquet.BoundaryOrder_UNORDERED
+				}
 				pw.ColumnIndexes = append(pw.ColumnIndexes, columnIndex)
 
 				//add OffsetIndex
@@ -474,40 +555,41 @@ func (pw *ParquetWriter) Flush(flag bool) error {
 					}
 
 					if !pw.disableColumnIndex {
-						var minVal []byte
-						var maxVal []byte
-						var nullCount *int64
-						if page.Header.DataPageHeader != nil && page.Header.DataPageHeader.Statistics != nil {
-							minVal = page.Header.DataPageHeader.Statistics.Min
-							maxVal = page.Header.DataPageHeader.Statistics.Max
-							nullCount = page.Header.DataPageHeader.Statistics.NullCount
-
-						} else if page.Header.DataPageHeaderV2 != nil && page.Header.DataPageHeaderV2.Statistics != nil {
-							minVal = page.Header.DataPageHeaderV2.Statistics.Min
-							maxVal = page.Header.DataPageHeaderV2.Statistics.Max
-							nullCount = page.Header.DataPageHeaderV2.Statistics.NullCount
-						}
+						if columnIndex := pw.ColumnIndexes[len(pw.ColumnIndexes)-1]; columnIndex != nil {
+							var minVal []byte
+							var maxVal []byte
+							var nullCount *int64
+							if page.Header.DataPageHeader != nil && page.Header.DataPageHeader.Statistics != nil {
+								minVal = page.Header.DataPageHeader.Statistics.Min
+								maxVal = page.Header.DataPageHeader.Statistics.Max
+								nullCount = page.Header.DataPageHeader.Statistics.NullCount
+
+							} else if page.Header.DataPageHeaderV2 != nil && page.Header.DataPageHeaderV2.Statistics != nil {
+								minVal = page.Header.DataPageHeaderV2.Statistics.Min
+								maxVal = page.Header.DataPageHeaderV2.Statistics.Max
+								nullCount = page.Header.DataPageHeaderV2.Statistics.NullCount
+							}
 
-						// Handle nil values properly in column index.
-						// As per Parquet spec, min/max values are for non-null values only.
-						// For null values, we set min/max values to empty byte arrays and mark the page as potentially containing nulls.
-						columnIndex := pw.ColumnIndexes[len(pw.ColumnIndexes)-1]
-						if minVal == nil || maxVal == nil {
-							columnIndex.MinValues[dataPageIndex] = []byte{}
-							columnIndex.MaxValues[dataPageIndex] = []byte{}
-							columnIndex.NullPages[dataPageIndex] = true
-						} else {
-							columnIndex.MinValues[dataPageIndex] = minVal
-							columnIndex.MaxValues[dataPageIndex] = maxVal
-							columnIndex.NullPages[dataPageIndex] = false
-						}
+							// Handle nil values properly in column index.
+							// As per Parquet spec, min/max values are for non-null values only.
+							// For null values, we set min/max values to empty byte arrays and mark the page as potentially containing nulls.
+							if minVal == nil || maxVal == nil {
+								columnIndex.MinValues[dataPageIndex] = []byte{}
+								columnIndex.MaxValues[dataPageIndex] = []byte{}
+								columnIndex.NullPages[dataPageIndex] = true
+							} else {
+								columnIndex.MinValues[dataPageIndex] = minVal
+								columnIndex.MaxValues[dataPageIndex] = maxVal
+								columnIndex.NullPages[dataPageIndex] = false
+							}
 
-						// Statistics.NullCount is nil when statistics are omitted for the column otherwise for all column page headers it will be populated.
-						if nullCount != nil {
-							if columnIndex.NullCounts == nil {
-								columnIndex.NullCounts = make([]int64, dataPageCount)
+							// Statistics.NullCount is nil when statistics are omitted for the column otherwise for all column page headers it will be populated.
+							if nullCount != nil {
+								if columnIndex.NullCounts == nil {
+									columnIndex.NullCounts = make([]int64, dataPageCount)
+								}
+								columnIndex.NullCounts[dataPageIndex] = *nullCount
 							}
-							columnIndex.NullCounts[dataPageIndex] = *nullCount
 						}
 
 						pageLocation := parquet.NewPageLocation()
@@ -519,7 +601,7 @@ func (pw *ParquetWriter) Flush(flag bool) error {
 						offsetIndex := pw.OffsetIndexes[len(pw.OffsetIndexes)-1]
 						offsetIndex.PageLocations = append(offsetIndex.PageLocations, pageLocation)
 
-						firstRowIndex += int64(page.Header.DataPageHeader.NumValues)
+						firstRowIndex += page.NumRows
 						dataPageIndex++
 					}
 				}
@@ -3,9 +3,14 @@ package writer
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,9 +18,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/xitongsys/parquet-go-source/buffer"
 	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/layout"
+	"github.com/xitongsys/parquet-go/marshal"
 	"github.com/xitongsys/parquet-go/parquet"
 	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/schema"
 	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/types"
 )
 
 // TestNullCountsFromColumnIndex tests that NullCounts is correctly set in the ColumnIndex.
@@ -450,3 +460,917 @@ func TestDisableColumnIndex(t *testing.T) {
 	assert.Equal(t, 0, len(pw.ColumnIndexes), "ColumnIndexes should be empty when disabled")
 	assert.Equal(t, 0, len(pw.OffsetIndexes), "OffsetIndexes should be empty when disabled")
 }
+
+// TestStatisticsTruncateLength tests that binary statistics are truncated to
+// valid bounds and that the footer declares the column orders
+func TestStatisticsTruncateLength(t *testing.T) {
+	type Entry struct {
+		Name    string `parquet:"name=Name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Dict    string `parquet:"name=Dict, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+		Decimal string `parquet:"name=Decimal, type=BYTE_ARRAY, convertedtype=DECIMAL, precision=20, scale=2"`
+		Age     int32  `parquet:"name=Age, type=INT32, convertedtype=UINT_32"`
+	}
+
+	var buf bytes.Buffer
+	fw := writerfile.NewWriterFile(&buf)
+	pw, err := NewParquetWriter(fw, new(Entry), 1, WithStatisticsTruncateLength(4))
+	assert.NoError(t, err)
+
+	entries := []Entry{
+		{Name: "aaaaaaaa", Dict: "bbbbbbbb", Decimal: "\x00\x00\x00\x00\x01", Age: 1},
+		{Name: "zzzzzzzz", Dict: "cccccccc", Decimal: "\xff\xff\xff\xff\xff", Age: -1},
+	}
+	for _, entry := range entries {
+		assert.NoError(t, pw.Write(entry))
+	}
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, len(pr.Footer.ColumnOrders))
+	for _, columnOrder := range pr.Footer.ColumnOrders {
+		assert.True(t, columnOrder.IsSetTYPE_ORDER())
+	}
+
+	columns := pr.Footer.RowGroups[0].GetColumns()
+	assert.Equal(t, []byte("aaaa"), columns[0].MetaData.Statistics.MinValue)
+	assert.Equal(t, []byte("zzz{"), columns[0].MetaData.Statistics.MaxValue)
+	assert.Equal(t, []byte("bbbb"), columns[1].MetaData.Statistics.MinValue)
+	assert.Equal(t, []byte("cccd"), columns[1].MetaData.Statistics.MaxValue)
+	// decimals are compared as signed integers and are never truncated
+	assert.Equal(t, []byte("\xff\xff\xff\xff\xff"), columns[2].MetaData.Statistics.MinValue)
+	assert.Equal(t, []byte("\x00\x00\x00\x00\x01"), columns[2].MetaData.Statistics.MaxValue)
+	// UINT_32 values are compared as unsigned integers
+	assert.Equal(t, []byte{1, 0, 0, 0}, columns[3].MetaData.Statistics.MinValue)
+	assert.Equal(t, []byte{0xff, 0xff, 0xff, 0xff}, columns[3].MetaData.Statistics.MaxValue)
+
+	thriftReader := source.ConvertToThriftReader(pf, *columns[0].ColumnIndexOffset)
+	protocol := thrift.NewTCompactProtocolFactory().GetProtocol(thriftReader)
+	columnIndex := parquet.NewColumnIndex()
+	assert.NoError(t, columnIndex.Read(context.Background(), protocol))
+	assert.Equal(t, []byte("aaaa"), columnIndex.MinValues[0])
+	assert.Equal(t, []byte("zzz{"), columnIndex.MaxValues[0])
+}
+
+// TestStatisticsOrder tests that the statistics of unsigned integers use the
+// unsigned order, decimals in bytes the signed order and that INTERVAL has none
+func TestStatisticsOrder(t *testing.T) {
+	type Entry struct {
+		U32      int32  `parquet:"name=u32, type=INT32, convertedtype=UINT_32"`
+		U64      int64  `parquet:"name=u64, type=INT64, convertedtype=UINT_64"`
+		U64Log   int64  `parquet:"name=u64_log, type=INT64, logicaltype=INTEGER, logicaltype.bitwidth=64, logicaltype.issigned=false"`
+		DecFixed string `parquet:"name=dec_fixed, type=FIXED_LEN_BYTE_ARRAY, length=2, convertedtype=DECIMAL, precision=4, scale=2"`
+		DecBytes string `parquet:"name=dec_bytes, type=BYTE_ARRAY, convertedtype=DECIMAL, precision=10, scale=2"`
+		DecDict  string `parquet:"name=dec_dict, type=BYTE_ARRAY, convertedtype=DECIMAL, precision=10, scale=2, encoding=PLAIN_DICTIONARY"`
+		Interval string `parquet:"name=interval, type=FIXED_LEN_BYTE_ARRAY, length=12, convertedtype=INTERVAL"`
+	}
+
+	var buf bytes.Buffer
+	fw := writerfile.NewWriterFile(&buf)
+	pw, err := NewParquetWriter(fw, new(Entry), 1)
+	assert.NoError(t, err)
+
+	interval := func(months byte) string {
+		return string([]byte{months, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	}
+	entries := []Entry{
+		{U32: 1, U64: 1, U64Log: 1, DecFixed: "\x00\x01", DecBytes: "\x01\x00", DecDict: "\x01\x00", Interval: interval(1)},
+		{U32: -1, U64: -1, U64Log: -1, DecFixed: "\xff\xff", DecBytes: "\xff", DecDict: "\xff", Interval: interval(2)},
+		{U32: math.MaxInt32, U64: math.MaxInt64, U64Log: math.MaxInt64, DecFixed: "\x7f\xff", DecBytes: "\x7f", DecDict: "\x7f", Interval: interval(3)},
+	}
+	for _, entry := range entries {
+		assert.NoError(t, pw.Write(entry))
+	}
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, len(pr.Footer.ColumnOrders))
+
+	columns := pr.Footer.RowGroups[0].GetColumns()
+	expects := []struct{ min, max []byte }{
+		{[]byte{1, 0, 0, 0}, []byte{0xff, 0xff, 0xff, 0xff}},
+		{[]byte{1, 0, 0, 0, 0, 0, 0, 0}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{[]byte{1, 0, 0, 0, 0, 0, 0, 0}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{[]byte("\xff\xff"), []byte("\x7f\xff")},
+		{[]byte("\xff"), []byte("\x01\x00")},
+		{[]byte("\xff"), []byte("\x01\x00")},
+	}
+	for i, expect := range expects {
+		statistics := columns[i].MetaData.Statistics
+		assert.Equal(t, expect.min, statistics.MinValue, columns[i].MetaData.PathInSchema)
+		assert.Equal(t, expect.max, statistics.MaxValue, columns[i].MetaData.PathInSchema)
+
+		thriftReader := source.ConvertToThriftReader(pf, columns[i].GetColumnIndexOffset())
+		protocol := thrift.NewTCompactProtocolFactory().GetProtocol(thriftReader)
+		columnIndex := parquet.NewColumnIndex()
+		assert.NoError(t, columnIndex.Read(context.Background(), protocol))
+		assert.Equal(t, expect.min, columnIndex.MinValues[0], columns[i].MetaData.PathInSchema)
+		assert.Equal(t, expect.max, columnIndex.MaxValues[0], columns[i].MetaData.PathInSchema)
+	}
+
+	// the order of INTERVAL is undefined, only the null count is written
+	statistics := columns[6].MetaData.Statistics
+	assert.Nil(t, statistics.MinValue)
+	assert.Nil(t, statistics.MaxValue)
+	assert.Equal(t, int64(0), statistics.GetNullCount())
+	assert.Nil(t, columns[6].ColumnIndexOffset)
+	assert.NotNil(t, columns[6].OffsetIndexOffset)
+
+	pr, err = reader.NewParquetReader(pf, new(Entry), 1)
+	assert.NoError(t, err)
+	res := make([]Entry, 3)
+	assert.NoError(t, pr.Read(&res))
+	assert.Equal(t, entries, res)
+}
+
+// TestPageChecksum tests that page checksums are written and that a
+// corrupted page is reported with its column and offset
+func TestPageChecksum(t *testing.T) {
+	type Entry struct {
+		Name string `parquet:"name=Name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Dict string `parquet:"name=Dict, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+		Age  int32  `parquet:"name=Age, type=INT32"`
+	}
+
+	var buf bytes.Buffer
+	fw := writerfile.NewWriterFile(&buf)
+	pw, err := NewParquetWriter(fw, new(Entry), 1, WithPageChecksum(true))
+	assert.NoError(t, err)
+	pw.CompressionType = parquet.CompressionCodec_UNCOMPRESSED
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, pw.Write(Entry{Name: fmt.Sprintf("name_%d", i), Dict: "dict", Age: int32(i)}))
+	}
+	assert.NoError(t, pw.WriteStop())
+
+	data := buf.Bytes()
+	pf, err := buffer.NewBufferFile(data)
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, new(Entry), 1, reader.WithVerifyPageChecksum(true))
+	assert.NoError(t, err)
+	entries := make([]Entry, 10)
+	assert.NoError(t, pr.Read(&entries))
+	assert.Equal(t, "name_9", entries[9].Name)
+	assert.Equal(t, "dict", entries[9].Dict)
+
+	columns := pr.Footer.RowGroups[0].GetColumns()
+	for _, column := range columns {
+		offset := column.MetaData.DataPageOffset
+		if column.MetaData.DictionaryPageOffset != nil {
+			offset = *column.MetaData.DictionaryPageOffset
+		}
+		pageHeader, err := layout.ReadPageHeader(source.ConvertToThriftReader(pf, offset))
+		assert.NoError(t, err)
+		assert.True(t, pageHeader.IsSetCrc())
+	}
+
+	// corrupt the last byte of the Age page
+	ageColumn := columns[2].MetaData
+	corrupted := append([]byte{}, data...)
+	corrupted[ageColumn.DataPageOffset+ageColumn.TotalCompressedSize-1] ^= 0xFF
+
+	pf, err = buffer.NewBufferFile(corrupted)
+	assert.NoError(t, err)
+	pr, err = reader.NewParquetReader(pf, new(Entry), 1, reader.WithVerifyPageChecksum(true))
+	assert.NoError(t, err)
+	err = pr.Read(&entries)
+	var checksumErr *layout.PageChecksumError
+	assert.True(t, errors.As(err, &checksumErr))
+	assert.Equal(t, ageColumn.DataPageOffset, checksumErr.Offset)
+	assert.Contains(t, err.Error(), "column Parquet_go_root.Age at offset")
+
+	// corrupt the data page following the dictionary page
+	dictColumn := columns[1].MetaData
+	corrupted = append([]byte{}, data...)
+	corrupted[dictColumn.DataPageOffset+dictColumn.TotalCompressedSize-(dictColumn.DataPageOffset-*dictColumn.DictionaryPageOffset)-1] ^= 0xFF
+
+	pf, err = buffer.NewBufferFile(corrupted)
+	assert.NoError(t, err)
+	pr, err = reader.NewParquetReader(pf, new(Entry), 1, reader.WithVerifyPageChecksum(true))
+	assert.NoError(t, err)
+	err = pr.Read(&entries)
+	assert.True(t, errors.As(err, &checksumErr))
+	assert.Equal(t, dictColumn.DataPageOffset, checksumErr.Offset)
+}
+
+func TestByteStreamSplit(t *testing.T) {
+	type Entry struct {
+		Int32   int32    `parquet:"name=int32, type=INT32, encoding=BYTE_STREAM_SPLIT"`
+		Int64   *int64   `parquet:"name=int64, type=INT64, repetitiontype=OPTIONAL, encoding=BYTE_STREAM_SPLIT"`
+		Decimal string   `parquet:"name=decimal, type=FIXED_LEN_BYTE_ARRAY, length=4, convertedtype=DECIMAL, scale=2, precision=9, encoding=BYTE_STREAM_SPLIT"`
+		Float   float32  `parquet:"name=float, type=FLOAT, encoding=BYTE_STREAM_SPLIT"`
+		Double  *float64 `parquet:"name=double, type=DOUBLE, repetitiontype=OPTIONAL, encoding=BYTE_STREAM_SPLIT"`
+	}
+
+	expected := make([]Entry, 100)
+	for i := range expected {
+		expected[i] = Entry{
+			Int32:   int32(i * 1000),
+			Decimal: string([]byte{0, 0, byte(i >> 8), byte(i)}),
+			Float:   float32(i) / 4,
+		}
+		if i%3 != 0 {
+			int64Val, doubleVal := int64(-i), float64(i)/8
+			expected[i].Int64, expected[i].Double = &int64Val, &doubleVal
+		}
+	}
+
+	for _, compressionType := range []parquet.CompressionCodec{parquet.CompressionCodec_UNCOMPRESSED, parquet.CompressionCodec_SNAPPY} {
+		var buf bytes.Buffer
+		fw := writerfile.NewWriterFile(&buf)
+		pw, err := NewParquetWriter(fw, new(Entry), 1)
+		assert.NoError(t, err)
+		pw.CompressionType = compressionType
+		pw.PageSize = 256
+		for _, entry := range expected {
+			assert.NoError(t, pw.Write(entry))
+		}
+		assert.NoError(t, pw.WriteStop())
+
+		pf, err := buffer.NewBufferFile(buf.Bytes())
+		assert.NoError(t, err)
+		pr, err := reader.NewParquetReader(pf, new(Entry), 1)
+		assert.NoError(t, err)
+		for _, column := range pr.Footer.RowGroups[0].GetColumns() {
+			assert.Contains(t, column.MetaData.Encodings, parquet.Encoding_BYTE_STREAM_SPLIT)
+		}
+		entries := make([]Entry, len(expected))
+		assert.NoError(t, pr.Read(&entries))
+		assert.Equal(t, expected, entries)
+
+		// skipping rows decodes the values from the raw page data
+		pr, err = reader.NewParquetReader(pf, new(Entry), 1)
+		assert.NoError(t, err)
+		assert.NoError(t, pr.SkipRows(50))
+		entries = make([]Entry, 10)
+		assert.NoError(t, pr.Read(&entries))
+		assert.Equal(t, expected[50:60], entries)
+	}
+}
+
+func TestNativeTypes(t *testing.T) {
+	type Entry struct {
+		Timestamp time.Time     `parquet:"name=timestamp, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MICROS"`
+		Local     time.Time     `parquet:"name=local, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+		Date      time.Time     `parquet:"name=date, type=INT32, convertedtype=DATE"`
+		Duration  time.Duration `parquet:"name=duration, type=INT32, convertedtype=TIME_MILLIS"`
+		Int96     *time.Time    `parquet:"name=int96, type=INT96, repetitiontype=OPTIONAL"`
+		Decimal   *big.Rat      `parquet:"name=decimal, type=FIXED_LEN_BYTE_ARRAY, length=8, convertedtype=DECIMAL, scale=3, precision=18, repetitiontype=OPTIONAL"`
+		BigInt    big.Int       `parquet:"name=bigint, type=INT64, convertedtype=DECIMAL, scale=2, precision=18"`
+		UUID      [16]byte      `parquet:"name=uuid, type=FIXED_LEN_BYTE_ARRAY, length=16"`
+		Times     []time.Time   `parquet:"name=times, type=INT64, convertedtype=TIMESTAMP_MICROS, repetitiontype=REPEATED"`
+	}
+
+	base := time.Date(2021, 3, 4, 5, 6, 7, 891234000, time.UTC)
+	expected := make([]Entry, 20)
+	for i := range expected {
+		ts := base.Add(time.Duration(i) * time.Hour)
+		expected[i] = Entry{
+			Timestamp: ts,
+			Local:     time.Date(2021, 3, 4, i, 0, 0, 0, time.Local),
+			Date:      time.Date(2021, 3, 4+i, 0, 0, 0, 0, time.UTC),
+			Duration:  time.Duration(i) * time.Minute,
+			UUID:      [16]byte{byte(i), 1, 2, 3},
+			Times:     []time.Time{ts, ts.Add(time.Second)},
+		}
+		expected[i].BigInt.SetInt64(int64(-i * 101))
+		if i%2 == 0 {
+			expected[i].Int96 = &ts
+			expected[i].Decimal = big.NewRat(int64(i*1000+7), 1000)
+		}
+	}
+
+	for _, fast := range []bool{false, true} {
+		var buf bytes.Buffer
+		fw := writerfile.NewWriterFile(&buf)
+		pw, err := NewParquetWriter(fw, new(Entry), 1)
+		assert.NoError(t, err)
+		if fast {
+			pw.MarshalFunc = marshal.MarshalFast
+		}
+		for _, entry := range expected {
+			assert.NoError(t, pw.Write(entry))
+		}
+		assert.NoError(t, pw.WriteStop())
+
+		pf, err := buffer.NewBufferFile(buf.Bytes())
+		assert.NoError(t, err)
+		pr, err := reader.NewParquetReader(pf, new(Entry), 1)
+		assert.NoError(t, err)
+		entries := make([]Entry, len(expected))
+		assert.NoError(t, pr.Read(&entries))
+
+		for i, entry := range entries {
+			exp := expected[i]
+			assert.True(t, exp.Timestamp.Equal(entry.Timestamp))
+			assert.True(t, exp.Local.Equal(entry.Local))
+			assert.True(t, exp.Date.Equal(entry.Date))
+			assert.Equal(t, exp.Duration, entry.Duration)
+			assert.Equal(t, 0, exp.BigInt.Cmp(&entry.BigInt))
+			assert.Equal(t, exp.UUID, entry.UUID)
+			assert.Equal(t, len(exp.Times), len(entry.Times))
+			for j := range exp.Times {
+				assert.True(t, exp.Times[j].Equal(entry.Times[j]))
+			}
+			if exp.Int96 == nil {
+				assert.Nil(t, entry.Int96)
+				assert.Nil(t, entry.Decimal)
+			} else {
+				assert.True(t, exp.Int96.Equal(*entry.Int96))
+				assert.Equal(t, 0, exp.Decimal.Cmp(entry.Decimal))
+			}
+		}
+	}
+}
+
+type testMoney struct {
+	Cents int64
+}
+
+func (m testMoney) MarshalParquetValue() (interface{}, error) {
+	return m.Cents, nil
+}
+
+func (m *testMoney) UnmarshalParquetValue(val interface{}) error {
+	m.Cents, _ = val.(int64)
+	return nil
+}
+
+type testColor int
+
+func (c testColor) MarshalParquetValue() (interface{}, error) {
+	return []string{"red", "green", "blue"}[c], nil
+}
+
+func (c *testColor) UnmarshalParquetValue(val interface{}) error {
+	for i, name := range []string{"red", "green", "blue"} {
+		if name == val {
+			*c = testColor(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown color %v", val)
+}
+
+type testIP []byte
+
+func (ip testIP) MarshalParquetValue() (interface{}, error) {
+	return string(ip), nil
+}
+
+func (ip *testIP) UnmarshalParquetValue(val interface{}) error {
+	*ip = testIP(val.(string))
+	return nil
+}
+
+func TestValueMarshaler(t *testing.T) {
+	type Entry struct {
+		Price    testMoney   `parquet:"name=price, type=INT64, convertedtype=DECIMAL, scale=2, precision=18"`
+		Discount *testMoney  `parquet:"name=discount, type=INT64, repetitiontype=OPTIONAL"`
+		Color    testColor   `parquet:"name=color, type=BYTE_ARRAY, convertedtype=ENUM"`
+		IP       testIP      `parquet:"name=ip, type=BYTE_ARRAY"`
+		Colors   []testColor `parquet:"name=colors, type=BYTE_ARRAY, convertedtype=ENUM, repetitiontype=REPEATED"`
+	}
+
+	expected := make([]Entry, 10)
+	for i := range expected {
+		expected[i] = Entry{
+			Price:  testMoney{Cents: int64(i * 100)},
+			Color:  testColor(i % 3),
+			IP:     testIP{10, 0, 0, byte(i)},
+			Colors: []testColor{testColor((i + 1) % 3), testColor((i + 2) % 3)},
+		}
+		if i%2 == 0 {
+			expected[i].Discount = &testMoney{Cents: int64(-i)}
+		}
+	}
+
+	for _, fast := range []bool{false, true} {
+		var buf bytes.Buffer
+		fw := writerfile.NewWriterFile(&buf)
+		pw, err := NewParquetWriter(fw, new(Entry), 1)
+		assert.NoError(t, err)
+		if fast {
+			pw.MarshalFunc = marshal.MarshalFast
+		}
+		for _, entry := range expected {
+			assert.NoError(t, pw.Write(entry))
+		}
+		assert.NoError(t, pw.WriteStop())
+
+		pf, err := buffer.NewBufferFile(buf.Bytes())
+		assert.NoError(t, err)
+		pr, err := reader.NewParquetReader(pf, new(Entry), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(expected)), pr.GetNumRows())
+		entries := make([]Entry, len(expected))
+		assert.NoError(t, pr.Read(&entries))
+		assert.Equal(t, expected, entries)
+	}
+}
+
+func TestFloat16AndUnknown(t *testing.T) {
+	type Entry struct {
+		Half    types.Float16  `parquet:"name=half, type=FIXED_LEN_BYTE_ARRAY, length=2, logicaltype=FLOAT16"`
+		HalfPtr *types.Float16 `parquet:"name=half_ptr, type=FIXED_LEN_BYTE_ARRAY, length=2, logicaltype=FLOAT16, repetitiontype=OPTIONAL"`
+		Nothing *int32         `parquet:"name=nothing, type=INT32, logicaltype=UNKNOWN, repetitiontype=OPTIONAL"`
+		Nanos   time.Duration  `parquet:"name=nanos, type=INT64, logicaltype=TIME, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	}
+
+	expected := make([]Entry, 10)
+	for i := range expected {
+		expected[i] = Entry{
+			Half:  types.Float16(float32(i) - 4.5),
+			Nanos: time.Duration(i)*time.Hour + 123456789,
+		}
+		if i%2 == 0 {
+			half := types.Float16(float32(i) / 4)
+			expected[i].HalfPtr = &half
+		}
+	}
+
+	for _, fast := range []bool{false, true} {
+		var buf bytes.Buffer
+		fw := writerfile.NewWriterFile(&buf)
+		pw, err := NewParquetWriter(fw, new(Entry), 1)
+		assert.NoError(t, err)
+		if fast {
+			pw.MarshalFunc = marshal.MarshalFast
+		}
+		for _, entry := range expected {
+			assert.NoError(t, pw.Write(entry))
+		}
+		assert.NoError(t, pw.WriteStop())
+
+		pf, err := buffer.NewBufferFile(buf.Bytes())
+		assert.NoError(t, err)
+		pr, err := reader.NewParquetReader(pf, new(Entry), 1)
+		assert.NoError(t, err)
+		entries := make([]Entry, len(expected))
+		assert.NoError(t, pr.Read(&entries))
+		assert.Equal(t, expected, entries)
+
+		//FLOAT16 statistics are ordered as floats
+		stats := pr.Footer.RowGroups[0].Columns[0].MetaData.Statistics
+		assert.Equal(t, common.Float32ToFLOAT16(-4.5), string(stats.MinValue))
+		assert.Equal(t, common.Float32ToFLOAT16(4.5), string(stats.MaxValue))
+		assert.True(t, pr.Footer.Schema[3].LogicalType.IsSetUNKNOWN())
+
+		pf, err = buffer.NewBufferFile(buf.Bytes())
+		assert.NoError(t, err)
+		pr, err = reader.NewParquetReader(pf, nil, 1)
+		assert.NoError(t, err)
+		rows, err := pr.ReadJSON(1)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"half":-4.5,"half_ptr":0,"nothing":null,"nanos":"00:00:00.123456789"}`, rows[0])
+	}
+}
+
+func TestLegacyListAndMap(t *testing.T) {
+	//write the legacy shapes with structs and annotate them as LIST and MAP
+	type LegacyEntry struct {
+		Ints struct {
+			Array []int32 `parquet:"name=array, type=INT32, repetitiontype=REPEATED"`
+		} `parquet:"name=ints"`
+		Names *struct {
+			Bag []struct {
+				Array_element *string `parquet:"name=array_element, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+			} `parquet:"name=bag, repetitiontype=REPEATED"`
+		} `parquet:"name=names"`
+		Attrs struct {
+			Map []struct {
+				K string `parquet:"name=k, type=BYTE_ARRAY, convertedtype=UTF8"`
+				V int64  `parquet:"name=v, type=INT64"`
+			} `parquet:"name=map, repetitiontype=REPEATED"`
+		} `parquet:"name=attrs"`
+	}
+	type Entry struct {
+		Ints  []int32
+		Names []*string
+		Attrs map[string]int64
+	}
+
+	var buf bytes.Buffer
+	fw := writerfile.NewWriterFile(&buf)
+	pw, err := NewParquetWriter(fw, new(LegacyEntry), 1)
+	assert.NoError(t, err)
+	for i, info := range pw.SchemaHandler.Infos {
+		switch info.ExName {
+		case "ints", "names":
+			pw.SchemaHandler.SchemaElements[i].ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_LIST)
+		case "attrs":
+			pw.SchemaHandler.SchemaElements[i].ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_MAP)
+		case "map":
+			pw.SchemaHandler.SchemaElements[i].ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_MAP_KEY_VALUE)
+		}
+	}
+
+	expected := make([]Entry, 5)
+	for i := range expected {
+		var entry LegacyEntry
+		entry.Names = &struct {
+			Bag []struct {
+				Array_element *string `parquet:"name=array_element, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+			} `parquet:"name=bag, repetitiontype=REPEATED"`
+		}{}
+		expected[i] = Entry{Ints: []int32{}, Names: []*string{}, Attrs: map[string]int64{}}
+		for j := 0; j < i; j++ {
+			name := fmt.Sprintf("name_%d", j)
+			entry.Ints.Array = append(entry.Ints.Array, int32(j))
+			entry.Names.Bag = append(entry.Names.Bag, struct {
+				Array_element *string `parquet:"name=array_element, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+			}{&name})
+			entry.Attrs.Map = append(entry.Attrs.Map, struct {
+				K string `parquet:"name=k, type=BYTE_ARRAY, convertedtype=UTF8"`
+				V int64  `parquet:"name=v, type=INT64"`
+			}{name, int64(j)})
+			expected[i].Ints = append(expected[i].Ints, int32(j))
+			expected[i].Names = append(expected[i].Names, &name)
+			expected[i].Attrs[name] = int64(j)
+		}
+		assert.NoError(t, pw.Write(entry))
+	}
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	entries := make([]Entry, len(expected))
+	assert.NoError(t, pr.Read(&entries))
+	assert.Equal(t, expected, entries)
+
+	pr, err = reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	res, err := pr.ReadByNumber(len(expected))
+	assert.NoError(t, err)
+	assert.Equal(t, reflect.TypeOf([]*string{}), reflect.ValueOf(res[0]).FieldByName("Names").Type())
+	for i := range res {
+		got, _ := json.Marshal(res[i])
+		exp, _ := json.Marshal(expected[i])
+		assert.JSONEq(t, string(exp), string(got))
+	}
+}
+
+func TestFieldID(t *testing.T) {
+	type Address struct {
+		City string `parquet:"name=city, type=BYTE_ARRAY, convertedtype=UTF8, fieldid=11"`
+		Zip  int32  `parquet:"name=zip, type=INT32"`
+	}
+	type Entry struct {
+		Id      int64            `parquet:"name=id, type=INT64, fieldid=1"`
+		Address *Address         `parquet:"name=address, fieldid=2"`
+		Tags    []string         `parquet:"name=tags, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8, fieldid=3, valuefieldid=31"`
+		Scores  map[string]int32 `parquet:"name=scores, type=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=INT32, fieldid=4, keyfieldid=41, valuefieldid=42"`
+		Note    string           `parquet:"name=note, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Code    int32            `parquet:"name=code, type=INT32, fieldid=0"`
+	}
+
+	var buf bytes.Buffer
+	fw := writerfile.NewWriterFile(&buf)
+	pw, err := NewParquetWriter(fw, new(Entry), 1)
+	assert.NoError(t, err)
+	expected := make([]Entry, 10)
+	for i := range expected {
+		expected[i] = Entry{
+			Id:      int64(i),
+			Address: &Address{City: fmt.Sprintf("city_%d", i), Zip: int32(i)},
+			Tags:    []string{"a", "b"},
+			Scores:  map[string]int32{"x": int32(i)},
+			Note:    "note",
+			Code:    int32(i) * 10,
+		}
+		assert.NoError(t, pw.Write(expected[i]))
+	}
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetColumnReader(pf, 1)
+	assert.NoError(t, err)
+	ids := make(map[string]int32)
+	for exPath, fieldID := range pr.SchemaHandler.GetFieldIDs() {
+		ids[strings.Join(common.StrToPath(pr.SchemaHandler.InPathToExPath[exPath])[1:], ".")] = fieldID
+	}
+	assert.Equal(t, map[string]int32{
+		"id": 1, "address": 2, "address.city": 11, "tags": 3, "tags.list.element": 31,
+		"scores": 4, "scores.key_value.key": 41, "scores.key_value.value": 42, "code": 0,
+	}, ids)
+
+	//0 is a field_id
+	values, _, _, err := pr.ReadColumnByFieldID(0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int32(0), int32(10)}, values)
+
+	values, _, _, err = pr.ReadColumnByFieldID(11, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"city_0", "city_1", "city_2"}, values)
+	assert.NoError(t, pr.SkipRowsByFieldID(42, 5))
+	values, _, _, err = pr.ReadColumnByFieldID(42, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int32(5), int32(6)}, values)
+	_, _, _, err = pr.ReadColumnByFieldID(5, 1)
+	assert.Error(t, err)
+
+	pr, err = reader.NewParquetReader(pf, new(Entry), 1)
+	assert.NoError(t, err)
+	addresses := make([]*Address, 2)
+	assert.NoError(t, pr.ReadPartialByFieldID(&addresses, 2))
+	assert.Equal(t, []*Address{expected[0].Address, expected[1].Address}, addresses)
+
+}
+
+func TestRowWriter(t *testing.T) {
+	type Child struct {
+		X    float64 `parquet:"name=x, type=DOUBLE"`
+		Flag *bool   `parquet:"name=flag, type=BOOLEAN"`
+	}
+	type Entry struct {
+		ID     int32            `parquet:"name=id, type=INT32"`
+		Name   *string          `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Tags   []int64          `parquet:"name=tags, type=LIST, valuetype=INT64"`
+		Scores map[string]int32 `parquet:"name=scores, type=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=INT32"`
+		Child  *Child           `parquet:"name=child"`
+		Items  []Child          `parquet:"name=items, repetitiontype=REPEATED"`
+	}
+	field := func(name string, v marshal.Value) marshal.Field {
+		return marshal.Field{Name: name, Value: v}
+	}
+
+	flag, name := true, "name"
+	entries := []Entry{
+		{ID: 1, Name: &name, Tags: []int64{1, 2}, Scores: map[string]int32{"a": 1}, Child: &Child{X: 1.5, Flag: &flag}, Items: []Child{{X: 2}, {X: 3, Flag: &flag}}},
+		{ID: 2, Tags: []int64{}, Scores: map[string]int32{}},
+	}
+	rows := []marshal.Row{
+		{
+			field("id", marshal.Int32Value(1)),
+			field("name", marshal.ByteArrayValue(name)),
+			field("tags", marshal.ListValue([]marshal.Value{marshal.Int64Value(1), marshal.Int64Value(2)})),
+			field("scores", marshal.MapValue([]marshal.MapEntry{{Key: marshal.ByteArrayValue("a"), Value: marshal.Int32Value(1)}})),
+			field("child", marshal.GroupValue(marshal.Row{field("x", marshal.DoubleValue(1.5)), field("flag", marshal.BooleanValue(true))})),
+			field("items", marshal.ListValue([]marshal.Value{
+				marshal.GroupValue(marshal.Row{field("x", marshal.DoubleValue(2)), field("flag", marshal.NullValue())}),
+				marshal.GroupValue(marshal.Row{field("x", marshal.DoubleValue(3)), field("flag", marshal.BooleanValue(true))}),
+			})),
+		},
+		{
+			field("id", marshal.Int32Value(2)),
+			field("name", marshal.NullValue()),
+			field("tags", marshal.ListValue([]marshal.Value{})),
+			field("scores", marshal.MapValue([]marshal.MapEntry{})),
+			field("child", marshal.NullValue()),
+			field("items", marshal.ListValue([]marshal.Value{})),
+		},
+	}
+
+	//rows written by the row writer, missing optional fields are null
+	var buf bytes.Buffer
+	rw, err := NewRowWriterFromWriter(new(Entry), &buf, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, rw.WriteRow(rows[0]))
+	assert.NoError(t, rw.WriteRow(marshal.Row{rows[1][0], rows[1][2], rows[1][3], rows[1][5]}))
+	assert.NoError(t, rw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, new(Entry), 1)
+	assert.NoError(t, err)
+	res := make([]Entry, len(entries))
+	assert.NoError(t, pr.Read(&res))
+	assert.Equal(t, entries, res)
+
+	pr, err = reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	got, err := pr.ReadRows(len(rows))
+	assert.NoError(t, err)
+	assert.Equal(t, rows, got)
+
+	//rows read from a file written with structs
+	buf.Reset()
+	pw, err := NewParquetWriterFromWriter(&buf, new(Entry), 1)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		assert.NoError(t, pw.Write(entry))
+	}
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err = buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err = reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	got, err = pr.ReadRows(len(rows) + 1)
+	assert.NoError(t, err)
+	assert.Equal(t, rows, got)
+
+	//type mismatches and null required fields
+	sh := pw.SchemaHandler
+	_, err = marshal.MarshalRows([]interface{}{marshal.Row{field("id", marshal.Int64Value(1))}}, sh)
+	assert.Error(t, err)
+	_, err = marshal.MarshalRows([]interface{}{marshal.Row{field("tags", marshal.ListValue(nil))}}, sh)
+	assert.Error(t, err)
+}
+
+func TestReadJSON(t *testing.T) {
+	type Entry struct {
+		Name      string            `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+		Timestamp time.Time         `parquet:"name=timestamp, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=MICROS"`
+		Local     time.Time         `parquet:"name=local, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=false, logicaltype.unit=MILLIS"`
+		Date      time.Time         `parquet:"name=date, type=INT32, convertedtype=DATE"`
+		Time      time.Duration     `parquet:"name=time, type=INT32, convertedtype=TIME_MILLIS"`
+		Int96     *time.Time        `parquet:"name=int96, type=INT96, repetitiontype=OPTIONAL"`
+		Decimal   *big.Rat          `parquet:"name=decimal, type=FIXED_LEN_BYTE_ARRAY, length=8, convertedtype=DECIMAL, scale=3, precision=18, repetitiontype=OPTIONAL"`
+		UUID      [16]byte          `parquet:"name=uuid, type=FIXED_LEN_BYTE_ARRAY, length=16, logicaltype=UUID"`
+		Bytes     string            `parquet:"name=bytes, type=BYTE_ARRAY"`
+		Uint      int32             `parquet:"name=uint, type=INT32, convertedtype=UINT_32"`
+		Ratio     float64           `parquet:"name=ratio, type=DOUBLE"`
+		Tags      []int64           `parquet:"name=tags, type=LIST, valuetype=INT64"`
+		Scores    map[string]*int32 `parquet:"name=scores, type=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=INT32, valuerepetitiontype=OPTIONAL"`
+	}
+
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 891234000, time.UTC)
+	score := int32(90)
+	entries := []Entry{
+		{
+			Name:      "a",
+			Timestamp: ts,
+			Local:     time.Date(2021, 3, 4, 5, 6, 7, 891000000, time.Local),
+			Date:      time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+			Time:      5*time.Hour + 6*time.Minute + 7891*time.Millisecond,
+			Int96:     &ts,
+			Decimal:   big.NewRat(-12345, 1000),
+			UUID:      [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+			Bytes:     "\x00\xff",
+			Uint:      -1,
+			Ratio:     math.NaN(),
+			Tags:      []int64{1, 2},
+			Scores:    map[string]*int32{"math": &score, "none": nil},
+		},
+		{Name: "b", Timestamp: ts, Local: time.Date(1970, 1, 1, 0, 0, 0, 0, time.Local), Date: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), Ratio: 0.5, Tags: []int64{}, Scores: map[string]*int32{}},
+	}
+	expected := []string{
+		`{"name":"a","timestamp":"2021-03-04T05:06:07.891234Z","local":"2021-03-04T05:06:07.891","date":"2021-03-04","time":"05:06:07.891",` +
+			`"int96":"2021-03-04T05:06:07.891234Z","decimal":"-12.345","uuid":"123e4567-e89b-12d3-a456-426614174000","bytes":"AP8=",` +
+			`"uint":4294967295,"ratio":"NaN","tags":[1,2],"scores":{"math":90,"none":null}}`,
+		`{"name":"b","timestamp":"2021-03-04T05:06:07.891234Z","local":"1970-01-01T00:00:00","date":"1970-01-01","time":"00:00:00",` +
+			`"int96":null,"decimal":null,"uuid":"00000000-0000-0000-0000-000000000000","bytes":"","uint":0,"ratio":0.5,"tags":[],"scores":{}}`,
+	}
+
+	var buf bytes.Buffer
+	pw, err := NewParquetWriterFromWriter(&buf, new(Entry), 1)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		assert.NoError(t, pw.Write(entry))
+	}
+	assert.NoError(t, pw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	res, err := pr.ReadJSON(len(entries))
+	assert.NoError(t, err)
+	assert.Equal(t, len(expected), len(res))
+	for i := range res {
+		//the order of the map keys isn't fixed
+		assert.JSONEq(t, expected[i], res[i])
+	}
+
+	//write the JSON back and read it as NDJSON
+	var jsonBuf bytes.Buffer
+	jw, err := NewParquetWriterFromWriter(&jsonBuf, pw.SchemaHandler, 1)
+	assert.NoError(t, err)
+	jw.MarshalFunc = marshal.MarshalJSONBase64
+	for _, record := range res {
+		assert.NoError(t, jw.Write(record))
+	}
+	assert.NoError(t, jw.WriteStop())
+
+	pf, err = buffer.NewBufferFile(jsonBuf.Bytes())
+	assert.NoError(t, err)
+	pr, err = reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	var ndjson bytes.Buffer
+	n, err := pr.ReadNDJSON(&ndjson, len(entries)+1)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entries), n)
+	lines := strings.Split(strings.TrimSuffix(ndjson.String(), "\n"), "\n")
+	assert.Equal(t, len(expected), len(lines))
+	for i := range lines {
+		assert.JSONEq(t, expected[i], lines[i])
+	}
+}
+
+func TestJSONSchemaInference(t *testing.T) {
+	records := []string{
+		`{"id": 1, "ts": "2021-03-04T05:06:07Z", "tags": ["a", "b"], "child": {"name": "x"}, "attrs": {"k": 1.5}}`,
+		`{"id": 2, "score": 0.5, "tags": null, "child": {"name": "y", "age": 3}}`,
+	}
+	item, err := schema.InferJSONSchema(records, schema.WithJSONMapPaths("$.attrs"))
+	assert.NoError(t, err)
+	jsonSchema, err := json.Marshal(item)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	jw, err := NewJSONWriterFromWriter(string(jsonSchema), &buf, 1)
+	assert.NoError(t, err)
+	jw.MarshalFunc = marshal.MarshalJSONStrict
+	for _, record := range records {
+		assert.NoError(t, jw.Write(record))
+	}
+	assert.NoError(t, jw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	res, err := pr.ReadJSON(len(records))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"ts":"2021-03-04T05:06:07Z","tags":["a","b"],"child":{"name":"x","age":null},"attrs":{"k":1.5},"score":null}`, res[0])
+	assert.JSONEq(t, `{"id":2,"ts":null,"tags":null,"child":{"name":"y","age":3},"attrs":null,"score":0.5}`, res[1])
+
+	sh, err := schema.NewSchemaHandlerFromJSONSample(records)
+	assert.NoError(t, err)
+	testData := []struct {
+		record string
+		err    string
+	}{
+		{`{"id": 1, "unknown": 2}`, "$.unknown: unknown field"},
+		{`{"id": 1.5}`, `$.id: strconv.ParseInt: parsing "1.5": invalid syntax`},
+		{`{"child": {"age": "3"}}`, "$.child.age: field age expects INT64, get string"},
+		{`{"tags": ["a", {}]}`, "$.tags[1]: field element expects BYTE_ARRAY, get object"},
+		{`{"ts": "now"}`, `$.ts: parsing time "now" as "2006-01-02T15:04:05.999999999": cannot parse "now" as "2006"`},
+		{`[]`, "$: field parquet_go_root expects object, get array"},
+	}
+	for _, data := range testData {
+		_, err := marshal.MarshalJSONStrict([]interface{}{data.record}, sh)
+		assert.EqualError(t, err, data.err)
+		//MarshalJSON ignores the unknown fields
+		if strings.Contains(data.err, "unknown") {
+			_, err = marshal.MarshalJSON([]interface{}{data.record}, sh)
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestAvroSchema(t *testing.T) {
+	avsc := `{"type":"record","name":"User","fields":[{"name":"id","type":"long"},{"name":"name","type":["null","string"],"default":null}]}`
+	item, err := schema.AvroToJSONSchema(avsc)
+	assert.NoError(t, err)
+	jsonSchema, err := json.Marshal(item)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	jw, err := NewJSONWriterFromWriter(string(jsonSchema), &buf, 1, WithAvroSchema(avsc))
+	assert.NoError(t, err)
+	assert.NoError(t, jw.Write(`{"id": 1, "name": "a"}`))
+	assert.NoError(t, jw.WriteStop())
+
+	pf, err := buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	res, err := pr.GetAvroSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, avsc, res)
+	rows, err := pr.ReadJSON(1)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"name":"a"}`, rows[0])
+
+	//without the key-value metadata, the schema is converted
+	buf.Reset()
+	jw, err = NewJSONWriterFromWriter(string(jsonSchema), &buf, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, jw.WriteStop())
+	pf, err = buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err = reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	_, ok := pr.GetKeyValueMetadata(schema.AvroSchemaKey)
+	assert.False(t, ok)
+	res, err = pr.GetAvroSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"record","name":"User","fields":[{"name":"id","type":"long"},{"name":"name","type":["null","string"],"default":null}]}`, res)
+
+	//the Avro schema of a handler from NewSchemaHandlerFromAvro is stored automatically
+	avsc = `{"type":"record","name":"User","namespace":"com.example","fields":[{"name":"id","type":"long"}]}`
+	sh, err := schema.NewSchemaHandlerFromAvro(avsc)
+	assert.NoError(t, err)
+	buf.Reset()
+	pw, err := NewParquetWriterFromWriter(&buf, sh, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, pw.WriteStop())
+	pf, err = buffer.NewBufferFile(buf.Bytes())
+	assert.NoError(t, err)
+	pr, err = reader.NewParquetReader(pf, nil, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "com.example.User", pr.SchemaHandler.GetExName(0))
+	value, ok := pr.GetKeyValueMetadata(schema.AvroSchemaKey)
+	assert.True(t, ok)
+	assert.Equal(t, avsc, value)
+}